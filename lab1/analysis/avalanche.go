@@ -0,0 +1,294 @@
+package analysis
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"lab1/interfaces"
+	"math"
+	"math/bits"
+)
+
+const DefaultSamples = 200
+
+type Target int
+
+const (
+	Plaintext Target = iota
+	Key
+)
+
+func (t Target) String() string {
+	switch t {
+	case Plaintext:
+		return "plaintext"
+	case Key:
+		return "key"
+	default:
+		return fmt.Sprintf("Target(%d)", int(t))
+	}
+}
+
+type Config struct {
+	Samples int
+	Rand    io.Reader
+}
+
+// Result holds the statistics of flipping every input bit (plaintext or key)
+// across Samples random (key, plaintext) pairs. Bits are numbered from the
+// most significant bit of byte 0, as in the permutation tables.
+type Result struct {
+	Target     Target
+	Rounds     int
+	Samples    int
+	InputBits  int
+	OutputBits int
+
+	// Flips[n] counts the trials in which exactly n ciphertext bits changed.
+	Flips []int
+	// SAC[i][j] is the probability that output bit j changes when input bit i is flipped.
+	SAC [][]float64
+	// BIC[j][k] is the largest |correlation| between changes of output bits j and k over all input bits.
+	BIC [][]float64
+}
+
+type RoundsFactory func(rounds int) (interfaces.BlockCipher, error)
+
+func Analyze(cipher interfaces.BlockCipher, keySize int, target Target, config Config) (*Result, error) {
+	if cipher == nil {
+		return nil, errors.New("cipher cannot be nil")
+	}
+	if keySize <= 0 {
+		return nil, fmt.Errorf("key size must be positive (got %d)", keySize)
+	}
+	if target != Plaintext && target != Key {
+		return nil, fmt.Errorf("unknown target: %v", target)
+	}
+
+	samples := config.Samples
+	if samples < 0 {
+		return nil, errors.New("number of samples cannot be negative")
+	}
+	if samples == 0 {
+		samples = DefaultSamples
+	}
+
+	random := config.Rand
+	if random == nil {
+		random = rand.Reader
+	}
+
+	blockSize := cipher.BlockSize()
+	outputBits := blockSize * 8
+	inputBits := outputBits
+	if target == Key {
+		inputBits = keySize * 8
+	}
+
+	result := &Result{
+		Target:     target,
+		Samples:    samples,
+		InputBits:  inputBits,
+		OutputBits: outputBits,
+		Flips:      make([]int, outputBits+1),
+		SAC:        newMatrix(inputBits, outputBits),
+		BIC:        newMatrix(outputBits, outputBits),
+	}
+
+	key := make([]byte, keySize)
+	plaintext := make([]byte, blockSize)
+	defer interfaces.Zeroize(key)
+
+	changed := make([]int, 0, outputBits)
+	counts := make([]int, outputBits)
+	pairs := make([]int, outputBits*outputBits)
+
+	for i := 0; i < inputBits; i++ {
+		clear(counts)
+		clear(pairs)
+
+		for s := 0; s < samples; s++ {
+			if _, err := io.ReadFull(random, key); err != nil {
+				return nil, fmt.Errorf("failed to generate key: %w", err)
+			}
+			if _, err := io.ReadFull(random, plaintext); err != nil {
+				return nil, fmt.Errorf("failed to generate plaintext: %w", err)
+			}
+
+			diff, err := flipDifference(cipher, key, plaintext, target, i)
+			if err != nil {
+				return nil, err
+			}
+
+			changed = setBits(diff, changed[:0])
+			result.Flips[len(changed)]++
+
+			for a, j := range changed {
+				counts[j]++
+				for _, k := range changed[a+1:] {
+					pairs[j*outputBits+k]++
+				}
+			}
+		}
+
+		for j := 0; j < outputBits; j++ {
+			result.SAC[i][j] = float64(counts[j]) / float64(samples)
+
+			for k := j + 1; k < outputBits; k++ {
+				c := math.Abs(correlation(samples, counts[j], counts[k], pairs[j*outputBits+k]))
+				if c > result.BIC[j][k] {
+					result.BIC[j][k] = c
+					result.BIC[k][j] = c
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func AnalyzeRounds(factory RoundsFactory, keySize int, rounds []int, target Target, config Config) ([]*Result, error) {
+	if factory == nil {
+		return nil, errors.New("factory cannot be nil")
+	}
+
+	results := make([]*Result, 0, len(rounds))
+	for _, r := range rounds {
+		cipher, err := factory(r)
+		if err != nil {
+			return nil, fmt.Errorf("rounds %d: %w", r, err)
+		}
+
+		result, err := Analyze(cipher, keySize, target, config)
+		if destroyer, ok := cipher.(interfaces.Destroyer); ok {
+			destroyer.Destroy()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rounds %d: %w", r, err)
+		}
+
+		result.Rounds = r
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Avalanche returns the mean fraction of ciphertext bits that change per
+// flipped input bit; an ideal cipher gives 0.5.
+func (r *Result) Avalanche() float64 {
+	mean, _ := r.flipStats()
+	return mean / float64(r.OutputBits)
+}
+
+func (r *Result) AvalancheStdDev() float64 {
+	_, variance := r.flipStats()
+	return math.Sqrt(variance) / float64(r.OutputBits)
+}
+
+// SACDeviation returns the mean and maximum of |SAC[i][j] - 0.5|.
+func (r *Result) SACDeviation() (mean, max float64) {
+	for _, row := range r.SAC {
+		for _, p := range row {
+			deviation := math.Abs(p - 0.5)
+			mean += deviation
+			if deviation > max {
+				max = deviation
+			}
+		}
+	}
+	return mean / float64(r.InputBits*r.OutputBits), max
+}
+
+func (r *Result) BICMax() float64 {
+	var max float64
+	for _, row := range r.BIC {
+		for _, c := range row {
+			if c > max {
+				max = c
+			}
+		}
+	}
+	return max
+}
+
+func (r *Result) flipStats() (mean, variance float64) {
+	trials := 0
+	for n, count := range r.Flips {
+		trials += count
+		mean += float64(n * count)
+	}
+	if trials == 0 {
+		return 0, 0
+	}
+	mean /= float64(trials)
+
+	for n, count := range r.Flips {
+		d := float64(n) - mean
+		variance += d * d * float64(count)
+	}
+	return mean, variance / float64(trials)
+}
+
+func flipDifference(cipher interfaces.BlockCipher, key, plaintext []byte, target Target, bit int) ([]byte, error) {
+	if err := cipher.SetKey(key); err != nil {
+		return nil, fmt.Errorf("failed to set key: %w", err)
+	}
+
+	original, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("encryption failed: %w", err)
+	}
+
+	mask := byte(0x80) >> (bit % 8)
+	if target == Key {
+		key[bit/8] ^= mask
+		err = cipher.SetKey(key)
+		key[bit/8] ^= mask
+		if err != nil {
+			return nil, fmt.Errorf("failed to set key: %w", err)
+		}
+	} else {
+		plaintext[bit/8] ^= mask
+		defer func() { plaintext[bit/8] ^= mask }()
+	}
+
+	modified, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("encryption failed: %w", err)
+	}
+
+	interfaces.XorBytes(original, modified)
+	return original, nil
+}
+
+func setBits(data []byte, indices []int) []int {
+	for i, b := range data {
+		for b != 0 {
+			bit := bits.LeadingZeros8(b)
+			indices = append(indices, i*8+bit)
+			b &^= 0x80 >> bit
+		}
+	}
+	return indices
+}
+
+func correlation(samples, countA, countB, countAB int) float64 {
+	n := float64(samples)
+	a, b, ab := float64(countA), float64(countB), float64(countAB)
+
+	denominator := math.Sqrt(a * (n - a) * b * (n - b))
+	if denominator == 0 {
+		return 0
+	}
+	return (n*ab - a*b) / denominator
+}
+
+func newMatrix(rows, cols int) [][]float64 {
+	matrix := make([][]float64, rows)
+	for i := range matrix {
+		matrix[i] = make([]float64, cols)
+	}
+	return matrix
+}
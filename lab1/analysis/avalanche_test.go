@@ -0,0 +1,201 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/csv"
+	"lab1/deal"
+	"lab1/des"
+	"lab1/interfaces"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+type xorCipher struct {
+	key []byte
+}
+
+func (x *xorCipher) SetKey(key []byte) error {
+	x.key = append(x.key[:0], key...)
+	return nil
+}
+
+func (x *xorCipher) Encrypt(block []byte) ([]byte, error) {
+	result := append([]byte(nil), block...)
+	interfaces.XorBytes(result, x.key)
+	return result, nil
+}
+
+func (x *xorCipher) Decrypt(block []byte) ([]byte, error) {
+	return x.Encrypt(block)
+}
+
+func (x *xorCipher) BlockSize() int {
+	return 8
+}
+
+func seeded(seed uint64) Config {
+	return Config{Samples: 64, Rand: rand.NewChaCha8([32]byte{byte(seed)})}
+}
+
+func TestAnalyzeXOR(t *testing.T) {
+	for _, target := range []Target{Plaintext, Key} {
+		result, err := Analyze(&xorCipher{}, 8, target, seeded(1))
+		if err != nil {
+			t.Fatalf("%v: Analyze: %v", target, err)
+		}
+
+		if got, want := result.Avalanche(), 1.0/64; got != want {
+			t.Errorf("%v: avalanche %v, want %v", target, got, want)
+		}
+		if got := result.AvalancheStdDev(); got != 0 {
+			t.Errorf("%v: avalanche stddev %v, want 0", target, got)
+		}
+		if result.Flips[1] != 64*64 {
+			t.Errorf("%v: Flips[1] = %d, want %d", target, result.Flips[1], 64*64)
+		}
+
+		for i := range result.SAC {
+			for j, p := range result.SAC[i] {
+				want := 0.0
+				if i == j {
+					want = 1
+				}
+				if p != want {
+					t.Fatalf("%v: SAC[%d][%d] = %v, want %v", target, i, j, p, want)
+				}
+			}
+		}
+
+		if _, max := result.SACDeviation(); max != 0.5 {
+			t.Errorf("%v: max SAC deviation %v, want 0.5", target, max)
+		}
+		if got := result.BICMax(); got != 0 {
+			t.Errorf("%v: BIC max %v, want 0", target, got)
+		}
+	}
+}
+
+func TestAnalyzeDES(t *testing.T) {
+	cipher, err := des.NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+
+	result, err := Analyze(cipher, des.DESKeySize, Plaintext, seeded(2))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if got := result.Avalanche(); math.Abs(got-0.5) > 0.01 {
+		t.Errorf("plaintext avalanche %v, want about 0.5", got)
+	}
+	if mean, _ := result.SACDeviation(); mean > 0.1 {
+		t.Errorf("mean SAC deviation %v, want below 0.1", mean)
+	}
+
+	result, err = Analyze(cipher, des.DESKeySize, Key, seeded(3))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	// The least significant bit of every key byte is a parity bit that DES ignores.
+	for i, row := range result.SAC {
+		for j, p := range row {
+			if i%8 == 7 && p != 0 {
+				t.Fatalf("parity bit %d changed output bit %d with probability %v", i, j, p)
+			}
+		}
+		if i%8 != 7 && row[0] == 0 && row[1] == 0 && row[2] == 0 {
+			t.Errorf("key bit %d has no effect", i)
+		}
+	}
+}
+
+func TestAnalyzeRounds(t *testing.T) {
+	results, err := AnalyzeRounds(func(rounds int) (interfaces.BlockCipher, error) {
+		return des.NewReducedDES(rounds)
+	}, des.DESKeySize, []int{1, 2, 4, 16}, Plaintext, seeded(4))
+	if err != nil {
+		t.Fatalf("AnalyzeRounds: %v", err)
+	}
+
+	if results[0].Avalanche() > 0.2 {
+		t.Errorf("1 round: avalanche %v, want weak diffusion", results[0].Avalanche())
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Avalanche() <= results[i-1].Avalanche()-0.02 {
+			t.Errorf("avalanche dropped from %v (%d rounds) to %v (%d rounds)",
+				results[i-1].Avalanche(), results[i-1].Rounds, results[i].Avalanche(), results[i].Rounds)
+		}
+	}
+	if _, max := results[0].SACDeviation(); max != 0.5 {
+		t.Errorf("1 round: max SAC deviation %v, want 0.5", max)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSummaryCSV(&buf, results); err != nil {
+		t.Fatalf("WriteSummaryCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("summary CSV: %v", err)
+	}
+	if len(records) != len(results)+1 || len(records[0]) != len(summaryHeader) {
+		t.Fatalf("summary CSV has %d rows of %d fields", len(records), len(records[0]))
+	}
+	if records[4][0] != "plaintext" || records[4][1] != "16" {
+		t.Errorf("last summary row %v", records[4])
+	}
+}
+
+func TestAnalyzeDEALRounds(t *testing.T) {
+	results, err := AnalyzeRounds(func(rounds int) (interfaces.BlockCipher, error) {
+		return deal.NewDEAL(rounds)
+	}, 16, []int{1, 3}, Plaintext, Config{Samples: 8, Rand: rand.NewChaCha8([32]byte{5})})
+	if err != nil {
+		t.Fatalf("AnalyzeRounds: %v", err)
+	}
+
+	// One DEAL round leaves the right half untouched.
+	if got := results[0].Avalanche(); got > 0.3 {
+		t.Errorf("1 round: avalanche %v", got)
+	}
+	if got := results[1].Avalanche(); math.Abs(got-0.5) > 0.05 {
+		t.Errorf("3 rounds: avalanche %v, want about 0.5", got)
+	}
+}
+
+func TestReports(t *testing.T) {
+	result, err := Analyze(&xorCipher{}, 8, Plaintext, seeded(6))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMatrixCSV(&buf, result.SAC); err != nil {
+		t.Fatalf("WriteMatrixCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("matrix CSV: %v", err)
+	}
+	if len(records) != 65 || len(records[0]) != 65 {
+		t.Fatalf("matrix CSV is %dx%d, want 65x65", len(records), len(records[0]))
+	}
+	if records[3][0] != "2" || records[3][2] != "0.000000" || records[3][3] != "1.000000" {
+		t.Errorf("row 2: %v", records[3][:5])
+	}
+
+	buf.Reset()
+	if err := WriteHeatMap(&buf, result.SAC, 0.5); err != nil {
+		t.Fatalf("WriteHeatMap: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 65 {
+		t.Fatalf("heat map has %d lines, want 65", len(lines))
+	}
+	if want := " 5  " + strings.Repeat("@", 64); lines[6] != want {
+		t.Errorf("heat map row 5:\n%q\nwant\n%q", lines[6], want)
+	}
+}
@@ -0,0 +1,171 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+const heatMapShades = " .:-=+*#%@"
+
+var summaryHeader = []string{
+	"target", "rounds", "samples", "input_bits", "output_bits",
+	"avalanche", "avalanche_stddev", "sac_mean_deviation", "sac_max_deviation", "bic_max",
+}
+
+var sboxHeader = []string{
+	"sbox", "input_bits", "output_bits", "bijective", "differential_uniformity",
+	"linearity", "nonlinearity", "algebraic_degree", "fixed_points", "opposite_fixed_points",
+}
+
+func WriteSummaryCSV(w io.Writer, results []*Result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(summaryHeader); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		sacMean, sacMax := r.SACDeviation()
+		record := []string{
+			r.Target.String(),
+			strconv.Itoa(r.Rounds),
+			strconv.Itoa(r.Samples),
+			strconv.Itoa(r.InputBits),
+			strconv.Itoa(r.OutputBits),
+			formatFloat(r.Avalanche()),
+			formatFloat(r.AvalancheStdDev()),
+			formatFloat(sacMean),
+			formatFloat(sacMax),
+			formatFloat(r.BICMax()),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func WriteSBoxCSV(w io.Writer, properties []SBoxProperties) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sboxHeader); err != nil {
+		return err
+	}
+
+	for _, p := range properties {
+		record := []string{
+			p.Name,
+			strconv.Itoa(p.InputBits),
+			strconv.Itoa(p.OutputBits),
+			strconv.FormatBool(p.Bijective),
+			strconv.Itoa(p.DifferentialUniformity),
+			strconv.Itoa(p.Linearity),
+			strconv.Itoa(p.Nonlinearity),
+			strconv.Itoa(p.AlgebraicDegree),
+			strconv.Itoa(p.FixedPoints),
+			strconv.Itoa(p.OppositeFixedPoints),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTableCSV writes a DDT or LAT with input differences or masks as rows.
+func WriteTableCSV(w io.Writer, table [][]int) error {
+	cw := csv.NewWriter(w)
+
+	if len(table) > 0 {
+		header := make([]string, len(table[0])+1)
+		header[0] = "input"
+		for j := range table[0] {
+			header[j+1] = strconv.Itoa(j)
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for i, row := range table {
+		record := make([]string, len(row)+1)
+		record[0] = strconv.Itoa(i)
+		for j, v := range row {
+			record[j+1] = strconv.Itoa(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMatrixCSV writes one row per matrix row, prefixed with the row index,
+// under a header of column indices.
+func WriteMatrixCSV(w io.Writer, matrix [][]float64) error {
+	cw := csv.NewWriter(w)
+
+	if len(matrix) > 0 {
+		header := make([]string, len(matrix[0])+1)
+		header[0] = "bit"
+		for j := range matrix[0] {
+			header[j+1] = strconv.Itoa(j)
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for i, row := range matrix {
+		record := make([]string, len(row)+1)
+		record[0] = strconv.Itoa(i)
+		for j, v := range row {
+			record[j+1] = formatFloat(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteHeatMap draws one character per cell, shaded by the distance from
+// ideal (0.5 for SAC, 0 for BIC): blank means ideal, '@' the worst possible.
+func WriteHeatMap(w io.Writer, matrix [][]float64, ideal float64) error {
+	scale := math.Max(ideal, 1-ideal)
+	width := len(strconv.Itoa(len(matrix)))
+
+	if _, err := fmt.Fprintf(w, "%*s  |deviation from %.2f|: '%c' 0 .. '%c' %.2f\n",
+		width, "", ideal, heatMapShades[0], heatMapShades[len(heatMapShades)-1], scale); err != nil {
+		return err
+	}
+
+	line := make([]byte, 0, 64)
+	for i, row := range matrix {
+		line = line[:0]
+		for _, v := range row {
+			level := int(math.Abs(v-ideal) / scale * float64(len(heatMapShades)-1))
+			level = min(max(level, 0), len(heatMapShades)-1)
+			line = append(line, heatMapShades[level])
+		}
+
+		if _, err := fmt.Fprintf(w, "%*d  %s\n", width, i, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
@@ -0,0 +1,230 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"lab1/des"
+	"lab1/permutations"
+	"math/bits"
+)
+
+// SBox computes the cryptographic properties of a permutations.SBox, whose
+// methods it keeps.
+type SBox struct {
+	*permutations.SBox
+	// table caches the outputs, which every property walks.
+	table []int
+}
+
+func NewSBox(box *permutations.SBox) (*SBox, error) {
+	if box == nil {
+		return nil, errors.New("NewSBox: no S-box given")
+	}
+	return &SBox{SBox: box, table: box.Table()}, nil
+}
+
+// NewByteSBox wraps an 8×8 byte table such as the Rijndael S-box.
+func NewByteSBox(table []byte) (*SBox, error) {
+	values := make([]int, len(table))
+	for i, v := range table {
+		values[i] = int(v)
+	}
+	box, err := permutations.NewSBox(8, 8, values)
+	if err != nil {
+		return nil, fmt.Errorf("NewByteSBox: %w", err)
+	}
+	return NewSBox(box)
+}
+
+// NewDESSBox returns DES S-box index (0-7) as a 6×4 box indexed by the
+// six input bits directly: the outer bits select the row, the inner four
+// the column.
+func NewDESSBox(index int) (*SBox, error) {
+	if index < 0 || index >= len(des.SBoxes) {
+		return nil, fmt.Errorf("NewDESSBox: index must be between 0 and %d (got %d)", len(des.SBoxes)-1, index)
+	}
+
+	rows := make([][]int, len(des.SBoxes[index]))
+	for row, values := range des.SBoxes[index] {
+		rows[row] = make([]int, len(values))
+		for col, v := range values {
+			rows[row][col] = int(v)
+		}
+	}
+	box, err := permutations.NewSBoxFromRows(4, rows)
+	if err != nil {
+		return nil, fmt.Errorf("NewDESSBox: %w", err)
+	}
+	return NewSBox(box)
+}
+
+func (s *SBox) inputs() int {
+	return 1 << s.InputBits()
+}
+
+func (s *SBox) outputs() int {
+	return 1 << s.OutputBits()
+}
+
+// DDT returns the difference distribution table: DDT[a][b] counts the
+// inputs x with S(x) ^ S(x^a) == b.
+func (s *SBox) DDT() [][]int {
+	ddt := newIntMatrix(s.inputs(), s.outputs())
+	for a := range ddt {
+		for x, y := range s.table {
+			ddt[a][y^s.table[x^a]]++
+		}
+	}
+	return ddt
+}
+
+// LAT returns the linear approximation table in its biased form: LAT[a][b]
+// is the number of inputs x with a·x == b·S(x), minus 2^(n-1).
+func (s *SBox) LAT() [][]int {
+	lat := newIntMatrix(s.inputs(), s.outputs())
+
+	walsh := make([]int, s.inputs())
+	for b := 0; b < s.outputs(); b++ {
+		for x, y := range s.table {
+			walsh[x] = 1 - 2*parity(b&y)
+		}
+		walshHadamard(walsh)
+		for a, w := range walsh {
+			lat[a][b] = w / 2
+		}
+	}
+	return lat
+}
+
+// DifferentialUniformity is the largest DDT entry over nonzero input differences.
+func (s *SBox) DifferentialUniformity() int {
+	return maxEntry(s.DDT()[1:], 0)
+}
+
+// Linearity is the largest |LAT| entry over nonzero output masks.
+func (s *SBox) Linearity() int {
+	return maxEntry(s.LAT(), 1)
+}
+
+// Nonlinearity is the smallest Hamming distance between a nonzero component
+// function b·S and any affine function.
+func (s *SBox) Nonlinearity() int {
+	return s.inputs()/2 - s.Linearity()
+}
+
+// AlgebraicDegree is the highest degree of the algebraic normal forms of the
+// output coordinate functions.
+func (s *SBox) AlgebraicDegree() int {
+	degree := 0
+	anf := make([]int, s.inputs())
+	for bit := 0; bit < s.OutputBits(); bit++ {
+		for x, y := range s.table {
+			anf[x] = y >> bit & 1
+		}
+		mobius(anf)
+		for monomial, coefficient := range anf {
+			if coefficient != 0 {
+				degree = max(degree, bits.OnesCount(uint(monomial)))
+			}
+		}
+	}
+	return degree
+}
+
+// FixedPoints counts the inputs with S(x) == x and, for the opposite fixed
+// points, S(x) == ^x. Both are zero unless the box is n×n.
+func (s *SBox) FixedPoints() (fixed, opposite int) {
+	if s.InputBits() != s.OutputBits() {
+		return 0, 0
+	}
+
+	mask := s.outputs() - 1
+	for x, y := range s.table {
+		switch y {
+		case x:
+			fixed++
+		case x ^ mask:
+			opposite++
+		}
+	}
+	return fixed, opposite
+}
+
+// SBoxProperties summarizes an S-box for comparison reports.
+type SBoxProperties struct {
+	Name                   string
+	InputBits              int
+	OutputBits             int
+	Bijective              bool
+	DifferentialUniformity int
+	Linearity              int
+	Nonlinearity           int
+	AlgebraicDegree        int
+	FixedPoints            int
+	OppositeFixedPoints    int
+}
+
+func (s *SBox) Properties(name string) SBoxProperties {
+	linearity := s.Linearity()
+	fixed, opposite := s.FixedPoints()
+
+	return SBoxProperties{
+		Name:                   name,
+		InputBits:              s.InputBits(),
+		OutputBits:             s.OutputBits(),
+		Bijective:              s.IsBijective(),
+		DifferentialUniformity: s.DifferentialUniformity(),
+		Linearity:              linearity,
+		Nonlinearity:           s.inputs()/2 - linearity,
+		AlgebraicDegree:        s.AlgebraicDegree(),
+		FixedPoints:            fixed,
+		OppositeFixedPoints:    opposite,
+	}
+}
+
+// maxEntry returns the largest absolute value in the matrix, skipping the
+// first skipColumns columns of every row.
+func maxEntry(matrix [][]int, skipColumns int) int {
+	result := 0
+	for _, row := range matrix {
+		for _, v := range row[skipColumns:] {
+			result = max(result, v, -v)
+		}
+	}
+	return result
+}
+
+func newIntMatrix(rows, cols int) [][]int {
+	cells := make([]int, rows*cols)
+	matrix := make([][]int, rows)
+	for i := range matrix {
+		matrix[i] = cells[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return matrix
+}
+
+func parity(x int) int {
+	return bits.OnesCount(uint(x)) & 1
+}
+
+// walshHadamard transforms f in place: f'[a] = sum over x of f[x]·(-1)^(a·x).
+func walshHadamard(f []int) {
+	for length := 1; length < len(f); length <<= 1 {
+		for start := 0; start < len(f); start += 2 * length {
+			for i := start; i < start+length; i++ {
+				f[i], f[i+length] = f[i]+f[i+length], f[i]-f[i+length]
+			}
+		}
+	}
+}
+
+// mobius turns a truth table into algebraic normal form coefficients in place.
+func mobius(f []int) {
+	for length := 1; length < len(f); length <<= 1 {
+		for start := 0; start < len(f); start += 2 * length {
+			for i := start; i < start+length; i++ {
+				f[i+length] ^= f[i]
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/csv"
+	"lab1/permutations"
+	"testing"
+)
+
+// Heys' tutorial S-box, whose DDT and LAT are tabulated in the tutorial.
+var heysSBox = []int{0xE, 0x4, 0xD, 0x1, 0x2, 0xF, 0xB, 0x8, 0x3, 0xA, 0x6, 0xC, 0x5, 0x9, 0x0, 0x7}
+
+func newSBox(t *testing.T, bits int, table []int) *SBox {
+	t.Helper()
+	box, err := permutations.NewSBox(bits, bits, table)
+	if err != nil {
+		t.Fatalf("permutations.NewSBox: %v", err)
+	}
+	s, err := NewSBox(box)
+	if err != nil {
+		t.Fatalf("NewSBox: %v", err)
+	}
+	return s
+}
+
+func TestHeysSBox(t *testing.T) {
+	s := newSBox(t, 4, heysSBox)
+
+	ddt := s.DDT()
+	if ddt[0][0] != 16 || ddt[0xB][0x2] != 8 || ddt[0x4][0x6] != 6 {
+		t.Errorf("DDT entries %d %d %d, want 16 8 6", ddt[0][0], ddt[0xB][0x2], ddt[0x4][0x6])
+	}
+	for a, row := range ddt {
+		sum := 0
+		for _, v := range row {
+			sum += v
+		}
+		if sum != 16 {
+			t.Fatalf("DDT row %d sums to %d", a, sum)
+		}
+	}
+
+	lat := s.LAT()
+	// The approximations used in the tutorial's linear attack.
+	if lat[0][0] != 8 || lat[0xB][0x4] != 4 || lat[0x4][0x5] != -4 {
+		t.Errorf("LAT entries %d %d %d, want 8 4 -4", lat[0][0], lat[0xB][0x4], lat[0x4][0x5])
+	}
+
+	want := SBoxProperties{
+		Name: "heys", InputBits: 4, OutputBits: 4, Bijective: true,
+		DifferentialUniformity: 8, Linearity: 6, Nonlinearity: 2, AlgebraicDegree: 3,
+		OppositeFixedPoints: 2,
+	}
+	if got := s.Properties("heys"); got != want {
+		t.Errorf("properties\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDESSBoxes(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		s, err := NewDESSBox(i)
+		if err != nil {
+			t.Fatalf("NewDESSBox(%d): %v", i, err)
+		}
+		p := s.Properties("")
+
+		if p.DifferentialUniformity != 16 {
+			t.Errorf("S%d: differential uniformity %d, want 16", i+1, p.DifferentialUniformity)
+		}
+		if p.Bijective || p.AlgebraicDegree != 5 {
+			t.Errorf("S%d: bijective %v, degree %d", i+1, p.Bijective, p.AlgebraicDegree)
+		}
+
+		// Flipping a single input bit always changes at least two output bits.
+		ddt := s.DDT()
+		for a := 1; a < 64; a <<= 1 {
+			for _, b := range []int{0, 1, 2, 4, 8} {
+				if ddt[a][b] != 0 {
+					t.Errorf("S%d: DDT[%#x][%#x] = %d", i+1, a, b, ddt[a][b])
+				}
+			}
+		}
+	}
+
+	// Matsui's best approximation: NS5(16, 15) = 12.
+	s5, _ := NewDESSBox(4)
+	if got := s5.LAT()[16][15]; got != 12-32 {
+		t.Errorf("S5 LAT[16][15] = %d, want -20", got)
+	}
+	if got := s5.Nonlinearity(); got != 12 {
+		t.Errorf("S5 nonlinearity %d, want 12", got)
+	}
+
+	if _, err := NewDESSBox(8); err == nil {
+		t.Error("NewDESSBox(8) succeeded")
+	}
+}
+
+func TestSBoxFixedPoints(t *testing.T) {
+	identity := make([]int, 16)
+	for i := range identity {
+		identity[i] = i
+	}
+	identity[3], identity[12] = 12, 3
+
+	s := newSBox(t, 4, identity)
+	if fixed, opposite := s.FixedPoints(); fixed != 14 || opposite != 2 {
+		t.Errorf("fixed points %d, opposite %d, want 14 and 2", fixed, opposite)
+	}
+	// The two degree-4 point indicators introduced by the swap cancel.
+	if got := s.AlgebraicDegree(); got != 3 {
+		t.Errorf("degree %d, want 3", got)
+	}
+	if got := s.DifferentialUniformity(); got != 16 {
+		t.Errorf("differential uniformity %d, want 16", got)
+	}
+}
+
+func TestNewSBoxErrors(t *testing.T) {
+	if _, err := NewSBox(nil); err == nil {
+		t.Error("NewSBox(nil) succeeded")
+	}
+	if _, err := NewByteSBox(make([]byte, 255)); err == nil {
+		t.Error("NewByteSBox accepted 255 entries")
+	}
+
+	if s := newSBox(t, 2, []int{0, 0, 1, 1}); s.Properties("").Bijective {
+		t.Error("non-injective box reported bijective")
+	}
+}
+
+func TestSBoxReports(t *testing.T) {
+	s := newSBox(t, 4, heysSBox)
+
+	var buf bytes.Buffer
+	if err := WriteSBoxCSV(&buf, []SBoxProperties{s.Properties("heys")}); err != nil {
+		t.Fatalf("WriteSBoxCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("S-box CSV: %v", err)
+	}
+	if len(records) != 2 || records[1][0] != "heys" || records[1][4] != "8" || records[1][6] != "2" {
+		t.Errorf("S-box CSV %v", records)
+	}
+
+	buf.Reset()
+	if err := WriteTableCSV(&buf, s.DDT()); err != nil {
+		t.Fatalf("WriteTableCSV: %v", err)
+	}
+	records, err = csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("table CSV: %v", err)
+	}
+	if len(records) != 17 || len(records[0]) != 17 || records[12][3] != "8" {
+		t.Errorf("DDT CSV is %dx%d, row 11 %v", len(records), len(records[0]), records[12])
+	}
+}
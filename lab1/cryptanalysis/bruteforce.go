@@ -0,0 +1,222 @@
+package cryptanalysis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"lab1/des"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultProgressInterval = time.Second
+
+	maxBruteForceKeyBits = 56
+)
+
+// BruteForceConfig describes the searched DES keys: Known holds the known
+// bits and Unknown selects the bits to enumerate. Parity bits are never
+// enumerated; every candidate gets odd parity.
+type BruteForceConfig struct {
+	Known   uint64
+	Unknown uint64
+	Workers int
+	// Complement uses the complementation property E(~K, ~P) = ~E(K, P):
+	// with a known pair for P and one for ~P, every encryption tests both K
+	// and ~K, so the keys with the known bits inverted are searched too at
+	// no extra cost. When every key bit is unknown, the top unknown bit is
+	// fixed to zero, since each key or its complement has it clear; Total
+	// then halves and searching all 2^56 keys takes 2^55 encryptions.
+	Complement bool
+	// Progress, when set, receives the number of candidates tried and the
+	// total every ProgressInterval and once more when the search ends.
+	Progress         func(searched, total uint64)
+	ProgressInterval time.Duration
+}
+
+type BruteForceResult struct {
+	Key []byte
+	// Searched is the number of candidates encrypted before the search stopped.
+	Searched uint64
+	Total    uint64
+	// Complemented reports that Key is the complement of a searched candidate.
+	Complemented bool
+	Elapsed      time.Duration
+}
+
+// BruteForce searches the DES key that maps every plaintext to its
+// ciphertext. Candidates are split across workers, which stop as soon as
+// one finds the key or ctx is done.
+func BruteForce(ctx context.Context, pairs []KnownPair, config BruteForceConfig) (*BruteForceResult, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("BruteForce: no known pairs")
+	}
+	if err := checkUnknownBits(config.Unknown, maxBruteForceKeyBits); err != nil {
+		return nil, fmt.Errorf("BruteForce: %w", err)
+	}
+
+	workers := config.Workers
+	if workers < 0 {
+		return nil, errors.New("BruteForce: number of workers cannot be negative")
+	}
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	interval := config.ProgressInterval
+	if interval < 0 {
+		return nil, errors.New("BruteForce: progress interval cannot be negative")
+	}
+	if interval == 0 {
+		interval = DefaultProgressInterval
+	}
+
+	// Test the first pair, or a pair with a complementary partner, first.
+	primary, complement := 0, -1
+	if config.Complement {
+		primary, complement = complementaryPairs(pairs)
+		if complement < 0 {
+			return nil, errors.New("BruteForce: the complementation property needs known pairs for P and ~P")
+		}
+	}
+
+	known, unknown := config.Known&^config.Unknown, config.Unknown
+	if config.Complement && unknown == ^uint64(des.ParityBits) {
+		unknown &^= 1 << (63 - bits.LeadingZeros64(unknown))
+	}
+	total := uint64(1) << bits.OnesCount64(unknown)
+	result := &BruteForceResult{Total: total}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var searched atomic.Uint64
+	var found atomic.Pointer[[]byte]
+	var complemented atomic.Bool
+
+	stopProgress := startProgress(config.Progress, interval, &searched, total)
+	start := time.Now()
+
+	bitsliced, err := des.NewBitslicedDES()
+	if err != nil {
+		return nil, fmt.Errorf("BruteForce: %w", err)
+	}
+
+	// Candidates go through bitsliced DES 64 at a time; only matches are
+	// verified with the table-driven cipher.
+	err = parallelBatches(ctx, workers, 0, total, func(cipher *des.DES, first, last uint64) error {
+		var keys, ciphertexts [des.BitslicedLanes]uint64
+		for group := first; group < last; group += des.BitslicedLanes {
+			n := min(last-group, des.BitslicedLanes)
+			for i := range keys {
+				keys[i] = des.CandidateKey(known, unknown, group+min(uint64(i), n-1))
+			}
+			bitsliced.EncryptKeys(pairs[primary].Plaintext, &keys, &ciphertexts)
+			searched.Add(n)
+
+			for i, key := range keys[:n] {
+				var match uint64
+				switch {
+				case ciphertexts[i] == pairs[primary].Ciphertext:
+					match = key
+				case complement >= 0 && ^ciphertexts[i] == pairs[complement].Ciphertext:
+					match = ^key
+				default:
+					continue
+				}
+
+				ok, err := verifyKey(cipher, match, pairs)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+
+				keyBytes := binary.BigEndian.AppendUint64(nil, match)
+				if found.CompareAndSwap(nil, &keyBytes) {
+					complemented.Store(match != key)
+				}
+				cancel()
+				return nil
+			}
+		}
+		return nil
+	})
+
+	result.Elapsed = time.Since(start)
+	result.Searched = searched.Load()
+	stopProgress()
+
+	if key := found.Load(); key != nil {
+		result.Key = *key
+		result.Complemented = complemented.Load()
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("BruteForce: %w", err)
+	}
+	return result, fmt.Errorf("BruteForce: %w", ErrKeyNotFound)
+}
+
+// complementaryPairs returns the indices of two pairs with complementary
+// plaintexts, or -1 for the second when there are none.
+func complementaryPairs(pairs []KnownPair) (int, int) {
+	index := make(map[uint64]int, len(pairs))
+	for i, pair := range pairs {
+		if j, ok := index[^pair.Plaintext]; ok {
+			return j, i
+		}
+		index[pair.Plaintext] = i
+	}
+	return 0, -1
+}
+
+func verifyKey(cipher *des.DES, key uint64, pairs []KnownPair) (bool, error) {
+	for _, pair := range pairs {
+		ciphertext, err := desEncrypt(cipher, key, pair.Plaintext)
+		if err != nil {
+			return false, err
+		}
+		if ciphertext != pair.Ciphertext {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// startProgress reports the search progress from its own goroutine until
+// the returned function is called, which sends the final report.
+func startProgress(progress func(searched, total uint64), interval time.Duration, searched *atomic.Uint64, total uint64) func() {
+	if progress == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				progress(searched.Load(), total)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+		progress(searched.Load(), total)
+	}
+}
@@ -0,0 +1,153 @@
+package cryptanalysis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBruteForce(t *testing.T) {
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	oracle, _ := NewDESOracle(16, key)
+	pairs, err := GenerateKnownPairs(oracle, 2, rand.NewChaCha8([32]byte{6}))
+	if err != nil {
+		t.Fatalf("GenerateKnownPairs: %v", err)
+	}
+
+	var mu sync.Mutex
+	var reports []uint64
+	config := BruteForceConfig{
+		Known:   0x13345779_9BBC0000,
+		Unknown: 0xFEFE,
+		Workers: 4,
+		Progress: func(searched, total uint64) {
+			mu.Lock()
+			defer mu.Unlock()
+			reports = append(reports, searched)
+		},
+		ProgressInterval: time.Millisecond,
+	}
+
+	result, err := BruteForce(context.Background(), pairs, config)
+	if err != nil {
+		t.Fatalf("BruteForce: %v", err)
+	}
+	if !bytes.Equal(result.Key, key) || result.Complemented {
+		t.Errorf("key %x (complemented %v), want %x", result.Key, result.Complemented, key)
+	}
+	if result.Total != 1<<14 || result.Searched == 0 || result.Searched > result.Total {
+		t.Errorf("searched %d of %d", result.Searched, result.Total)
+	}
+	if len(reports) == 0 || reports[len(reports)-1] != result.Searched {
+		t.Errorf("progress reports %v, want the last to be %d", reports, result.Searched)
+	}
+
+	config.Known ^= 0x00000000_00010000
+	config.Unknown = 0x0EFE
+	config.Progress = nil
+	result, err = BruteForce(context.Background(), pairs, config)
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("wrong known bits: got %v, want ErrKeyNotFound", err)
+	}
+	if result.Searched != result.Total {
+		t.Errorf("searched %d of %d without finding the key", result.Searched, result.Total)
+	}
+}
+
+func TestBruteForceComplement(t *testing.T) {
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	oracle, _ := NewDESOracle(16, key)
+
+	var pairs []KnownPair
+	for _, plaintext := range []uint64{0x01234567_89ABCDEF, ^uint64(0x01234567_89ABCDEF)} {
+		ciphertext, err := oracle.query(plaintext)
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		pairs = append(pairs, KnownPair{plaintext, ciphertext})
+	}
+
+	// The known bits describe the complement of the key.
+	config := BruteForceConfig{Known: ^uint64(0x13345779_9BBCDFF1), Unknown: 0x0EFE, Workers: 2, Complement: true}
+	result, err := BruteForce(context.Background(), pairs, config)
+	if err != nil {
+		t.Fatalf("BruteForce: %v", err)
+	}
+	if !bytes.Equal(result.Key, key) || !result.Complemented {
+		t.Errorf("key %x (complemented %v), want complemented %x", result.Key, result.Complemented, key)
+	}
+
+	if _, err := BruteForce(context.Background(), pairs[:1], config); err == nil {
+		t.Error("complement search without a complementary pair succeeded")
+	}
+}
+
+func TestBruteForceComplementHalves(t *testing.T) {
+	// The top key bit is set, so only the complement, a low candidate once
+	// that bit is fixed to zero, is reached.
+	key := []byte{0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xE3, 0xFE}
+	oracle, _ := NewDESOracle(16, key)
+
+	var pairs []KnownPair
+	for _, plaintext := range []uint64{0x01234567_89ABCDEF, ^uint64(0x01234567_89ABCDEF)} {
+		ciphertext, err := oracle.query(plaintext)
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		pairs = append(pairs, KnownPair{plaintext, ciphertext})
+	}
+
+	config := BruteForceConfig{Unknown: 0xFEFEFEFE_FEFEFEFE, Workers: 1, Complement: true}
+	result, err := BruteForce(context.Background(), pairs, config)
+	if err != nil {
+		t.Fatalf("BruteForce: %v", err)
+	}
+	if !bytes.Equal(result.Key, key) || !result.Complemented {
+		t.Errorf("key %x (complemented %v), want complemented %x", result.Key, result.Complemented, key)
+	}
+	if result.Total != 1<<55 || result.Searched > 2*searchBatch {
+		t.Errorf("searched %d of %d", result.Searched, result.Total)
+	}
+
+	// Without the property the same mask needs every key.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	config.Complement = false
+	result, err = BruteForce(ctx, pairs, config)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled search: got %v", err)
+	}
+	if result.Total != 1<<56 {
+		t.Errorf("total %d without the complementation property, want %d", result.Total, uint64(1)<<56)
+	}
+
+	// A partial mask is never closed under complementation.
+	config = BruteForceConfig{Unknown: 0x0EFE, Workers: 1, Complement: true}
+	if result, _ := BruteForce(ctx, pairs, config); result.Total != 1<<10 {
+		t.Errorf("total %d for a partial mask, want %d", result.Total, 1<<10)
+	}
+}
+
+func TestBruteForceErrors(t *testing.T) {
+	pairs := []KnownPair{{1, 2}}
+
+	if _, err := BruteForce(context.Background(), nil, BruteForceConfig{}); err == nil {
+		t.Error("no pairs accepted")
+	}
+	if _, err := BruteForce(context.Background(), pairs, BruteForceConfig{Unknown: 0x0100}); err == nil {
+		t.Error("unknown parity bit accepted")
+	}
+	if _, err := BruteForce(context.Background(), pairs, BruteForceConfig{Workers: -1}); err == nil {
+		t.Error("negative workers accepted")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := BruteForce(ctx, pairs, BruteForceConfig{Unknown: 0xFEFE}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,208 @@
+package cryptanalysis
+
+import (
+	"encoding/binary"
+	"fmt"
+	"lab1/des"
+)
+
+// The attacks work on DES values packed into integers, most significant bit
+// first, so that bit n of the tables in lab1/des is bit n from the top.
+
+const desSBoxCount = 8
+
+var (
+	inverseIP = invertTable(des.IPTable)
+	inverseP  = invertTable(des.PermutationTable)
+)
+
+// Oracle encrypts chosen plaintexts under a key unknown to the attacker.
+type Oracle func(plaintext []byte) ([]byte, error)
+
+// NewDESOracle returns an oracle for DES reduced to the given number of rounds.
+func NewDESOracle(rounds int, key []byte) (Oracle, error) {
+	cipher, err := des.NewReducedDES(rounds)
+	if err != nil {
+		return nil, err
+	}
+	if err := cipher.SetKey(key); err != nil {
+		return nil, err
+	}
+	return cipher.Encrypt, nil
+}
+
+func (o Oracle) query(plaintext uint64) (uint64, error) {
+	var block [8]byte
+	binary.BigEndian.PutUint64(block[:], plaintext)
+
+	ciphertext, err := o(block[:])
+	if err != nil {
+		return 0, err
+	}
+	if len(ciphertext) != des.DESBlockSize {
+		return 0, fmt.Errorf("oracle returned %d bytes, want %d", len(ciphertext), des.DESBlockSize)
+	}
+	return binary.BigEndian.Uint64(ciphertext), nil
+}
+
+// permute applies a 1-based permutation table to the low width bits of value.
+func permute(value uint64, width int, table []int) uint64 {
+	var result uint64
+	for _, position := range table {
+		result = result<<1 | value>>(width-position)&1
+	}
+	return result
+}
+
+func invertTable(table []int) []int {
+	inverse := make([]int, len(table))
+	for i, position := range table {
+		inverse[position-1] = i + 1
+	}
+	return inverse
+}
+
+// unpermuteBlock undoes the initial permutation: for plaintexts it returns
+// (L0, R0); for ciphertexts the pre-output block (Rn, Ln).
+func unpermuteBlock(block uint64) (uint32, uint32) {
+	permuted := permute(block, 64, des.IPTable)
+	return uint32(permuted >> 32), uint32(permuted)
+}
+
+// fromRoundDifference maps a difference in (L0, R0) to the plaintext difference.
+func fromRoundDifference(difference uint64) uint64 {
+	return permute(difference, 64, inverseIP)
+}
+
+func expand(half uint32) uint64 {
+	return permute(uint64(half), 32, des.ExpansionTable)
+}
+
+// sboxInput returns the six bits of a 48-bit value that feed S-box index.
+func sboxInput(value uint64, index int) int {
+	return int(value>>(42-6*index)) & 0x3F
+}
+
+func sboxLookup(index, input int) int {
+	row := (input&0x20)>>4 | input&0x01
+	col := (input & 0x1E) >> 1
+	return int(des.SBoxes[index][row][col])
+}
+
+// sboxOutputs undoes P, so that S-box index owns bits 28-4*index..31-4*index.
+func sboxOutputs(f uint32) uint32 {
+	return uint32(permute(uint64(f), 32, inverseP))
+}
+
+func sboxOutput(outputs uint32, index int) int {
+	return int(outputs>>(28-4*index)) & 0xF
+}
+
+// sboxDDT[i][a][b] counts the S-box i inputs x with S(x)^S(x^a) == b.
+var sboxDDT = func() (ddt [desSBoxCount][64][16]int) {
+	for i := range ddt {
+		for a := 0; a < 64; a++ {
+			for x := 0; x < 64; x++ {
+				ddt[i][a][sboxLookup(i, x)^sboxLookup(i, x^a)]++
+			}
+		}
+	}
+	return ddt
+}()
+
+// roundKeyToKey maps the bits of round key number round (1-based) to the
+// bit positions of the 64-bit key they were selected from.
+func roundKeyToKey(round int) []int {
+	shift := 0
+	for _, s := range des.RotationSchedule[:round] {
+		shift += s
+	}
+
+	positions := make([]int, len(des.PC2Table))
+	for i, p := range des.PC2Table {
+		// p is a 1-based position in the rotated C||D register.
+		cd := p - 1
+		if cd < 28 {
+			cd = (cd + shift) % 28
+		} else {
+			cd = 28 + (cd-28+shift)%28
+		}
+		positions[i] = des.PC1Table[cd]
+	}
+	return positions
+}
+
+// keySearch enumerates the keys that agree with the known key bits and
+// returns the first one that maps every plaintext to its ciphertext under
+// DES reduced to rounds.
+type keySearch struct {
+	rounds      int
+	known       uint64
+	unknownBits []int
+	plaintexts  []uint64
+	ciphertexts []uint64
+}
+
+func newKeySearch(rounds int, known map[int]uint64) *keySearch {
+	ks := &keySearch{rounds: rounds}
+
+	determined := make(map[int]bool)
+	for position, bit := range known {
+		determined[position] = true
+		ks.known |= bit << (64 - position)
+	}
+
+	for _, position := range des.PC1Table {
+		if !determined[position] {
+			ks.unknownBits = append(ks.unknownBits, position)
+		}
+	}
+	return ks
+}
+
+func (ks *keySearch) addPair(plaintext, ciphertext uint64) {
+	ks.plaintexts = append(ks.plaintexts, plaintext)
+	ks.ciphertexts = append(ks.ciphertexts, ciphertext)
+}
+
+func (ks *keySearch) run() ([]byte, int, error) {
+	cipher, err := des.NewReducedDES(ks.rounds)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cipher.Destroy()
+
+	key := make([]byte, des.DESKeySize)
+	block := make([]byte, des.DESBlockSize)
+
+	candidates := 1 << len(ks.unknownBits)
+	for candidate := 0; candidate < candidates; candidate++ {
+		value := ks.known
+		for i, position := range ks.unknownBits {
+			value |= uint64(candidate>>i&1) << (64 - position)
+		}
+		binary.BigEndian.PutUint64(key, des.SetParity(value))
+
+		if err := cipher.SetKey(key); err != nil {
+			return nil, 0, err
+		}
+
+		matched := true
+		for i, plaintext := range ks.plaintexts {
+			binary.BigEndian.PutUint64(block, plaintext)
+			ciphertext, err := cipher.Encrypt(block)
+			if err != nil {
+				return nil, 0, err
+			}
+			if binary.BigEndian.Uint64(ciphertext) != ks.ciphertexts[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return key, candidate + 1, nil
+		}
+	}
+
+	return nil, candidates, ErrKeyNotFound
+}
@@ -0,0 +1,321 @@
+package cryptanalysis
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+const DefaultDifferentialPairs = 256
+
+var ErrKeyNotFound = errors.New("key not found")
+
+// Characteristic is a DES differential characteristic: the difference in
+// (L0, R0) after the initial permutation and the F output difference it
+// predicts in each round it covers.
+type Characteristic struct {
+	Name     string
+	Input    uint64
+	FOutputs []uint32
+}
+
+var (
+	// OneRoundCharacteristic holds with probability 1: the difference in L0
+	// passes round 1 untouched and enters round 2 through S1 only.
+	OneRoundCharacteristic = Characteristic{
+		Name:     "1-round",
+		Input:    0x20000000_00000000,
+		FOutputs: []uint32{0},
+	}
+
+	// The two 3-round characteristics of Biham and Shamir's attack on
+	// 6-round DES, each with probability 1/16.
+	ThreeRoundCharacteristicA = Characteristic{
+		Name:     "3-round A",
+		Input:    0x40080000_04000000,
+		FOutputs: []uint32{0x40080000, 0, 0x40080000},
+	}
+	ThreeRoundCharacteristicB = Characteristic{
+		Name:     "3-round B",
+		Input:    0x00200008_00000400,
+		FOutputs: []uint32{0x00200008, 0, 0x00200008},
+	}
+)
+
+func (c Characteristic) Rounds() int {
+	return len(c.FOutputs)
+}
+
+// Output returns the predicted difference in (L, R) after the last round
+// of the characteristic.
+func (c Characteristic) Output() uint64 {
+	left, right := uint32(c.Input>>32), uint32(c.Input)
+	for _, f := range c.FOutputs {
+		left, right = right, left^f
+	}
+	return uint64(left)<<32 | uint64(right)
+}
+
+// Probability multiplies the S-box DDT probabilities of every round.
+func (c Characteristic) Probability() float64 {
+	p := 1.0
+	left, right := uint32(c.Input>>32), uint32(c.Input)
+	for _, f := range c.FOutputs {
+		inputs, outputs := expand(right), sboxOutputs(f)
+		for i := 0; i < desSBoxCount; i++ {
+			p *= float64(sboxDDT[i][sboxInput(inputs, i)][sboxOutput(outputs, i)]) / 64
+		}
+		left, right = right, left^f
+	}
+	return p
+}
+
+// attackedSBoxes returns the S-boxes of the round after the characteristic
+// that receive no input difference. Their output difference is zero, which
+// makes the matching bits of the last-round F output difference known.
+func (c Characteristic) attackedSBoxes() []int {
+	inputs := expand(uint32(c.Output()))
+	var sboxes []int
+	for i := 0; i < desSBoxCount; i++ {
+		if sboxInput(inputs, i) == 0 {
+			sboxes = append(sboxes, i)
+		}
+	}
+	return sboxes
+}
+
+type DifferentialConfig struct {
+	// Rounds selects the attacked cipher: 4 or 6 rounds. It is ignored when
+	// Characteristics is set, which attacks len(FOutputs)+3 rounds.
+	Rounds          int
+	Characteristics []Characteristic
+	// Pairs is the number of chosen-plaintext pairs per characteristic.
+	Pairs int
+	Rand  io.Reader
+}
+
+// SubkeyCount is the winning 6-bit subkey of one S-box (0-based) with the
+// number of pairs that suggested it and the best count of any other subkey.
+type SubkeyCount struct {
+	SBox     int
+	Subkey   int
+	Count    int
+	RunnerUp int
+}
+
+type CharacteristicReport struct {
+	Characteristic Characteristic
+	Probability    float64
+	Pairs          int
+	// Filtered counts the pairs consistent with the S-box difference tables.
+	Filtered           int
+	ExpectedRightPairs float64
+	Subkeys            []SubkeyCount
+}
+
+type DifferentialResult struct {
+	Rounds          int
+	Characteristics []CharacteristicReport
+	// RoundKey is the last round key with the bits of RoundKeyMask recovered.
+	RoundKey     uint64
+	RoundKeyMask uint64
+	Queries      int
+	// Searched counts the keys tried when completing the recovered round key.
+	Searched int
+	Key      []byte
+}
+
+// DifferentialAttack recovers the key of reduced-round DES from chosen
+// plaintext pairs, following Biham and Shamir. Each characteristic of
+// r rounds gives the last round key bits of the S-boxes it leaves inactive
+// in round r+1; the remaining key bits are found by exhaustive search.
+func DifferentialAttack(oracle Oracle, config DifferentialConfig) (*DifferentialResult, error) {
+	if oracle == nil {
+		return nil, errors.New("DifferentialAttack: oracle cannot be nil")
+	}
+
+	characteristics := config.Characteristics
+	if len(characteristics) == 0 {
+		switch config.Rounds {
+		case 4:
+			characteristics = []Characteristic{OneRoundCharacteristic}
+		case 6:
+			characteristics = []Characteristic{ThreeRoundCharacteristicA, ThreeRoundCharacteristicB}
+		default:
+			return nil, fmt.Errorf("DifferentialAttack: no built-in characteristics for %d rounds (want 4 or 6)", config.Rounds)
+		}
+	}
+
+	rounds := characteristics[0].Rounds() + 3
+	for _, c := range characteristics {
+		if c.Rounds()+3 != rounds {
+			return nil, errors.New("DifferentialAttack: characteristics must cover the same number of rounds")
+		}
+	}
+
+	pairs := config.Pairs
+	if pairs < 0 {
+		return nil, errors.New("DifferentialAttack: number of pairs cannot be negative")
+	}
+	if pairs == 0 {
+		pairs = DefaultDifferentialPairs
+	}
+
+	random := config.Rand
+	if random == nil {
+		random = rand.Reader
+	}
+
+	result := &DifferentialResult{Rounds: rounds}
+	subkeys := make(map[int]int)
+	var plaintexts, ciphertexts []uint64
+
+	for _, c := range characteristics {
+		report := CharacteristicReport{
+			Characteristic:     c,
+			Probability:        c.Probability(),
+			Pairs:              pairs,
+			ExpectedRightPairs: c.Probability() * float64(pairs),
+		}
+
+		sboxes := c.attackedSBoxes()
+		if len(sboxes) == 0 {
+			return nil, fmt.Errorf("DifferentialAttack: characteristic %q leaves no S-box inactive", c.Name)
+		}
+
+		counts := make([][64]int, len(sboxes))
+		difference := fromRoundDifference(c.Input)
+		var buf [8]byte
+
+		for i := 0; i < pairs; i++ {
+			if _, err := io.ReadFull(random, buf[:]); err != nil {
+				return nil, fmt.Errorf("DifferentialAttack: %w", err)
+			}
+			p1 := binary.BigEndian.Uint64(buf[:])
+			p2 := p1 ^ difference
+
+			c1, err := oracle.query(p1)
+			if err != nil {
+				return nil, fmt.Errorf("DifferentialAttack: %w", err)
+			}
+			c2, err := oracle.query(p2)
+			if err != nil {
+				return nil, fmt.Errorf("DifferentialAttack: %w", err)
+			}
+			result.Queries += 2
+
+			if len(plaintexts) < 2 {
+				plaintexts = append(plaintexts, p1)
+				ciphertexts = append(ciphertexts, c1)
+			}
+
+			candidates, ok := pairCandidates(c, sboxes, c1, c2)
+			if !ok {
+				continue
+			}
+			report.Filtered++
+
+			for j, keys := range candidates {
+				for _, k := range keys {
+					counts[j][k]++
+				}
+			}
+		}
+
+		for j, sbox := range sboxes {
+			best := bestSubkey(sbox, counts[j])
+			report.Subkeys = append(report.Subkeys, best)
+
+			if previous, ok := subkeys[sbox]; ok && previous != best.Subkey {
+				result.Characteristics = append(result.Characteristics, report)
+				return result, fmt.Errorf("DifferentialAttack: characteristics disagree on the S%d subkey", sbox+1)
+			}
+			subkeys[sbox] = best.Subkey
+		}
+		result.Characteristics = append(result.Characteristics, report)
+	}
+
+	for sbox, subkey := range subkeys {
+		shift := 42 - 6*sbox
+		result.RoundKey |= uint64(subkey) << shift
+		result.RoundKeyMask |= 0x3F << shift
+	}
+
+	search := newKeySearch(rounds, knownKeyBits(rounds, result.RoundKey, result.RoundKeyMask))
+	for i := range plaintexts {
+		search.addPair(plaintexts[i], ciphertexts[i])
+	}
+
+	key, searched, err := search.run()
+	result.Searched = searched
+	if err != nil {
+		return result, fmt.Errorf("DifferentialAttack: %w", err)
+	}
+	result.Key = key
+	return result, nil
+}
+
+// pairCandidates returns, for every attacked S-box, the 6-bit subkeys of the
+// last round consistent with the ciphertext pair, or false when some S-box
+// admits none and the pair cannot be a right pair. Counting the suggestions
+// of each S-box separately works because wrong pairs spread their votes
+// over all 64 subkeys while every right pair votes for the real one.
+func pairCandidates(c Characteristic, sboxes []int, c1, c2 uint64) ([][]int, bool) {
+	r1, l1 := unpermuteBlock(c1)
+	r2, l2 := unpermuteBlock(c2)
+
+	// In the last round Rn' = R(n-2)' ^ Fn', and on the outputs of the
+	// attacked S-boxes R(n-2)' equals the characteristic's left difference.
+	leftDifference := uint32(c.Output() >> 32)
+	outputs := sboxOutputs(r1 ^ r2 ^ leftDifference)
+	e1, e2 := expand(l1), expand(l2)
+
+	candidates := make([][]int, len(sboxes))
+	for i, sbox := range sboxes {
+		in1, in2 := sboxInput(e1, sbox), sboxInput(e2, sbox)
+		want := sboxOutput(outputs, sbox)
+
+		if sboxDDT[sbox][in1^in2][want] == 0 {
+			return nil, false
+		}
+		for k := 0; k < 64; k++ {
+			if sboxLookup(sbox, in1^k)^sboxLookup(sbox, in2^k) == want {
+				candidates[i] = append(candidates[i], k)
+			}
+		}
+	}
+	return candidates, true
+}
+
+func bestSubkey(sbox int, counts [64]int) SubkeyCount {
+	best := SubkeyCount{SBox: sbox}
+	for k, count := range counts {
+		if count > best.Count {
+			best.RunnerUp = best.Count
+			best.Subkey, best.Count = k, count
+		} else {
+			best.RunnerUp = max(best.RunnerUp, count)
+		}
+	}
+	return best
+}
+
+// knownKeyBits maps the masked bits of the given round key to key bit positions.
+func knownKeyBits(round int, roundKey, mask uint64) map[int]uint64 {
+	known := make(map[int]uint64)
+	for i, position := range roundKeyToKey(round) {
+		shift := 47 - i
+		if mask>>shift&1 == 1 {
+			known[position] = roundKey >> shift & 1
+		}
+	}
+	return known
+}
+
+// SubkeyBits returns the number of last round key bits recovered.
+func (r *DifferentialResult) SubkeyBits() int {
+	return bits.OnesCount64(r.RoundKeyMask)
+}
@@ -0,0 +1,153 @@
+package cryptanalysis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"lab1/des"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestCharacteristics(t *testing.T) {
+	cases := []struct {
+		c      Characteristic
+		p      float64
+		output uint64
+		sboxes []int
+	}{
+		{OneRoundCharacteristic, 1, 0x00000000_20000000, []int{1, 2, 3, 4, 5, 6, 7}},
+		{ThreeRoundCharacteristicA, 1.0 / 16, 0x04000000_40080000, []int{1, 4, 5, 6, 7}},
+		{ThreeRoundCharacteristicB, 1.0 / 16, 0x00000400_00200008, []int{0, 1, 3, 4, 5}},
+	}
+
+	for _, c := range cases {
+		if got := c.c.Probability(); got != c.p {
+			t.Errorf("%s: probability %v, want %v", c.c.Name, got, c.p)
+		}
+		if got := c.c.Output(); got != c.output {
+			t.Errorf("%s: output %016x, want %016x", c.c.Name, got, c.output)
+		}
+		if got := c.c.attackedSBoxes(); !equalInts(got, c.sboxes) {
+			t.Errorf("%s: attacked S-boxes %v, want %v", c.c.Name, got, c.sboxes)
+		}
+	}
+}
+
+func TestDESHelpers(t *testing.T) {
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	roundKeys, err := des.NewDESKeySchedule().ExpandKey(key)
+	if err != nil {
+		t.Fatalf("ExpandKey: %v", err)
+	}
+
+	keyBits := binary.BigEndian.Uint64(key)
+	for round := 1; round <= des.DESRounds; round++ {
+		var want uint64
+		for _, b := range roundKeys[round-1] {
+			want = want<<8 | uint64(b)
+		}
+
+		var got uint64
+		for _, position := range roundKeyToKey(round) {
+			got = got<<1 | keyBits>>(64-position)&1
+		}
+		if got != want {
+			t.Fatalf("round %d key %012x, want %012x", round, got, want)
+		}
+	}
+
+	plaintext := uint64(0x0123456789ABCDEF)
+	left, right := unpermuteBlock(plaintext)
+	if back := fromRoundDifference(uint64(left)<<32 | uint64(right)); back != plaintext {
+		t.Errorf("IP round trip %016x, want %016x", back, plaintext)
+	}
+}
+
+func TestDifferentialAttack(t *testing.T) {
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+
+	cases := []struct {
+		rounds, pairs int
+	}{
+		{4, 16},
+		{6, 256},
+	}
+	for _, c := range cases {
+		oracle, err := NewDESOracle(c.rounds, key)
+		if err != nil {
+			t.Fatalf("NewDESOracle: %v", err)
+		}
+
+		result, err := DifferentialAttack(oracle, DifferentialConfig{
+			Rounds: c.rounds,
+			Pairs:  c.pairs,
+			Rand:   rand.NewChaCha8([32]byte{byte(c.rounds)}),
+		})
+		if err != nil {
+			t.Fatalf("%d rounds: %v", c.rounds, err)
+		}
+
+		if !bytes.Equal(result.Key, key) {
+			t.Errorf("%d rounds: recovered key %x, want %x", c.rounds, result.Key, key)
+		}
+		if got := result.SubkeyBits(); got != 42 {
+			t.Errorf("%d rounds: %d round key bits recovered, want 42", c.rounds, got)
+		}
+		if result.Queries != 2*c.pairs*len(result.Characteristics) {
+			t.Errorf("%d rounds: %d queries", c.rounds, result.Queries)
+		}
+
+		var report strings.Builder
+		if err := WriteDifferentialReport(&report, result); err != nil {
+			t.Fatalf("WriteDifferentialReport: %v", err)
+		}
+		if want := fmt.Sprintf("key %x found", key); !strings.Contains(report.String(), want) {
+			t.Errorf("%d rounds: report missing %q:\n%s", c.rounds, want, report.String())
+		}
+		for _, r := range result.Characteristics {
+			if r.Filtered < int(r.ExpectedRightPairs) {
+				t.Errorf("%d rounds, %s: %d pairs survived filtering, expected at least %v right pairs",
+					c.rounds, r.Characteristic.Name, r.Filtered, r.ExpectedRightPairs)
+			}
+			for _, s := range r.Subkeys {
+				if s.Count <= s.RunnerUp {
+					t.Errorf("%d rounds, %s, S%d: subkey count %d does not beat runner-up %d",
+						c.rounds, r.Characteristic.Name, s.SBox+1, s.Count, s.RunnerUp)
+				}
+			}
+		}
+	}
+}
+
+func TestDifferentialAttackErrors(t *testing.T) {
+	oracle, err := NewDESOracle(5, make([]byte, 8))
+	if err != nil {
+		t.Fatalf("NewDESOracle: %v", err)
+	}
+
+	if _, err := DifferentialAttack(oracle, DifferentialConfig{Rounds: 5}); err == nil {
+		t.Error("attack on 5 rounds without characteristics succeeded")
+	}
+	if _, err := DifferentialAttack(nil, DifferentialConfig{Rounds: 4}); err == nil {
+		t.Error("attack without oracle succeeded")
+	}
+	if _, err := DifferentialAttack(oracle, DifferentialConfig{
+		Characteristics: []Characteristic{OneRoundCharacteristic, ThreeRoundCharacteristicA},
+	}); err == nil {
+		t.Error("attack with mismatched characteristics succeeded")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
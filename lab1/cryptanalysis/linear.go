@@ -0,0 +1,593 @@
+package cryptanalysis
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"lab1/des"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// maxLastRoundSBoxes bounds the subkey bits guessed by LinearAttack2.
+const maxLastRoundSBoxes = 2
+
+// sboxLAT[i][a][b] is the number of inputs x of S-box i with a·x == b·S(x),
+// minus 32.
+var sboxLAT = func() (lat [desSBoxCount][64][16]int) {
+	for i := range lat {
+		for a := 0; a < 64; a++ {
+			for b := 0; b < 16; b++ {
+				for x := 0; x < 64; x++ {
+					if parity64(uint64(a&x)) == parity64(uint64(b&sboxLookup(i, x))) {
+						lat[i][a][b]++
+					}
+				}
+				lat[i][a][b] -= 32
+			}
+		}
+	}
+	return lat
+}()
+
+// RoundApproximation approximates one round function by a single S-box:
+// Alpha·X ^ Beta·F(X, K) == KeyMask·K with probability 1/2 + Bias.
+// The zero value is the trivial approximation of an inactive round.
+type RoundApproximation struct {
+	SBox   int
+	Input  int
+	Output int
+	Alpha  uint32
+	Beta   uint32
+	// KeyMask selects bits of the 48-bit round key, most significant first.
+	KeyMask uint64
+	Bias    float64
+}
+
+func (r RoundApproximation) Active() bool {
+	return r.Output != 0
+}
+
+// LinearApproximation is a linear expression over DES reduced to
+// len(Rounds) rounds: InputMask·(L0, R0) ^ OutputMask·(Ln, Rn) equals
+// the XOR of KeyMasks[i]·K(i+1) with probability 1/2 + Bias.
+type LinearApproximation struct {
+	Rounds     []RoundApproximation
+	InputMask  uint64
+	OutputMask uint64
+	KeyMasks   []uint64
+	Bias       float64
+}
+
+// newRoundApproximation places the S-box masks in the 32-bit halves.
+func newRoundApproximation(sbox, input, output int) RoundApproximation {
+	if output == 0 {
+		return RoundApproximation{}
+	}
+
+	var alpha uint32
+	keyMask := uint64(input) << (42 - 6*sbox)
+	for k, position := range des.ExpansionTable {
+		if keyMask>>(47-k)&1 == 1 {
+			alpha ^= 1 << (32 - position)
+		}
+	}
+
+	beta := uint32(permute(uint64(output)<<(28-4*sbox), 32, des.PermutationTable))
+
+	return RoundApproximation{
+		SBox:    sbox,
+		Input:   input,
+		Output:  output,
+		Alpha:   alpha,
+		Beta:    beta,
+		KeyMask: keyMask,
+		Bias:    float64(sboxLAT[sbox][input][output]) / 64,
+	}
+}
+
+// approximationsByBeta lists, for every F output mask that a single S-box
+// can produce, the round approximations with a nonzero bias.
+var approximationsByBeta = func() map[uint32][]RoundApproximation {
+	approximations := make(map[uint32][]RoundApproximation)
+	for sbox := 0; sbox < desSBoxCount; sbox++ {
+		for output := 1; output < 16; output++ {
+			for input := 1; input < 64; input++ {
+				if sboxLAT[sbox][input][output] == 0 {
+					continue
+				}
+				r := newRoundApproximation(sbox, input, output)
+				approximations[r.Beta] = append(approximations[r.Beta], r)
+			}
+		}
+	}
+	for _, list := range approximations {
+		slices.SortFunc(list, func(a, b RoundApproximation) int {
+			return int(math.Abs(b.Bias)*64) - int(math.Abs(a.Bias)*64)
+		})
+	}
+	return approximations
+}()
+
+// maxRoundCorrelation is the largest |2·bias| of any single S-box approximation.
+var maxRoundCorrelation = func() float64 {
+	best := 0.0
+	for _, list := range approximationsByBeta {
+		best = max(best, math.Abs(2*list[0].Bias))
+	}
+	return best
+}()
+
+// BestLinearApproximation searches the approximations of DES reduced to the
+// given number of rounds that use at most one S-box per round, as Matsui's
+// best expressions do, and returns the one with the largest |bias|.
+func BestLinearApproximation(rounds int) (*LinearApproximation, error) {
+	if rounds < 1 || rounds > des.DESRounds {
+		return nil, fmt.Errorf("BestLinearApproximation: rounds must be between 1 and %d (got %d)", des.DESRounds, rounds)
+	}
+
+	if rounds == 1 {
+		best := bestForBeta(0)
+		for beta := range approximationsByBeta {
+			if r := bestForBeta(beta); math.Abs(r.Bias) > math.Abs(best.Bias) {
+				best = r
+			}
+		}
+		return newLinearApproximation([]RoundApproximation{best}), nil
+	}
+
+	return searchTrails(rounds, 0)
+}
+
+// BestAttackApproximation returns the best approximation of the first
+// rounds-1 rounds whose output mask on the left half reaches at most two
+// S-boxes of the last round, as LinearAttack2 requires.
+func BestAttackApproximation(rounds int) (*LinearApproximation, error) {
+	if rounds < 3 || rounds > des.DESRounds {
+		return nil, fmt.Errorf("BestAttackApproximation: rounds must be between 3 and %d (got %d)", des.DESRounds, rounds)
+	}
+	return searchTrails(rounds-1, maxLastRoundSBoxes)
+}
+
+func searchTrails(rounds, lastRoundSBoxes int) (*LinearApproximation, error) {
+	s := &trailSearch{rounds: rounds, lastRoundSBoxes: lastRoundSBoxes, trail: make([]RoundApproximation, rounds)}
+
+	betas := []uint32{0}
+	for beta := range approximationsByBeta {
+		betas = append(betas, beta)
+	}
+	slices.Sort(betas)
+
+	for _, beta1 := range betas {
+		first := bestForBeta(beta1)
+		for _, beta2 := range betas {
+			if beta1 == 0 && beta2 == 0 {
+				continue
+			}
+			s.trail[0] = first
+			s.extend(1, beta2, roundCorrelation(first))
+		}
+	}
+
+	if s.best == nil {
+		return nil, errors.New("no approximation found")
+	}
+	return newLinearApproximation(s.best), nil
+}
+
+type trailSearch struct {
+	rounds int
+	// lastRoundSBoxes, when positive, limits the S-boxes whose outputs
+	// meet the left half of the output mask.
+	lastRoundSBoxes int
+	trail           []RoundApproximation
+	best            []RoundApproximation
+	bestCorrelation float64
+}
+
+// extend fills round index, whose F output mask is fixed to beta by the
+// rounds before it, and recurses. In the middle of the trail round i's
+// input mask must equal beta(i-1) ^ beta(i+1) so that the intermediate
+// halves cancel; the last round's input mask is free.
+func (s *trailSearch) extend(index int, beta uint32, correlation float64) {
+	remaining := s.rounds - index
+	// Two consecutive rounds cannot both be inactive, so at least every
+	// other remaining round costs a factor of maxRoundCorrelation.
+	bound := correlation * math.Pow(maxRoundCorrelation, float64(remaining/2))
+	if bound <= s.bestCorrelation {
+		return
+	}
+
+	previous := s.trail[index-1].Beta
+	candidates := approximationsByBeta[beta]
+	if beta == 0 {
+		candidates = []RoundApproximation{{}}
+	}
+
+	if index == s.rounds-1 {
+		// Candidates are sorted by |bias|, so the first acceptable one is best.
+		for _, last := range candidates {
+			if s.lastRoundSBoxes > 0 && len(activeSBoxes(last.Alpha^previous)) > s.lastRoundSBoxes {
+				continue
+			}
+			if total := correlation * roundCorrelation(last); total > s.bestCorrelation {
+				s.trail[index] = last
+				s.best = slices.Clone(s.trail)
+				s.bestCorrelation = total
+			}
+			return
+		}
+		return
+	}
+
+	for _, r := range candidates {
+		next := r.Alpha ^ previous
+		if _, ok := approximationsByBeta[next]; !ok && next != 0 {
+			continue
+		}
+		if beta == 0 && next == 0 {
+			continue
+		}
+		s.trail[index] = r
+		s.extend(index+1, next, correlation*roundCorrelation(r))
+	}
+}
+
+func bestForBeta(beta uint32) RoundApproximation {
+	if beta == 0 {
+		return RoundApproximation{}
+	}
+	return approximationsByBeta[beta][0]
+}
+
+func roundCorrelation(r RoundApproximation) float64 {
+	if !r.Active() {
+		return 1
+	}
+	return math.Abs(2 * r.Bias)
+}
+
+func newLinearApproximation(rounds []RoundApproximation) *LinearApproximation {
+	n := len(rounds)
+	beta := func(i int) uint32 {
+		if i < 0 || i >= n {
+			return 0
+		}
+		return rounds[i].Beta
+	}
+
+	a := &LinearApproximation{Rounds: rounds, Bias: 0.5}
+	a.InputMask = uint64(beta(0))<<32 | uint64(rounds[0].Alpha^beta(1))
+	a.OutputMask = uint64(rounds[n-1].Alpha^beta(n-2))<<32 | uint64(beta(n-1))
+	if n == 1 {
+		// With one round Ln = R0 carries the input mask.
+		a.InputMask = uint64(beta(0)) << 32
+		a.OutputMask = uint64(rounds[0].Alpha)<<32 | uint64(beta(0))
+	}
+
+	for _, r := range rounds {
+		a.KeyMasks = append(a.KeyMasks, r.KeyMask)
+		if r.Active() {
+			a.Bias *= 2 * r.Bias
+		}
+	}
+	return a
+}
+
+// KeyParity returns the XOR of the round key bits selected by KeyMasks.
+func (a *LinearApproximation) KeyParity(roundKeys []uint64) int {
+	p := 0
+	for i, mask := range a.KeyMasks {
+		p ^= parity64(roundKeys[i] & mask)
+	}
+	return p
+}
+
+// KeyBits returns the 1-based positions of the 64-bit key whose XOR the
+// approximation reveals: round key bits are traced back through the key
+// schedule and bits used an even number of times cancel.
+func (a *LinearApproximation) KeyBits() []int {
+	uses := make(map[int]int)
+	for i, mask := range a.KeyMasks {
+		for j, position := range roundKeyToKey(i + 1) {
+			if mask>>(47-j)&1 == 1 {
+				uses[position]++
+			}
+		}
+	}
+
+	var positions []int
+	for position, count := range uses {
+		if count%2 == 1 {
+			positions = append(positions, position)
+		}
+	}
+	slices.Sort(positions)
+	return positions
+}
+
+// KnownPair is a plaintext and its ciphertext, packed most significant byte first.
+type KnownPair struct {
+	Plaintext  uint64
+	Ciphertext uint64
+}
+
+// GenerateKnownPairs encrypts count random plaintexts with the oracle.
+func GenerateKnownPairs(oracle Oracle, count int, random io.Reader) ([]KnownPair, error) {
+	if oracle == nil {
+		return nil, errors.New("GenerateKnownPairs: oracle cannot be nil")
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	pairs := make([]KnownPair, count)
+	var buf [8]byte
+	for i := range pairs {
+		if _, err := io.ReadFull(random, buf[:]); err != nil {
+			return nil, fmt.Errorf("GenerateKnownPairs: %w", err)
+		}
+		plaintext := binary.BigEndian.Uint64(buf[:])
+		ciphertext, err := oracle.query(plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("GenerateKnownPairs: %w", err)
+		}
+		pairs[i] = KnownPair{plaintext, ciphertext}
+	}
+	return pairs, nil
+}
+
+type LinearResult struct {
+	Approximation *LinearApproximation
+	Pairs         int
+	// Rounds is the number of rounds of the attacked cipher.
+	Rounds int
+	// KeyParity is the recovered XOR of the key bits in Approximation.KeyBits().
+	KeyParity int
+	// Counter is the number of pairs for which the left side of the
+	// approximation was zero under the chosen subkey.
+	Counter int
+	// SBoxes, Subkey and SubkeyMask describe the last round key bits
+	// guessed by Algorithm 2; they are empty for Algorithm 1.
+	SBoxes     []int
+	Subkey     uint64
+	SubkeyMask uint64
+	// RunnerUp is the counter deviation |T - N/2| of the second best subkey.
+	RunnerUp int
+}
+
+// Deviation returns |T - N/2| of the chosen subkey.
+func (r *LinearResult) Deviation() int {
+	return abs(2*r.Counter-r.Pairs) / 2
+}
+
+// LinearAttack1 is Matsui's Algorithm 1: with an approximation covering all
+// rounds of the cipher it recovers the parity of the approximation's key bits.
+func LinearAttack1(pairs []KnownPair, approximation *LinearApproximation) (*LinearResult, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("LinearAttack1: no known pairs")
+	}
+
+	counter := 0
+	for _, pair := range pairs {
+		l0, r0 := unpermuteBlock(pair.Plaintext)
+		rn, ln := unpermuteBlock(pair.Ciphertext)
+		input := uint64(l0)<<32 | uint64(r0)
+		output := uint64(ln)<<32 | uint64(rn)
+		if parity64(input&approximation.InputMask^output&approximation.OutputMask) == 0 {
+			counter++
+		}
+	}
+
+	return &LinearResult{
+		Approximation: approximation,
+		Pairs:         len(pairs),
+		Rounds:        len(approximation.Rounds),
+		KeyParity:     guessParity(counter, len(pairs), approximation.Bias),
+		Counter:       counter,
+	}, nil
+}
+
+// LinearAttack2 is Matsui's Algorithm 2: with an approximation of the first
+// n-1 rounds it guesses the last round subkey bits that feed the
+// approximation's output mask and keeps the guess whose counter deviates
+// most from N/2. It also recovers the key parity of the approximation.
+func LinearAttack2(pairs []KnownPair, approximation *LinearApproximation) (*LinearResult, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("LinearAttack2: no known pairs")
+	}
+
+	// With Lm = Rn ^ F(Ln, Kn) and Rm = Ln, the approximation becomes
+	// InputMask·P ^ gamma·Rn ^ beta·Ln ^ gamma·F(Ln, Kn) = key parity.
+	gamma := uint32(approximation.OutputMask >> 32)
+	beta := uint32(approximation.OutputMask)
+	outputs := sboxOutputs(gamma)
+
+	sboxes := activeSBoxes(gamma)
+	if len(sboxes) == 0 {
+		return nil, errors.New("LinearAttack2: approximation does not involve the last round")
+	}
+	if len(sboxes) > maxLastRoundSBoxes {
+		return nil, fmt.Errorf("LinearAttack2: %d last-round S-boxes active, at most %d supported", len(sboxes), maxLastRoundSBoxes)
+	}
+
+	// Bucket the pairs by the last round S-box inputs and the parity of
+	// the known part, so that each subkey guess costs one pass over the
+	// buckets rather than over the pairs.
+	width := 6 * len(sboxes)
+	buckets := make([][2]int, 1<<width)
+	for _, pair := range pairs {
+		l0, r0 := unpermuteBlock(pair.Plaintext)
+		rn, ln := unpermuteBlock(pair.Ciphertext)
+
+		known := parity64((uint64(l0)<<32|uint64(r0))&approximation.InputMask) ^
+			parity64(uint64(gamma&rn)) ^ parity64(uint64(beta&ln))
+
+		expanded := expand(ln)
+		index := 0
+		for _, sbox := range sboxes {
+			index = index<<6 | sboxInput(expanded, sbox)
+		}
+		buckets[index][known]++
+	}
+
+	result := &LinearResult{
+		Approximation: approximation,
+		Pairs:         len(pairs),
+		Rounds:        len(approximation.Rounds) + 1,
+		SBoxes:        sboxes,
+	}
+
+	bestDeviation := -1
+	for guess := 0; guess < 1<<width; guess++ {
+		counter := 0
+		for index, bucket := range buckets {
+			f := 0
+			for j, sbox := range sboxes {
+				shift := 6 * (len(sboxes) - 1 - j)
+				in := (index ^ guess) >> shift & 0x3F
+				f ^= parity64(uint64(sboxLookup(sbox, in) & sboxOutput(outputs, sbox)))
+			}
+			counter += bucket[f]
+		}
+
+		deviation := abs(2*counter-len(pairs)) / 2
+		if deviation > bestDeviation {
+			result.RunnerUp = max(result.RunnerUp, bestDeviation)
+			bestDeviation = deviation
+			result.Counter = counter
+			result.Subkey = 0
+			for j, sbox := range sboxes {
+				shift := 6 * (len(sboxes) - 1 - j)
+				result.Subkey |= uint64(guess>>shift&0x3F) << (42 - 6*sbox)
+			}
+		} else {
+			result.RunnerUp = max(result.RunnerUp, deviation)
+		}
+	}
+
+	for _, sbox := range sboxes {
+		result.SubkeyMask |= 0x3F << (42 - 6*sbox)
+	}
+	result.KeyParity = guessParity(result.Counter, len(pairs), approximation.Bias)
+	return result, nil
+}
+
+// guessParity turns the counter into the key parity: a positive bias means
+// the left side usually equals the key parity.
+func guessParity(counter, pairs int, bias float64) int {
+	mostlyZero := 2*counter > pairs
+	if mostlyZero == (bias > 0) {
+		return 0
+	}
+	return 1
+}
+
+type SuccessRate struct {
+	Pairs         int
+	Trials        int
+	SubkeySuccess float64
+	ParitySuccess float64
+}
+
+// LinearSuccessRate runs Algorithm 2 against DES reduced to rounds under
+// random keys for every pair count and reports how often the last round
+// subkey bits and the key parity were recovered correctly.
+func LinearSuccessRate(rounds int, pairCounts []int, trials int, random io.Reader) ([]SuccessRate, error) {
+	if trials <= 0 {
+		return nil, fmt.Errorf("LinearSuccessRate: trials must be positive (got %d)", trials)
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	approximation, err := BestAttackApproximation(rounds)
+	if err != nil {
+		return nil, fmt.Errorf("LinearSuccessRate: %w", err)
+	}
+
+	var rates []SuccessRate
+	key := make([]byte, des.DESKeySize)
+	for _, count := range pairCounts {
+		rate := SuccessRate{Pairs: count, Trials: trials}
+		subkeyHits, parityHits := 0, 0
+
+		for trial := 0; trial < trials; trial++ {
+			if _, err := io.ReadFull(random, key); err != nil {
+				return nil, fmt.Errorf("LinearSuccessRate: %w", err)
+			}
+			roundKeys, err := expandRoundKeys(key)
+			if err != nil {
+				return nil, fmt.Errorf("LinearSuccessRate: %w", err)
+			}
+
+			oracle, err := NewDESOracle(rounds, key)
+			if err != nil {
+				return nil, fmt.Errorf("LinearSuccessRate: %w", err)
+			}
+			pairs, err := GenerateKnownPairs(oracle, count, random)
+			if err != nil {
+				return nil, fmt.Errorf("LinearSuccessRate: %w", err)
+			}
+
+			result, err := LinearAttack2(pairs, approximation)
+			if err != nil {
+				return nil, fmt.Errorf("LinearSuccessRate: %w", err)
+			}
+
+			if roundKeys[rounds-1]&result.SubkeyMask == result.Subkey {
+				subkeyHits++
+				if approximation.KeyParity(roundKeys) == result.KeyParity {
+					parityHits++
+				}
+			}
+		}
+
+		rate.SubkeySuccess = float64(subkeyHits) / float64(trials)
+		rate.ParitySuccess = float64(parityHits) / float64(trials)
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// expandRoundKeys returns the 48-bit DES round keys packed into integers.
+func expandRoundKeys(key []byte) ([]uint64, error) {
+	roundKeys, err := des.NewDESKeySchedule().ExpandKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	packed := make([]uint64, len(roundKeys))
+	for i, roundKey := range roundKeys {
+		for _, b := range roundKey {
+			packed[i] = packed[i]<<8 | uint64(b)
+		}
+	}
+	return packed, nil
+}
+
+// activeSBoxes returns the S-boxes whose outputs meet the F output mask.
+func activeSBoxes(mask uint32) []int {
+	outputs := sboxOutputs(mask)
+	var sboxes []int
+	for i := 0; i < desSBoxCount; i++ {
+		if sboxOutput(outputs, i) != 0 {
+			sboxes = append(sboxes, i)
+		}
+	}
+	return sboxes
+}
+
+func parity64(x uint64) int {
+	return bits.OnesCount64(x) & 1
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
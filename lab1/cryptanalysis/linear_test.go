@@ -0,0 +1,187 @@
+package cryptanalysis
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestBestLinearApproximation(t *testing.T) {
+	// |bias| of Matsui's best expressions, as coefficient·2^exponent.
+	cases := []struct {
+		rounds      int
+		coefficient float64
+		exponent    int
+	}{
+		{3, 1.56, -3},
+		{4, 1.95, -5},
+		{5, 1.22, -6},
+		{6, 1.95, -9},
+		{7, 1.95, -10},
+		{8, 1.22, -11},
+	}
+
+	for _, c := range cases {
+		a, err := BestLinearApproximation(c.rounds)
+		if err != nil {
+			t.Fatalf("%d rounds: %v", c.rounds, err)
+		}
+		want := c.coefficient * math.Pow(2, float64(c.exponent))
+		if got := math.Abs(a.Bias); math.Abs(got-want) > 0.01*want {
+			t.Errorf("%d rounds: |bias| %v, want %v", c.rounds, got, want)
+		}
+		if len(a.Rounds) != c.rounds || len(a.KeyMasks) != c.rounds {
+			t.Errorf("%d rounds: approximation has %d rounds", c.rounds, len(a.Rounds))
+		}
+	}
+
+	// Matsui's A: X[15] ^ F[7,18,24,29] = K[22] from NS5(16, 15) = 12.
+	a, _ := BestLinearApproximation(1)
+	r := a.Rounds[0]
+	if r.SBox != 4 || r.Input != 16 || r.Output != 15 || r.Alpha != 1<<15 || r.Beta != 1<<7|1<<18|1<<24|1<<29 || r.KeyMask != 1<<22 {
+		t.Errorf("best 1-round approximation %+v", r)
+	}
+
+	if _, err := BestLinearApproximation(0); err == nil {
+		t.Error("BestLinearApproximation(0) succeeded")
+	}
+}
+
+func TestLinearApproximationHolds(t *testing.T) {
+	random := rand.NewChaCha8([32]byte{1})
+	key := make([]byte, 8)
+	random.Read(key)
+	roundKeys, err := expandRoundKeys(key)
+	if err != nil {
+		t.Fatalf("expandRoundKeys: %v", err)
+	}
+
+	for _, rounds := range []int{1, 2, 3, 4} {
+		a, err := BestLinearApproximation(rounds)
+		if err != nil {
+			t.Fatalf("BestLinearApproximation: %v", err)
+		}
+		oracle, err := NewDESOracle(rounds, key)
+		if err != nil {
+			t.Fatalf("NewDESOracle: %v", err)
+		}
+		pairs, err := GenerateKnownPairs(oracle, 20000, random)
+		if err != nil {
+			t.Fatalf("GenerateKnownPairs: %v", err)
+		}
+
+		keyParity := a.KeyParity(roundKeys)
+		holds := 0
+		for _, pair := range pairs {
+			l0, r0 := unpermuteBlock(pair.Plaintext)
+			rn, ln := unpermuteBlock(pair.Ciphertext)
+			lhs := parity64((uint64(l0)<<32|uint64(r0))&a.InputMask) ^ parity64((uint64(ln)<<32|uint64(rn))&a.OutputMask)
+			if lhs == keyParity {
+				holds++
+			}
+		}
+
+		got := float64(holds)/float64(len(pairs)) - 0.5
+		if math.Abs(got-a.Bias) > 0.015 {
+			t.Errorf("%d rounds: measured bias %v, predicted %v", rounds, got, a.Bias)
+		}
+
+		// The key bits traced through the schedule have the same parity.
+		keyBits := binary.BigEndian.Uint64(key)
+		p := 0
+		for _, position := range a.KeyBits() {
+			p ^= int(keyBits>>(64-position)) & 1
+		}
+		if p != keyParity {
+			t.Errorf("%d rounds: key bits %v have parity %d, round keys %d", rounds, a.KeyBits(), p, keyParity)
+		}
+	}
+}
+
+func TestLinearAttack1(t *testing.T) {
+	random := rand.NewChaCha8([32]byte{2})
+	a, _ := BestLinearApproximation(3)
+
+	for trial := 0; trial < 4; trial++ {
+		key := make([]byte, 8)
+		random.Read(key)
+		roundKeys, _ := expandRoundKeys(key)
+		oracle, _ := NewDESOracle(3, key)
+
+		pairs, err := GenerateKnownPairs(oracle, 1000, random)
+		if err != nil {
+			t.Fatalf("GenerateKnownPairs: %v", err)
+		}
+		result, err := LinearAttack1(pairs, a)
+		if err != nil {
+			t.Fatalf("LinearAttack1: %v", err)
+		}
+		if want := a.KeyParity(roundKeys); result.KeyParity != want {
+			t.Errorf("key %x: parity %d, want %d (T=%d of %d)", key, result.KeyParity, want, result.Counter, result.Pairs)
+		}
+	}
+
+	if _, err := LinearAttack1(nil, a); err == nil {
+		t.Error("LinearAttack1 without pairs succeeded")
+	}
+}
+
+func TestLinearAttack2(t *testing.T) {
+	random := rand.NewChaCha8([32]byte{3})
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	roundKeys, _ := expandRoundKeys(key)
+
+	a, err := BestAttackApproximation(6)
+	if err != nil {
+		t.Fatalf("BestAttackApproximation: %v", err)
+	}
+	oracle, _ := NewDESOracle(6, key)
+	pairs, err := GenerateKnownPairs(oracle, 1<<15, random)
+	if err != nil {
+		t.Fatalf("GenerateKnownPairs: %v", err)
+	}
+
+	result, err := LinearAttack2(pairs, a)
+	if err != nil {
+		t.Fatalf("LinearAttack2: %v", err)
+	}
+	if result.Rounds != 6 || len(result.SBoxes) == 0 {
+		t.Fatalf("result for %d rounds, S-boxes %v", result.Rounds, result.SBoxes)
+	}
+	if got, want := result.Subkey, roundKeys[5]&result.SubkeyMask; got != want {
+		t.Errorf("subkey %012x, want %012x", got, want)
+	}
+	if want := a.KeyParity(roundKeys); result.KeyParity != want {
+		t.Errorf("key parity %d, want %d", result.KeyParity, want)
+	}
+	if result.Deviation() <= result.RunnerUp {
+		t.Errorf("deviation %d does not beat runner-up %d", result.Deviation(), result.RunnerUp)
+	}
+
+	var report strings.Builder
+	if err := WriteLinearReport(&report, result); err != nil {
+		t.Fatalf("WriteLinearReport: %v", err)
+	}
+	if want := fmt.Sprintf("round key 6: %012x", result.Subkey); !strings.Contains(report.String(), want) {
+		t.Errorf("report missing %q:\n%s", want, report.String())
+	}
+}
+
+func TestLinearSuccessRate(t *testing.T) {
+	rates, err := LinearSuccessRate(4, []int{16, 2048}, 8, rand.NewChaCha8([32]byte{4}))
+	if err != nil {
+		t.Fatalf("LinearSuccessRate: %v", err)
+	}
+	if len(rates) != 2 || rates[1].Pairs != 2048 || rates[1].Trials != 8 {
+		t.Fatalf("rates %+v", rates)
+	}
+	if rates[1].SubkeySuccess < 0.75 || rates[1].ParitySuccess < 0.75 {
+		t.Errorf("2048 pairs: success %+v", rates[1])
+	}
+	if rates[0].SubkeySuccess > rates[1].SubkeySuccess {
+		t.Errorf("success dropped with more pairs: %+v", rates)
+	}
+}
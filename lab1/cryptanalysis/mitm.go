@@ -0,0 +1,336 @@
+package cryptanalysis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"lab1/des"
+	"math/bits"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	DefaultMITMTableEntries = 1 << 20
+
+	// maxMITMKeyBits bounds the unknown bits of each key, which the table
+	// stores as 32-bit indices.
+	maxMITMKeyBits = 32
+	// searchBatch is the number of candidate keys a worker claims at once.
+	searchBatch = 1 << 10
+)
+
+// MITMConfig describes the reduced key space of a DoubleDES key K1 || K2.
+// Key1 and Key2 hold the known bits; Unknown1 and Unknown2 select the bits
+// to search and must not include parity bits.
+type MITMConfig struct {
+	Key1     uint64
+	Key2     uint64
+	Unknown1 uint64
+	Unknown2 uint64
+	Workers  int
+	// TableEntries bounds the table of middle values. When the K1 space is
+	// larger it is split into chunks, each followed by a pass over K2.
+	TableEntries int
+}
+
+type MITMResult struct {
+	Key []byte
+	// Candidates counts the key pairs that matched on the first pair;
+	// all but the right one are rejected by the remaining pairs.
+	Candidates  int64
+	Encryptions int64
+	Decryptions int64
+	Chunks      int
+}
+
+type middleEntry struct {
+	middle uint64
+	index  uint32
+}
+
+// MeetInTheMiddle recovers a DoubleDES key from known pairs. It encrypts
+// the first plaintext under every K1 candidate into a sorted table, then
+// decrypts the first ciphertext under every K2 candidate and looks the
+// result up; each match is checked against the other pairs. The work is
+// about 2^|Unknown1| + 2^|Unknown2| DES operations instead of their product.
+func MeetInTheMiddle(ctx context.Context, pairs []KnownPair, config MITMConfig) (*MITMResult, error) {
+	if len(pairs) < 2 {
+		return nil, fmt.Errorf("MeetInTheMiddle: need at least 2 known pairs to verify candidates (got %d)", len(pairs))
+	}
+	for _, unknown := range []uint64{config.Unknown1, config.Unknown2} {
+		if err := checkUnknownBits(unknown, maxMITMKeyBits); err != nil {
+			return nil, fmt.Errorf("MeetInTheMiddle: %w", err)
+		}
+	}
+
+	workers := config.Workers
+	if workers < 0 {
+		return nil, errors.New("MeetInTheMiddle: number of workers cannot be negative")
+	}
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	tableEntries := config.TableEntries
+	if tableEntries < 0 {
+		return nil, errors.New("MeetInTheMiddle: table size cannot be negative")
+	}
+	if tableEntries == 0 {
+		tableEntries = DefaultMITMTableEntries
+	}
+
+	m := &mitm{
+		pairs:    pairs,
+		key1:     config.Key1 &^ config.Unknown1,
+		key2:     config.Key2 &^ config.Unknown2,
+		unknown1: config.Unknown1,
+		unknown2: config.Unknown2,
+		workers:  workers,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &MITMResult{}
+	space1 := uint64(1) << bits.OnesCount64(config.Unknown1)
+	for start := uint64(0); start < space1 && m.found.Load() == nil; start += uint64(tableEntries) {
+		end := min(start+uint64(tableEntries), space1)
+		result.Chunks++
+
+		table, err := m.buildTable(ctx, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("MeetInTheMiddle: %w", err)
+		}
+		if err := m.matchTable(ctx, cancel, table); err != nil {
+			return nil, fmt.Errorf("MeetInTheMiddle: %w", err)
+		}
+	}
+
+	result.Candidates = m.candidates.Load()
+	result.Encryptions = m.encryptions.Load()
+	result.Decryptions = m.decryptions.Load()
+
+	found := m.found.Load()
+	if found == nil {
+		return result, fmt.Errorf("MeetInTheMiddle: %w", ErrKeyNotFound)
+	}
+	result.Key = binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(nil, found[0]), found[1])
+	return result, nil
+}
+
+type mitm struct {
+	pairs              []KnownPair
+	key1, key2         uint64
+	unknown1, unknown2 uint64
+	workers            int
+
+	found       atomic.Pointer[[2]uint64]
+	candidates  atomic.Int64
+	encryptions atomic.Int64
+	decryptions atomic.Int64
+}
+
+// buildTable encrypts the first plaintext under the K1 candidates with
+// indices in [start, end) and sorts the middle values.
+func (m *mitm) buildTable(ctx context.Context, start, end uint64) ([]middleEntry, error) {
+	table := make([]middleEntry, end-start)
+	plaintext := m.pairs[0].Plaintext
+
+	err := parallelRange(ctx, m.workers, start, end, func(cipher *des.DES, index uint64) error {
+		middle, err := desEncrypt(cipher, des.CandidateKey(m.key1, m.unknown1, index), plaintext)
+		if err != nil {
+			return err
+		}
+		table[index-start] = middleEntry{middle: middle, index: uint32(index)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.encryptions.Add(int64(len(table)))
+
+	slices.SortFunc(table, func(a, b middleEntry) int {
+		switch {
+		case a.middle < b.middle:
+			return -1
+		case a.middle > b.middle:
+			return 1
+		}
+		return 0
+	})
+	return table, nil
+}
+
+// matchTable decrypts the first ciphertext under every K2 candidate and
+// verifies the K1 candidates whose middle value matches.
+func (m *mitm) matchTable(ctx context.Context, cancel context.CancelFunc, table []middleEntry) error {
+	ciphertext := m.pairs[0].Ciphertext
+	space2 := uint64(1) << bits.OnesCount64(m.unknown2)
+
+	err := parallelRange(ctx, m.workers, 0, space2, func(cipher *des.DES, index uint64) error {
+		key2 := des.CandidateKey(m.key2, m.unknown2, index)
+		middle, err := desDecrypt(cipher, key2, ciphertext)
+		if err != nil {
+			return err
+		}
+		m.decryptions.Add(1)
+
+		i, _ := slices.BinarySearchFunc(table, middle, func(e middleEntry, target uint64) int {
+			switch {
+			case e.middle < target:
+				return -1
+			case e.middle > target:
+				return 1
+			}
+			return 0
+		})
+		for ; i < len(table) && table[i].middle == middle; i++ {
+			m.candidates.Add(1)
+			key1 := des.CandidateKey(m.key1, m.unknown1, uint64(table[i].index))
+			ok, err := m.verify(key1, key2)
+			if err != nil {
+				return err
+			}
+			if ok {
+				m.found.CompareAndSwap(nil, &[2]uint64{key1, key2})
+				cancel()
+				return nil
+			}
+		}
+		return nil
+	})
+	if m.found.Load() != nil {
+		return nil
+	}
+	return err
+}
+
+// verify double-encrypts the remaining plaintexts with its own ciphers,
+// since the worker's cipher still holds K2 for the next lookups.
+func (m *mitm) verify(key1, key2 uint64) (bool, error) {
+	first, err := des.NewDES()
+	if err != nil {
+		return false, err
+	}
+	defer first.Destroy()
+	second, err := des.NewDES()
+	if err != nil {
+		return false, err
+	}
+	defer second.Destroy()
+
+	for _, pair := range m.pairs[1:] {
+		middle, err := desEncrypt(first, key1, pair.Plaintext)
+		if err != nil {
+			return false, err
+		}
+		ciphertext, err := desEncrypt(second, key2, middle)
+		if err != nil {
+			return false, err
+		}
+		if ciphertext != pair.Ciphertext {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parallelRange calls fn for every index in [start, end), with each worker
+// owning a DES instance. Workers claim batches of indices and stop at the
+// first error or when ctx is done.
+func parallelRange(ctx context.Context, workers int, start, end uint64, fn func(cipher *des.DES, index uint64) error) error {
+	return parallelBatches(ctx, workers, start, end, func(cipher *des.DES, first, last uint64) error {
+		for index := first; index < last; index++ {
+			if err := fn(cipher, index); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// parallelBatches is parallelRange passing fn a whole batch [first, last).
+func parallelBatches(ctx context.Context, workers int, start, end uint64, fn func(cipher *des.DES, first, last uint64) error) error {
+	var next atomic.Uint64
+	next.Store(start)
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, workers)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cipher, err := des.NewDES()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			defer cipher.Destroy()
+
+			for {
+				if err := ctx.Err(); err != nil {
+					errCh <- err
+					return
+				}
+
+				batch := next.Add(searchBatch) - searchBatch
+				if batch >= end {
+					return
+				}
+				if err := fn(cipher, batch, min(batch+searchBatch, end)); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkUnknownBits(unknown uint64, limit int) error {
+	if unknown&des.ParityBits != 0 {
+		return fmt.Errorf("unknown key bits %016x include parity bits", unknown)
+	}
+	if n := bits.OnesCount64(unknown); n > limit {
+		return fmt.Errorf("%d unknown key bits, at most %d supported", n, limit)
+	}
+	return nil
+}
+
+func desEncrypt(cipher *des.DES, key, block uint64) (uint64, error) {
+	return desOperation(cipher, cipher.Encrypt, key, block)
+}
+
+func desDecrypt(cipher *des.DES, key, block uint64) (uint64, error) {
+	return desOperation(cipher, cipher.Decrypt, key, block)
+}
+
+func desOperation(cipher *des.DES, operation func([]byte) ([]byte, error), key, block uint64) (uint64, error) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], key)
+	if err := cipher.SetKey(buf[:]); err != nil {
+		return 0, err
+	}
+
+	binary.BigEndian.PutUint64(buf[:], block)
+	result, err := operation(buf[:])
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(result), nil
+}
@@ -0,0 +1,79 @@
+package cryptanalysis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	tripledes "lab1/tripleDes"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestMeetInTheMiddle(t *testing.T) {
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1, 0x0E, 0x32, 0x92, 0x32, 0xEA, 0x6D, 0x0D, 0x73}
+	cipher, err := tripledes.NewDoubleDES()
+	if err != nil {
+		t.Fatalf("NewDoubleDES: %v", err)
+	}
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	pairs, err := GenerateKnownPairs(cipher.Encrypt, 3, rand.NewChaCha8([32]byte{5}))
+	if err != nil {
+		t.Fatalf("GenerateKnownPairs: %v", err)
+	}
+
+	// 10 unknown bits in each half: the low byte and three bits of the next.
+	const unknown = 0x0EFE
+	config := MITMConfig{
+		Key1:         0x13345779_9BBCDFF1 &^ unknown,
+		Key2:         0x0E329232_EA6D0D73 &^ unknown,
+		Unknown1:     unknown,
+		Unknown2:     unknown,
+		Workers:      4,
+		TableEntries: 300,
+	}
+
+	result, err := MeetInTheMiddle(context.Background(), pairs, config)
+	if err != nil {
+		t.Fatalf("MeetInTheMiddle: %v", err)
+	}
+	if !bytes.Equal(result.Key, key) {
+		t.Errorf("key %x, want %x", result.Key, key)
+	}
+	if result.Chunks < 2 || result.Candidates < 1 {
+		t.Errorf("chunks %d, candidates %d", result.Chunks, result.Candidates)
+	}
+	if result.Encryptions > 1<<10 || result.Decryptions > int64(result.Chunks)<<10 {
+		t.Errorf("%d encryptions and %d decryptions for a 2^20 key space", result.Encryptions, result.Decryptions)
+	}
+
+	config.Key2 ^= 0x10000000_00000000
+	if _, err := MeetInTheMiddle(context.Background(), pairs, config); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("wrong known bits: got %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestMeetInTheMiddleErrors(t *testing.T) {
+	pairs := []KnownPair{{1, 2}, {3, 4}}
+
+	if _, err := MeetInTheMiddle(context.Background(), pairs[:1], MITMConfig{}); err == nil {
+		t.Error("single pair accepted")
+	}
+	if _, err := MeetInTheMiddle(context.Background(), pairs, MITMConfig{Unknown1: 0x01}); err == nil {
+		t.Error("unknown parity bit accepted")
+	}
+	if _, err := MeetInTheMiddle(context.Background(), pairs, MITMConfig{Unknown2: 0xFEFEFEFE_FE000000}); err == nil {
+		t.Error("35 unknown bits accepted")
+	}
+	if _, err := MeetInTheMiddle(context.Background(), pairs, MITMConfig{Workers: -1}); err == nil {
+		t.Error("negative workers accepted")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := MeetInTheMiddle(ctx, pairs, MITMConfig{Unknown1: 0xFE, Unknown2: 0xFE}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: got %v, want context.Canceled", err)
+	}
+}
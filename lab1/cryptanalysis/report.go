@@ -0,0 +1,72 @@
+package cryptanalysis
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+func WriteDifferentialReport(w io.Writer, result *DifferentialResult) error {
+	fmt.Fprintf(w, "%d-round DES, %d chosen plaintexts\n\n", result.Rounds, result.Queries)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHARACTERISTIC\tPROBABILITY\tPAIRS\tFILTERED\tEXPECTED RIGHT\tSBOX\tSUBKEY\tCOUNT\tRUNNER-UP")
+	for _, c := range result.Characteristics {
+		for i, s := range c.Subkeys {
+			if i == 0 {
+				fmt.Fprintf(tw, "%s\t%g\t%d\t%d\t%.1f", c.Characteristic.Name, c.Probability, c.Pairs, c.Filtered, c.ExpectedRightPairs)
+			} else {
+				fmt.Fprint(tw, "\t\t\t\t")
+			}
+			fmt.Fprintf(tw, "\tS%d\t%02x\t%d\t%d\n", s.SBox+1, s.Subkey, s.Count, s.RunnerUp)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nround key %d: %012x (mask %012x, %d bits)\n", result.Rounds, result.RoundKey, result.RoundKeyMask, result.SubkeyBits())
+	if result.Key == nil {
+		_, err := fmt.Fprintf(w, "key not found after %d candidates\n", result.Searched)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "key %x found after %d candidates\n", result.Key, result.Searched)
+	return err
+}
+
+func WriteLinearReport(w io.Writer, result *LinearResult) error {
+	a := result.Approximation
+	fmt.Fprintf(w, "%d-round DES, %d known plaintexts\n", result.Rounds, result.Pairs)
+	fmt.Fprintf(w, "approximation: %d rounds, input mask %016x, output mask %016x, bias %g\n\n",
+		len(a.Rounds), a.InputMask, a.OutputMask, a.Bias)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROUND\tSBOX\tINPUT\tOUTPUT\tBIAS")
+	for i, r := range a.Rounds {
+		if !r.Active() {
+			fmt.Fprintf(tw, "%d\t-\t\t\t\n", i+1)
+			continue
+		}
+		fmt.Fprintf(tw, "%d\tS%d\t%02x\t%x\t%g\n", i+1, r.SBox+1, r.Input, r.Output, r.Bias)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\ncounter %d, deviation %d\n", result.Counter, result.Deviation())
+	if result.SubkeyMask != 0 {
+		fmt.Fprintf(w, "round key %d: %012x (mask %012x, runner-up deviation %d)\n",
+			result.Rounds, result.Subkey, result.SubkeyMask, result.RunnerUp)
+	}
+	_, err := fmt.Fprintf(w, "key bits %v: parity %d\n", a.KeyBits(), result.KeyParity)
+	return err
+}
+
+func WriteSuccessRates(w io.Writer, rates []SuccessRate) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PAIRS\tTRIALS\tSUBKEY\tSUBKEY+PARITY")
+	for _, r := range rates {
+		fmt.Fprintf(tw, "%d\t%d\t%.2f\t%.2f\n", r.Pairs, r.Trials, r.SubkeySuccess, r.ParitySuccess)
+	}
+	return tw.Flush()
+}
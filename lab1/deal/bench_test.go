@@ -0,0 +1,40 @@
+package deal
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkDEAL(b *testing.B) {
+	for _, rounds := range []int{6, 8} {
+		cipher, err := NewDEAL(rounds)
+		if err != nil {
+			b.Fatalf("NewDEAL: %v", err)
+		}
+		if err := cipher.SetKey([]byte("0123456789ABCDEF")); err != nil {
+			b.Fatalf("SetKey: %v", err)
+		}
+
+		block := []byte("sixteen byte blk")
+
+		b.Run(fmt.Sprintf("Encrypt/rounds=%d", rounds), func(b *testing.B) {
+			b.SetBytes(int64(cipher.BlockSize()))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := cipher.Encrypt(block); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("Decrypt/rounds=%d", rounds), func(b *testing.B) {
+			b.SetBytes(int64(cipher.BlockSize()))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := cipher.Decrypt(block); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
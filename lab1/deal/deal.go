@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"lab1/des"
 	"lab1/feistel"
+	"lab1/interfaces"
 )
 
 type DEALAdapter struct {
@@ -22,19 +23,17 @@ func NewDEALAdapter(desInstance *des.DES) *DEALAdapter {
 
 func (da *DEALAdapter) Apply(rightHalf []byte, roundKey []byte) ([]byte, error) {
 	if len(rightHalf) != 8 {
-		return nil, fmt.Errorf("DEAL adapter: right half must be 8 bytes (got %d)", len(rightHalf))
+		return nil, &interfaces.BlockSizeError{Cipher: "DEAL adapter: right half", Size: len(rightHalf), Valid: []int{8}}
 	}
 
 	desInstance, err := des.NewDES()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create DES: %w", err)
 	}
+	defer desInstance.Destroy()
 
 	if len(roundKey) >= 8 {
-		desKey := make([]byte, 8)
-		copy(desKey, roundKey[:8])
-
-		err := desInstance.SetKey(desKey)
+		err := desInstance.SetKey(roundKey[:8])
 		if err != nil {
 			return nil, fmt.Errorf("failed to set DES key: %w", err)
 		}
@@ -66,7 +65,7 @@ func NewDEALKeySchedule(numRounds int) *DEALKeySchedule {
 
 func (dks *DEALKeySchedule) ExpandKey(key []byte) ([][]byte, error) {
 	if len(key) != dks.keySize {
-		return nil, fmt.Errorf("DEAL key must be %d bytes (got %d)", dks.keySize, len(key))
+		return nil, &interfaces.KeySizeError{Cipher: "DEAL", Size: len(key), Valid: []int{dks.keySize}}
 	}
 
 	roundKeys := make([][]byte, dks.numRounds)
@@ -118,16 +117,21 @@ func (d *DEAL) SetKey(key []byte) error {
 	return d.network.SetKey(key)
 }
 
+func (d *DEAL) Destroy() {
+	d.network.Destroy()
+	d.desAdapter.desInstance.Destroy()
+}
+
 func (d *DEAL) Encrypt(block []byte) ([]byte, error) {
 	if len(block) != 16 {
-		return nil, fmt.Errorf("DEAL block must be 16 bytes (got %d)", len(block))
+		return nil, &interfaces.BlockSizeError{Cipher: "DEAL", Size: len(block), Valid: []int{16}}
 	}
 	return d.network.Encrypt(block)
 }
 
 func (d *DEAL) Decrypt(block []byte) ([]byte, error) {
 	if len(block) != 16 {
-		return nil, fmt.Errorf("DEAL block must be 16 bytes (got %d)", len(block))
+		return nil, &interfaces.BlockSizeError{Cipher: "DEAL", Size: len(block), Valid: []int{16}}
 	}
 	return d.network.Decrypt(block)
 }
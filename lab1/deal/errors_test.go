@@ -0,0 +1,52 @@
+package deal
+
+import (
+	"errors"
+	"lab1/interfaces"
+	"testing"
+)
+
+func TestDEALErrors(t *testing.T) {
+	cipher, err := NewDEAL(6)
+	if err != nil {
+		t.Fatalf("NewDEAL: %v", err)
+	}
+
+	if _, err := cipher.Encrypt(make([]byte, 16)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Encrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+
+	var keyErr *interfaces.KeySizeError
+	if err := cipher.SetKey(make([]byte, 24)); !errors.As(err, &keyErr) || keyErr.Size != 24 {
+		t.Errorf("24-byte key: got %v, want KeySizeError with size 24", err)
+	}
+
+	if err := cipher.SetKey(make([]byte, 16)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	if _, err := cipher.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Encrypt 8-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, 17)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Decrypt 17-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+}
+
+func TestDEALDestroy(t *testing.T) {
+	cipher, err := NewDEAL(6)
+	if err != nil {
+		t.Fatalf("NewDEAL: %v", err)
+	}
+	if err := cipher.SetKey(make([]byte, 16)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	cipher.Destroy()
+
+	if _, err := cipher.Encrypt(make([]byte, 16)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Encrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if err := cipher.SetKey(make([]byte, 16)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("SetKey after Destroy: got %v, want ErrDestroyed", err)
+	}
+}
@@ -0,0 +1,119 @@
+package des
+
+import "testing"
+
+func newBenchmarkDES(b *testing.B) *DES {
+	b.Helper()
+
+	cipher, err := NewDES()
+	if err != nil {
+		b.Fatalf("NewDES: %v", err)
+	}
+	if err := cipher.SetKey([]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}); err != nil {
+		b.Fatalf("SetKey: %v", err)
+	}
+	return cipher
+}
+
+func BenchmarkDESEncrypt(b *testing.B) {
+	cipher := newBenchmarkDES(b)
+	block := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+
+	b.SetBytes(DESBlockSize)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := cipher.Encrypt(block); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDESDecrypt(b *testing.B) {
+	cipher := newBenchmarkDES(b)
+	block := []byte{0x85, 0xE8, 0x13, 0x54, 0x0F, 0x0A, 0xB4, 0x05}
+
+	b.SetBytes(DESBlockSize)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := cipher.Decrypt(block); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDESKeySchedule(b *testing.B) {
+	schedule := NewDESKeySchedule()
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := schedule.ExpandKey(key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReferenceDESEncrypt runs the bit-level implementation that the
+// table-driven DES replaced, for comparison.
+func BenchmarkReferenceDESEncrypt(b *testing.B) {
+	reference := newReferenceDES(b, Config{})
+	if err := reference.network.SetKey([]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}); err != nil {
+		b.Fatalf("SetKey: %v", err)
+	}
+	block := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+
+	b.SetBytes(DESBlockSize)
+	b.ReportAllocs()
+	for b.Loop() {
+		reference.crypt(b, block, false)
+	}
+}
+
+func BenchmarkDESSetKey(b *testing.B) {
+	cipher := newBenchmarkDES(b)
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := cipher.SetKey(key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBitslicedDESBlocks encrypts 64 blocks at once, for comparison
+// with BenchmarkDESEncrypt.
+func BenchmarkBitslicedDESBlocks(b *testing.B) {
+	cipher, err := NewBitslicedDES()
+	if err != nil {
+		b.Fatalf("NewBitslicedDES: %v", err)
+	}
+	if err := cipher.SetKey([]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}); err != nil {
+		b.Fatalf("SetKey: %v", err)
+	}
+	blocks := make([]byte, BitslicedLanes*DESBlockSize)
+
+	b.SetBytes(int64(len(blocks)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := cipher.cryptBlocks(blocks, blocks, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBitslicedDESEncryptKeys(b *testing.B) {
+	cipher, err := NewBitslicedDES()
+	if err != nil {
+		b.Fatalf("NewBitslicedDES: %v", err)
+	}
+	var keys, ciphertexts [BitslicedLanes]uint64
+	for i := range keys {
+		keys[i] = uint64(i) << 8
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		cipher.EncryptKeys(0x0123456789ABCDEF, &keys, &ciphertexts)
+	}
+}
@@ -0,0 +1,306 @@
+package des
+
+import (
+	"encoding/binary"
+	"fmt"
+	"lab1/interfaces"
+	"math/bits"
+	"sync"
+)
+
+// BitslicedLanes is the number of blocks, or keys, processed together.
+const BitslicedLanes = 64
+
+// bitsliceTables holds a DES variant for bitsliced evaluation. The tables
+// are 0-based bit indices, so IP, E, P and FP cost nothing: they only pick
+// which uint64 slice feeds the next step. Every S-box output bit is a tree
+// of multiplexers over the first four input bits whose leaves are functions
+// of the last two, taken from the S-box truth table, so all lanes run the
+// same instructions whatever their data.
+type bitsliceTables struct {
+	ip, fp      [64]int
+	expansion   [48]int
+	permutation [32]int
+	// sboxes[i][o][j] is the truth table of output bit o for inputs 4j to
+	// 4j+3, a function of the last two input bits.
+	sboxes [8][4][16]uint8
+	// keyBits[r][k] is the bit of the 8-byte key that becomes bit k of the
+	// round r key.
+	keyBits [][48]int
+}
+
+var defaultBitsliceTables = sync.OnceValue(func() *bitsliceTables {
+	return newBitsliceTables(DefaultConfig())
+})
+
+func newBitsliceTables(config Config) *bitsliceTables {
+	t := &bitsliceTables{keyBits: make([][48]int, config.Rounds)}
+
+	fp := invertPermutation(config.IP)
+	for i := range 64 {
+		t.ip[i], t.fp[i] = config.IP[i]-1, fp[i]-1
+	}
+	for i := range 48 {
+		t.expansion[i] = config.Expansion[i] - 1
+	}
+	for i := range 32 {
+		t.permutation[i] = config.Permutation[i] - 1
+	}
+
+	for i := range t.sboxes {
+		for o := range 4 {
+			for x := range 64 {
+				row, col := x>>4&2|x&1, x>>1&0xF
+				bit := config.SBoxes[i][row][col] >> (3 - o) & 1
+				t.sboxes[i][o][x/4] |= bit << (x % 4)
+			}
+		}
+	}
+
+	// Follow every round key bit back through PC2, the rotations and PC1.
+	total := 0
+	for r := range t.keyBits {
+		total += config.Rotations[r]
+		for k, position := range config.PC2 {
+			cd := position - 1
+			if cd < 28 {
+				cd = (cd + total) % 28
+			} else {
+				cd = 28 + (cd-28+total)%28
+			}
+			t.keyBits[r][k] = config.PC1[cd] - 1
+		}
+	}
+	return t
+}
+
+// crypt runs the cipher on 64 lanes in place. state[s] holds bit s of every
+// lane, counting from the most significant bit of the block.
+func (t *bitsliceTables) crypt(state *[64]uint64, roundKeys [][48]uint64, decrypt bool) {
+	var left, right [32]uint64
+	for i := range 32 {
+		left[i], right[i] = state[t.ip[i]], state[t.ip[32+i]]
+	}
+
+	for round := range roundKeys {
+		key := &roundKeys[round]
+		if decrypt {
+			key = &roundKeys[len(roundKeys)-1-round]
+		}
+
+		var substituted [32]uint64
+		for i := range 8 {
+			var in [6]uint64
+			for b := range 6 {
+				in[b] = right[t.expansion[6*i+b]] ^ key[6*i+b]
+			}
+			t.sbox(i, &in, (*[4]uint64)(substituted[4*i:]))
+		}
+
+		var next [32]uint64
+		for j := range 32 {
+			next[j] = left[j] ^ substituted[t.permutation[j]]
+		}
+		left, right = right, next
+	}
+
+	var preoutput [64]uint64
+	copy(preoutput[:32], right[:])
+	copy(preoutput[32:], left[:])
+	for i := range 64 {
+		state[i] = preoutput[t.fp[i]]
+	}
+}
+
+func (t *bitsliceTables) sbox(i int, in *[6]uint64, out *[4]uint64) {
+	// All 16 functions of the last two input bits, indexed by truth table.
+	minterms := [4]uint64{^in[4] &^ in[5], ^in[4] & in[5], in[4] &^ in[5], in[4] & in[5]}
+	var functions [16]uint64
+	for f := 1; f < 16; f++ {
+		functions[f] = functions[f&(f-1)] | minterms[bits.TrailingZeros(uint(f))]
+	}
+
+	for o := range 4 {
+		leaves := &t.sboxes[i][o]
+
+		var nodes [8]uint64
+		for j := range nodes {
+			a, b := functions[leaves[2*j]&15], functions[leaves[2*j+1]&15]
+			nodes[j] = a ^ in[3]&(a^b)
+		}
+		for j := range 4 {
+			nodes[j] = nodes[2*j] ^ in[2]&(nodes[2*j]^nodes[2*j+1])
+		}
+		for j := range 2 {
+			nodes[j] = nodes[2*j] ^ in[1]&(nodes[2*j]^nodes[2*j+1])
+		}
+		out[o] = nodes[0] ^ in[0]&(nodes[0]^nodes[1])
+	}
+}
+
+// transpose64 swaps rows and columns of a 64x64 bit matrix whose rows are
+// read from the most significant bit. It is its own inverse.
+func transpose64(a *[64]uint64) {
+	mask := uint64(0x00000000FFFFFFFF)
+	for j := 32; j != 0; j >>= 1 {
+		for k := 0; k < 64; k = (k + j + 1) &^ j {
+			t := (a[k] ^ a[k+j]>>j) & mask
+			a[k] ^= t
+			a[k+j] ^= t << j
+		}
+		mask ^= mask << (j >> 1)
+	}
+}
+
+// BitslicedDES computes DES with bitwise operations on 64 blocks or keys
+// at once, in constant time with respect to keys and data. Encrypting 64
+// blocks together is still slower than 64 calls to the table-driven DES, so
+// it is not a BulkCipher for CipherContext; Encrypt and Decrypt exist to
+// validate it against DES. EncryptKeys, which shares one plaintext across
+// 64 keys, is where it pays off, in key search.
+type BitslicedDES struct {
+	tables    *bitsliceTables
+	policy    KeyPolicy
+	roundKeys [][48]uint64
+	destroyed bool
+}
+
+func NewBitslicedDES() (*BitslicedDES, error) {
+	return &BitslicedDES{tables: defaultBitsliceTables()}, nil
+}
+
+// NewBitslicedDESWithConfig builds a bitsliced DES variant, validated as
+// in NewDESWithConfig.
+func NewBitslicedDESWithConfig(config Config) (*BitslicedDES, error) {
+	config = config.withDefaults()
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &BitslicedDES{tables: newBitsliceTables(config)}, nil
+}
+
+// SetKeyPolicy selects the keys SetKey refuses; by default all are accepted.
+func (b *BitslicedDES) SetKeyPolicy(policy KeyPolicy) {
+	b.policy = policy
+}
+
+// SetKey accepts 8-byte keys and 7-byte keys without parity bits.
+func (b *BitslicedDES) SetKey(key []byte) error {
+	if b.destroyed {
+		return interfaces.ErrDestroyed
+	}
+	if err := b.policy.Check(key); err != nil {
+		return err
+	}
+
+	key, err := normalizeKey(key)
+	if err != nil {
+		return err
+	}
+	defer interfaces.Zeroize(key)
+
+	value := binary.BigEndian.Uint64(key)
+	roundKeys := make([][48]uint64, len(b.tables.keyBits))
+	for r, keyBits := range b.tables.keyBits {
+		for k, bit := range keyBits {
+			roundKeys[r][k] = -(value >> (63 - bit) & 1)
+		}
+	}
+
+	clear(b.roundKeys)
+	b.roundKeys = roundKeys
+	return nil
+}
+
+func (b *BitslicedDES) Destroy() {
+	clear(b.roundKeys)
+	b.roundKeys = nil
+	b.destroyed = true
+}
+
+func (b *BitslicedDES) Encrypt(block []byte) ([]byte, error) {
+	return b.crypt(block, false)
+}
+
+func (b *BitslicedDES) Decrypt(block []byte) ([]byte, error) {
+	return b.crypt(block, true)
+}
+
+func (b *BitslicedDES) crypt(block []byte, decrypt bool) ([]byte, error) {
+	if b.destroyed {
+		return nil, interfaces.ErrDestroyed
+	}
+	if len(block) != DESBlockSize {
+		return nil, &interfaces.BlockSizeError{Cipher: "bitsliced DES", Size: len(block), Valid: []int{DESBlockSize}}
+	}
+	result := make([]byte, DESBlockSize)
+	return result, b.cryptBlocks(result, block, decrypt)
+}
+
+func (b *BitslicedDES) BlockSize() int {
+	return DESBlockSize
+}
+
+// cryptBlocks runs the cipher on every block of src into dst, 64 at a time.
+// dst must be at least as long as src and may be src itself.
+func (b *BitslicedDES) cryptBlocks(dst, src []byte, decrypt bool) error {
+	if b.destroyed {
+		return interfaces.ErrDestroyed
+	}
+	if len(b.roundKeys) == 0 {
+		return fmt.Errorf("round keys not set: %w", interfaces.ErrKeyNotSet)
+	}
+	if len(src)%DESBlockSize != 0 {
+		return fmt.Errorf("bitsliced DES: input length %d is not a multiple of %d: %w", len(src), DESBlockSize, interfaces.ErrInvalidBlockSize)
+	}
+	if len(dst) < len(src) {
+		return fmt.Errorf("bitsliced DES: output of %d bytes is shorter than the input of %d", len(dst), len(src))
+	}
+
+	const groupSize = BitslicedLanes * DESBlockSize
+	var state [64]uint64
+	for start := 0; start < len(src); start += groupSize {
+		n := min(len(src)-start, groupSize) / DESBlockSize
+
+		clear(state[:])
+		for i := range n {
+			state[i] = binary.BigEndian.Uint64(src[start+i*DESBlockSize:])
+		}
+		transpose64(&state)
+		b.tables.crypt(&state, b.roundKeys, decrypt)
+		transpose64(&state)
+		for i := range n {
+			binary.BigEndian.PutUint64(dst[start+i*DESBlockSize:], state[i])
+		}
+	}
+	clear(state[:])
+	return nil
+}
+
+// EncryptKeys encrypts one plaintext under 64 keys, given with parity bits
+// as big-endian integers, and stores the ciphertext for keys[i] in
+// ciphertexts[i]. It ignores the key set by SetKey; the key schedule is
+// only a choice of key bits, so trying keys in batches like this is much
+// cheaper than calling SetKey for each.
+func (b *BitslicedDES) EncryptKeys(plaintext uint64, keys, ciphertexts *[BitslicedLanes]uint64) {
+	keySlices := *keys
+	transpose64(&keySlices)
+
+	roundKeys := make([][48]uint64, len(b.tables.keyBits))
+	for r, keyBits := range b.tables.keyBits {
+		for k, bit := range keyBits {
+			roundKeys[r][k] = keySlices[bit]
+		}
+	}
+
+	var state [64]uint64
+	for s := range state {
+		state[s] = -(plaintext >> (63 - s) & 1)
+	}
+	b.tables.crypt(&state, roundKeys, false)
+	transpose64(&state)
+
+	*ciphertexts = state
+	clear(keySlices[:])
+	clear(roundKeys)
+}
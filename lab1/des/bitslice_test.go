@@ -0,0 +1,127 @@
+package des
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"lab1/interfaces"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestBitslicedDESMatchesDES(t *testing.T) {
+	random := rand.New(rand.NewChaCha8([32]byte{47}))
+	configs := []Config{{}, {Rounds: 5}, {SBoxes: RandomSBoxes(random)}}
+
+	for _, config := range configs {
+		cipher, err := NewDESWithConfig(config)
+		if err != nil {
+			t.Fatalf("NewDESWithConfig: %v", err)
+		}
+		bitsliced, err := NewBitslicedDESWithConfig(config)
+		if err != nil {
+			t.Fatalf("NewBitslicedDESWithConfig: %v", err)
+		}
+
+		key := make([]byte, DESKeySize)
+		binary.BigEndian.PutUint64(key, random.Uint64())
+		cipher.SetKey(key)
+		if err := bitsliced.SetKey(key); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+
+		// 100 blocks: one full group of 64 and a partial one.
+		src := make([]byte, 100*DESBlockSize)
+		for i := 0; i < len(src); i += 8 {
+			binary.BigEndian.PutUint64(src[i:], random.Uint64())
+		}
+		encrypted := make([]byte, len(src))
+		if err := bitsliced.cryptBlocks(encrypted, src, false); err != nil {
+			t.Fatalf("cryptBlocks: %v", err)
+		}
+		for i := 0; i < len(src); i += DESBlockSize {
+			if want, _ := cipher.Encrypt(src[i : i+8]); !bytes.Equal(encrypted[i:i+8], want) {
+				t.Fatalf("rounds %d: block %d: got %X, want %X", config.Rounds, i/8, encrypted[i:i+8], want)
+			}
+		}
+
+		// In place, back to the plaintext.
+		if err := bitsliced.cryptBlocks(encrypted, encrypted, true); err != nil {
+			t.Fatalf("cryptBlocks: %v", err)
+		}
+		if !bytes.Equal(encrypted, src) {
+			t.Errorf("rounds %d: cryptBlocks did not restore the plaintext", config.Rounds)
+		}
+	}
+}
+
+func TestBitslicedDESVectors(t *testing.T) {
+	cipher, err := NewBitslicedDES()
+	if err != nil {
+		t.Fatalf("NewBitslicedDES: %v", err)
+	}
+	for _, v := range substitutionTableVectors[:8] {
+		if err := cipher.SetKey(decodeHex(t, v.key)); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		encrypted, err := cipher.Encrypt(decodeHex(t, v.plaintext))
+		if err != nil || !bytes.Equal(encrypted, decodeHex(t, v.ciphertext)) {
+			t.Errorf("key %s: got %X, %v; want %s", v.key, encrypted, err, v.ciphertext)
+		}
+		if decrypted, _ := cipher.Decrypt(encrypted); !bytes.Equal(decrypted, decodeHex(t, v.plaintext)) {
+			t.Errorf("key %s: decrypted %X", v.key, decrypted)
+		}
+	}
+}
+
+func TestEncryptKeys(t *testing.T) {
+	random := rand.New(rand.NewChaCha8([32]byte{64}))
+	bitsliced, _ := NewBitslicedDES()
+	cipher, _ := NewDES()
+
+	var keys, ciphertexts [BitslicedLanes]uint64
+	for i := range keys {
+		keys[i] = random.Uint64()
+	}
+	plaintext := random.Uint64()
+	bitsliced.EncryptKeys(plaintext, &keys, &ciphertexts)
+
+	block := binary.BigEndian.AppendUint64(nil, plaintext)
+	for i, key := range keys {
+		cipher.SetKey(binary.BigEndian.AppendUint64(nil, key))
+		want, _ := cipher.Encrypt(block)
+		if ciphertexts[i] != binary.BigEndian.Uint64(want) {
+			t.Errorf("key %016x: got %016x, want %X", key, ciphertexts[i], want)
+		}
+	}
+}
+
+func TestBitslicedDESErrors(t *testing.T) {
+	cipher, _ := NewBitslicedDES()
+	if err := cipher.cryptBlocks(make([]byte, 8), make([]byte, 8), false); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("cryptBlocks without key: got %v, want ErrKeyNotSet", err)
+	}
+	if err := cipher.SetKey(make([]byte, 6)); !errors.Is(err, interfaces.ErrInvalidKeySize) {
+		t.Errorf("6-byte key: got %v, want ErrInvalidKeySize", err)
+	}
+
+	cipher.SetKey(make([]byte, DESKeySize))
+	if err := cipher.cryptBlocks(make([]byte, 16), make([]byte, 12), false); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("12-byte input: got %v, want ErrInvalidBlockSize", err)
+	}
+	if err := cipher.cryptBlocks(make([]byte, 8), make([]byte, 16), true); err == nil {
+		t.Error("cryptBlocks into a short output succeeded")
+	}
+	if _, err := cipher.Encrypt(make([]byte, 16)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Encrypt 16-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+
+	cipher.Destroy()
+	if _, err := cipher.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Encrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+
+	if _, err := NewBitslicedDESWithConfig(Config{Rounds: 17}); err == nil {
+		t.Error("NewBitslicedDESWithConfig accepted 17 rounds")
+	}
+}
@@ -0,0 +1,167 @@
+package des
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Config describes a DES variant. Nil tables and a zero Rounds take the
+// standard DES values.
+type Config struct {
+	// IP is the initial permutation; the final permutation is its inverse.
+	IP          []int
+	Expansion   []int
+	Permutation []int
+	SBoxes      *[8][4][16]byte
+	PC1         []int
+	PC2         []int
+	// Rotations holds the left shift of C and D before each round and
+	// needs at least Rounds entries.
+	Rotations []int
+	Rounds    int
+}
+
+// DefaultConfig returns the standard DES tables. The slices are shared with
+// the package tables, so copy them before changing entries.
+func DefaultConfig() Config {
+	return Config{
+		IP:          IPTable,
+		Expansion:   ExpansionTable,
+		Permutation: PermutationTable,
+		SBoxes:      &SBoxes,
+		PC1:         PC1Table,
+		PC2:         PC2Table,
+		Rotations:   RotationSchedule,
+		Rounds:      DESRounds,
+	}
+}
+
+// NewDESWithConfig compiles a DES variant, e.g. with other S-boxes or more
+// or fewer rounds. Later changes to the config do not affect the cipher.
+func NewDESWithConfig(config Config) (*DES, error) {
+	config = config.withDefaults()
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	keySchedule := &DESKeySchedule{
+		rounds:    config.Rounds,
+		pc1:       clone(config.PC1),
+		pc2:       clone(config.PC2),
+		rotations: clone(config.Rotations[:config.Rounds]),
+	}
+	return &DES{tables: newDESTables(config), schedule: keySchedule}, nil
+}
+
+// RandomSBoxes draws S-boxes whose rows are random permutations of 0..15,
+// like the rows of the DES S-boxes.
+func RandomSBoxes(random *rand.Rand) *[8][4][16]byte {
+	var sboxes [8][4][16]byte
+	for i := range sboxes {
+		for row := range sboxes[i] {
+			for col, value := range random.Perm(16) {
+				sboxes[i][row][col] = byte(value)
+			}
+		}
+	}
+	return &sboxes
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.IP == nil {
+		c.IP = defaults.IP
+	}
+	if c.Expansion == nil {
+		c.Expansion = defaults.Expansion
+	}
+	if c.Permutation == nil {
+		c.Permutation = defaults.Permutation
+	}
+	if c.SBoxes == nil {
+		c.SBoxes = defaults.SBoxes
+	}
+	if c.PC1 == nil {
+		c.PC1 = defaults.PC1
+	}
+	if c.PC2 == nil {
+		c.PC2 = defaults.PC2
+	}
+	if c.Rotations == nil {
+		c.Rotations = defaults.Rotations
+	}
+	if c.Rounds == 0 {
+		c.Rounds = defaults.Rounds
+	}
+	return c
+}
+
+func (c Config) validate() error {
+	tables := []struct {
+		name      string
+		table     []int
+		size, max int
+	}{
+		{"IP", c.IP, 64, 64},
+		{"expansion", c.Expansion, 48, 32},
+		{"permutation", c.Permutation, 32, 32},
+		{"PC1", c.PC1, 56, 64},
+		{"PC2", c.PC2, 48, 56},
+	}
+	for _, t := range tables {
+		if err := checkTable(t.name, t.table, t.size, t.max); err != nil {
+			return err
+		}
+	}
+	if invertPermutation(c.IP) == nil {
+		return fmt.Errorf("DES config: IP is not a permutation of 1..64")
+	}
+
+	if c.Rounds < 1 || c.Rounds > len(c.Rotations) {
+		return fmt.Errorf("DES config: rounds must be between 1 and %d rotations (got %d)", len(c.Rotations), c.Rounds)
+	}
+	for i, shift := range c.Rotations[:c.Rounds] {
+		if shift < 0 || shift >= 28 {
+			return fmt.Errorf("DES config: rotation %d is %d, want 0..27", i, shift)
+		}
+	}
+
+	for i := range c.SBoxes {
+		for row := range c.SBoxes[i] {
+			for col, value := range c.SBoxes[i][row] {
+				if value > 15 {
+					return fmt.Errorf("DES config: S-box %d[%d][%d] is %d, want 0..15", i+1, row, col, value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func checkTable(name string, table []int, size, max int) error {
+	if len(table) != size {
+		return fmt.Errorf("DES config: %s has %d entries, want %d", name, len(table), size)
+	}
+	for i, position := range table {
+		if position < 1 || position > max {
+			return fmt.Errorf("DES config: %s[%d] is %d, want 1..%d", name, i, position, max)
+		}
+	}
+	return nil
+}
+
+// invertPermutation returns nil if the 1-based table is not a bijection.
+func invertPermutation(table []int) []int {
+	inverse := make([]int, len(table))
+	for i, position := range table {
+		if position < 1 || position > len(table) || inverse[position-1] != 0 {
+			return nil
+		}
+		inverse[position-1] = i + 1
+	}
+	return inverse
+}
+
+func clone(table []int) []int {
+	return append([]int(nil), table...)
+}
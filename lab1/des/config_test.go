@@ -0,0 +1,119 @@
+package des
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	cipher, err := NewDESWithConfig(Config{})
+	if err != nil {
+		t.Fatalf("NewDESWithConfig: %v", err)
+	}
+	for _, v := range substitutionTableVectors[:4] {
+		if err := cipher.SetKey(decodeHex(t, v.key)); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		if got, _ := cipher.Encrypt(decodeHex(t, v.plaintext)); !bytes.Equal(got, decodeHex(t, v.ciphertext)) {
+			t.Errorf("key %s: got %X, want %s", v.key, got, v.ciphertext)
+		}
+	}
+
+	// A config's IP is inverted for the final permutation.
+	if fp := invertPermutation(IPTable); !slices.Equal(fp, FPTable) {
+		t.Errorf("inverse of IP is %v", fp)
+	}
+}
+
+func TestConfigRounds(t *testing.T) {
+	key := decodeHex(t, "133457799BBCDFF1")
+	plaintext := decodeHex(t, "0123456789ABCDEF")
+
+	for _, rounds := range []int{1, 4, 8} {
+		reduced, _ := NewReducedDES(rounds)
+		configured, err := NewDESWithConfig(Config{Rounds: rounds})
+		if err != nil {
+			t.Fatalf("NewDESWithConfig(%d rounds): %v", rounds, err)
+		}
+		reduced.SetKey(key)
+		configured.SetKey(key)
+
+		want, _ := reduced.Encrypt(plaintext)
+		if got, _ := configured.Encrypt(plaintext); !bytes.Equal(got, want) {
+			t.Errorf("%d rounds: got %X, want %X", rounds, got, want)
+		}
+	}
+
+	// More rounds than DES need a longer rotation schedule.
+	rotations := append(append([]int(nil), RotationSchedule...), RotationSchedule...)
+	long, err := NewDESWithConfig(Config{Rotations: rotations, Rounds: 32})
+	if err != nil {
+		t.Fatalf("NewDESWithConfig(32 rounds): %v", err)
+	}
+	long.SetKey(key)
+	ciphertext, _ := long.Encrypt(plaintext)
+	if decrypted, _ := long.Decrypt(ciphertext); !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("32 rounds: decrypted %X", decrypted)
+	}
+}
+
+func TestConfigRandomSBoxes(t *testing.T) {
+	source := rand.NewChaCha8([32]byte{45})
+	config := DefaultConfig()
+	config.SBoxes = RandomSBoxes(rand.New(source))
+
+	cipher, err := NewDESWithConfig(config)
+	if err != nil {
+		t.Fatalf("NewDESWithConfig: %v", err)
+	}
+	standard, _ := NewDES()
+
+	key := decodeHex(t, "0123456789ABCDEF")
+	cipher.SetKey(key)
+	standard.SetKey(key)
+
+	// The S-boxes are copied, so later changes do not reach the cipher.
+	before, _ := cipher.Encrypt([]byte("sboxes!!"))
+	config.SBoxes[0][0][0] ^= 1
+	for i := 0; i < 8; i++ {
+		plaintext := make([]byte, DESBlockSize)
+		source.Read(plaintext)
+
+		ciphertext, _ := cipher.Encrypt(plaintext)
+		if reference, _ := standard.Encrypt(plaintext); bytes.Equal(ciphertext, reference) {
+			t.Errorf("random S-boxes encrypt %X like DES", plaintext)
+		}
+		if decrypted, _ := cipher.Decrypt(ciphertext); !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("round trip of %X: %X", plaintext, decrypted)
+		}
+	}
+	if after, _ := cipher.Encrypt([]byte("sboxes!!")); !bytes.Equal(before, after) {
+		t.Error("changing the config changed the cipher")
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	swapped := append([]int(nil), IPTable...)
+	swapped[0] = swapped[1]
+	badSBoxes := SBoxes
+	badSBoxes[7][3][15] = 16
+
+	for name, config := range map[string]Config{
+		"short IP":        {IP: IPTable[:63]},
+		"IP repeats":      {IP: swapped},
+		"expansion range": {Expansion: append(make([]int, 47), 33)},
+		"permutation":     {Permutation: make([]int, 32)},
+		"PC1 size":        {PC1: PC1Table[:48]},
+		"PC2 range":       {PC2: append(append([]int(nil), PC2Table[:47]...), 57)},
+		"S-box value":     {SBoxes: &badSBoxes},
+		"negative rounds": {Rounds: -1},
+		"too many rounds": {Rounds: 17},
+		"rotation":        {Rotations: []int{28}, Rounds: 1},
+	} {
+		if _, err := NewDESWithConfig(config); err == nil {
+			t.Errorf("%s: NewDESWithConfig succeeded", name)
+		}
+	}
+}
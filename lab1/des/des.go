@@ -3,9 +3,8 @@ package des
 // package main
 
 import (
-	"errors"
 	"fmt"
-	"lab1/feistel"
+	"lab1/interfaces"
 	"lab1/permutations"
 )
 
@@ -132,32 +131,6 @@ var PC2Table = []int{
 
 var RotationSchedule = []int{1, 1, 2, 2, 2, 2, 2, 2, 1, 2, 2, 2, 2, 2, 2, 1}
 
-func extract6Bits(data []byte, startBit int) byte {
-	var result byte = 0
-	for i := 0; i < 6; i++ {
-		bitIndex := startBit + i
-		byteIndex := bitIndex / 8
-		bitPos := 7 - (bitIndex % 8)
-		bit := (data[byteIndex] >> bitPos) & 1
-		result = (result << 1) | bit
-	}
-	return result
-}
-
-func set4Bits(data []byte, position int, value byte) {
-	for i := 0; i < 4; i++ {
-		bitIdx := position*4 + i
-		byteIdx := bitIdx / 8
-		bitPos := 7 - (bitIdx % 8)
-
-		if (value & (1 << (3 - i))) != 0 {
-			data[byteIdx] |= (1 << bitPos)
-		} else {
-			data[byteIdx] &^= (1 << bitPos)
-		}
-	}
-}
-
 func extractBits(data []byte, start, n int) []byte {
 	result := make([]byte, (n+7)/8)
 	for i := 0; i < n; i++ {
@@ -226,21 +199,46 @@ func combineBits(a, b []byte, bitsPerPart int) []byte {
 	return result
 }
 
-type DESFFunction struct{}
+type DESFFunction struct {
+	expansion   []int
+	permutation []int
+	sboxes      []*permutations.SBox
+}
 
 func NewDESFFunction() *DESFFunction {
-	return &DESFFunction{}
+	return &DESFFunction{expansion: ExpansionTable, permutation: PermutationTable, sboxes: newSBoxes(&SBoxes)}
+}
+
+// newSBoxes converts S-boxes given as rows and columns, which validated
+// tables always allow.
+func newSBoxes(tables *[8][4][16]byte) []*permutations.SBox {
+	boxes := make([]*permutations.SBox, len(tables))
+	for i, table := range tables {
+		rows := make([][]int, len(table))
+		for row, values := range table {
+			rows[row] = make([]int, len(values))
+			for col, v := range values {
+				rows[row][col] = int(v)
+			}
+		}
+		box, err := permutations.NewSBoxFromRows(4, rows)
+		if err != nil {
+			panic(err)
+		}
+		boxes[i] = box
+	}
+	return boxes
 }
 
 func (df *DESFFunction) Apply(rightHalf []byte, roundKey []byte) ([]byte, error) {
 	if len(rightHalf) != 4 {
-		return nil, errors.New("DES right half must be 4 bytes")
+		return nil, &interfaces.BlockSizeError{Cipher: "DES right half", Size: len(rightHalf), Valid: []int{4}}
 	}
 	if len(roundKey) != 6 {
-		return nil, errors.New("DES round key must be 6 bytes")
+		return nil, &interfaces.KeySizeError{Cipher: "DES round", Size: len(roundKey), Valid: []int{6}}
 	}
 
-	expanded, err := permutations.BitPermutations(rightHalf, ExpansionTable, permutations.HighToLow, permutations.FirstBit)
+	expanded, err := permutations.BitPermutations(rightHalf, df.expansion, permutations.HighToLow, permutations.FirstBit)
 	if err != nil {
 		return nil, fmt.Errorf("expansion failed: %w", err)
 	}
@@ -250,16 +248,11 @@ func (df *DESFFunction) Apply(rightHalf []byte, roundKey []byte) ([]byte, error)
 	}
 
 	substituted := make([]byte, 4)
-	for i := 0; i < 8; i++ {
-		sixBits := extract6Bits(expanded, i*6)
-		row := ((sixBits & 0x20) >> 4) | (sixBits & 0x01)
-		col := (sixBits & 0x1E) >> 1
-
-		value := SBoxes[i][row][col]
-		set4Bits(substituted, i, value)
+	if err := permutations.ApplySBoxes(substituted, expanded, df.sboxes); err != nil {
+		return nil, fmt.Errorf("substitution failed: %w", err)
 	}
 
-	result, err := permutations.BitPermutations(substituted, PermutationTable, permutations.HighToLow, permutations.FirstBit)
+	result, err := permutations.BitPermutations(substituted, df.permutation, permutations.HighToLow, permutations.FirstBit)
 	if err != nil {
 		return nil, fmt.Errorf("permutation failed: %w", err)
 	}
@@ -271,35 +264,66 @@ func (df *DESFFunction) HalfBlockSize() int {
 	return 4
 }
 
-type DESKeySchedule struct{}
+type DESKeySchedule struct {
+	rounds    int
+	policy    KeyPolicy
+	pc1       []int
+	pc2       []int
+	rotations []int
+}
 
 func NewDESKeySchedule() *DESKeySchedule {
-	return &DESKeySchedule{}
+	return &DESKeySchedule{rounds: DESRounds, pc1: PC1Table, pc2: PC2Table, rotations: RotationSchedule}
 }
 
+func NewReducedDESKeySchedule(rounds int) (*DESKeySchedule, error) {
+	if rounds < 1 || rounds > DESRounds {
+		return nil, fmt.Errorf("DES rounds must be between 1 and %d (got %d)", DESRounds, rounds)
+	}
+	return &DESKeySchedule{rounds: rounds, pc1: PC1Table, pc2: PC2Table, rotations: RotationSchedule[:rounds]}, nil
+}
+
+// SetPolicy selects the keys ExpandKey refuses.
+func (dks *DESKeySchedule) SetPolicy(policy KeyPolicy) {
+	dks.policy = policy
+}
+
+// ExpandKey accepts 8-byte keys and 7-byte keys without parity bits.
 func (dks *DESKeySchedule) ExpandKey(key []byte) ([][]byte, error) {
-	if len(key) != DESKeySize {
-		return nil, fmt.Errorf("DES key must be %d bytes", DESKeySize)
+	if err := dks.policy.Check(key); err != nil {
+		return nil, err
+	}
+
+	key, err := normalizeKey(key)
+	if err != nil {
+		return nil, err
 	}
+	defer interfaces.Zeroize(key)
 
-	permutedKey, err := permutations.BitPermutations(key, PC1Table, permutations.HighToLow, permutations.FirstBit)
+	permutedKey, err := permutations.BitPermutations(key, dks.pc1, permutations.HighToLow, permutations.FirstBit)
 	if err != nil {
 		return nil, fmt.Errorf("PC1 failed: %w", err)
 	}
 
 	C := extractBits(permutedKey, 0, 28)
 	D := extractBits(permutedKey, 28, 28)
+	interfaces.Zeroize(permutedKey)
+	defer func() { interfaces.Zeroize(C, D) }()
 
-	roundKeys := make([][]byte, DESRounds)
+	roundKeys := make([][]byte, len(dks.rotations))
 
-	for i := 0; i < DESRounds; i++ {
-		C = rotateLeft28(C, RotationSchedule[i])
-		D = rotateLeft28(D, RotationSchedule[i])
+	for i, shift := range dks.rotations {
+		rotatedC := rotateLeft28(C, shift)
+		rotatedD := rotateLeft28(D, shift)
+		interfaces.Zeroize(C, D)
+		C, D = rotatedC, rotatedD
 
 		combined := combineBits(C, D, 28)
 
-		roundKey, err := permutations.BitPermutations(combined, PC2Table, permutations.HighToLow, permutations.FirstBit)
+		roundKey, err := permutations.BitPermutations(combined, dks.pc2, permutations.HighToLow, permutations.FirstBit)
+		interfaces.Zeroize(combined)
 		if err != nil {
+			interfaces.Zeroize(roundKeys...)
 			return nil, fmt.Errorf("PC2 failed at round %d: %w", i, err)
 		}
 
@@ -310,86 +334,88 @@ func (dks *DESKeySchedule) ExpandKey(key []byte) ([][]byte, error) {
 }
 
 func (dks *DESKeySchedule) NumRounds() int {
-	return DESRounds
+	if dks.rounds == 0 {
+		return DESRounds
+	}
+	return dks.rounds
 }
 
+// DES runs on precomputed tables and uint64 state; DESFFunction and
+// DESKeySchedule remain as the bit-level reference implementation.
 type DES struct {
-	network *feistel.FeistelNetwork
+	tables    *desTables
+	schedule  *DESKeySchedule
+	roundKeys []uint64
+	destroyed bool
 }
 
 func NewDES() (*DES, error) {
-	fFunc := NewDESFFunction()
-	keySchedule := NewDESKeySchedule()
+	return &DES{tables: defaultTables(), schedule: NewDESKeySchedule()}, nil
+}
 
-	network, err := feistel.NewFeistelNetwork(fFunc, keySchedule)
+func NewReducedDES(rounds int) (*DES, error) {
+	keySchedule, err := NewReducedDESKeySchedule(rounds)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DES{network: network}, nil
+	return &DES{tables: defaultTables(), schedule: keySchedule}, nil
 }
 
-func (d *DES) SetKey(key []byte) error {
-	return d.network.SetKey(key)
+// SetKeyPolicy selects the keys SetKey refuses; by default all are accepted.
+func (d *DES) SetKeyPolicy(policy KeyPolicy) {
+	d.schedule.SetPolicy(policy)
 }
 
-func (d *DES) Encrypt(block []byte) ([]byte, error) {
-	if len(block) != DESBlockSize {
-		return nil, fmt.Errorf("DES block must be %d bytes", DESBlockSize)
+// SetKey accepts 8-byte keys and 7-byte keys without parity bits.
+func (d *DES) SetKey(key []byte) error {
+	if d.destroyed {
+		return interfaces.ErrDestroyed
 	}
-
-	permuted, err := permutations.BitPermutations(block, IPTable, permutations.HighToLow, permutations.FirstBit)
-	if err != nil {
-		return nil, fmt.Errorf("IP failed: %w", err)
+	if err := d.schedule.policy.Check(key); err != nil {
+		return err
 	}
 
-	result, err := d.network.Encrypt(permuted)
+	key, err := normalizeKey(key)
 	if err != nil {
-		return nil, fmt.Errorf("feistel encrypt failed: %w", err)
+		return err
 	}
+	defer interfaces.Zeroize(key)
 
-	if len(result) != DESBlockSize {
-		return nil, errors.New("unexpected block size after encryption rounds")
-	}
-	swapped := make([]byte, DESBlockSize)
-	copy(swapped[:4], result[4:])
-	copy(swapped[4:], result[:4])
+	roundKeys := d.tables.expandKey(key, d.schedule.NumRounds())
+	clear(d.roundKeys)
+	d.roundKeys = roundKeys
+	return nil
+}
 
-	final, err := permutations.BitPermutations(swapped, FPTable, permutations.HighToLow, permutations.FirstBit)
-	if err != nil {
-		return nil, fmt.Errorf("FP failed: %w", err)
-	}
+func (d *DES) Destroy() {
+	clear(d.roundKeys)
+	d.roundKeys = nil
+	d.destroyed = true
+}
 
-	return final, nil
+func (d *DES) Encrypt(block []byte) ([]byte, error) {
+	return d.crypt(block, false)
 }
 
 func (d *DES) Decrypt(block []byte) ([]byte, error) {
-	if len(block) != DESBlockSize {
-		return nil, fmt.Errorf("DES block must be %d bytes", DESBlockSize)
-	}
-
-	permuted, err := permutations.BitPermutations(block, IPTable, permutations.HighToLow, permutations.FirstBit)
-	if err != nil {
-		return nil, fmt.Errorf("IP failed: %w", err)
-	}
+	return d.crypt(block, true)
+}
 
-	if len(permuted) != DESBlockSize {
-		return nil, errors.New("unexpected block size after initial permutation")
+func (d *DES) crypt(block []byte, decrypt bool) ([]byte, error) {
+	if d.destroyed {
+		return nil, interfaces.ErrDestroyed
 	}
-	swapped := make([]byte, DESBlockSize)
-	copy(swapped[:4], permuted[4:])
-	copy(swapped[4:], permuted[:4])
-	result, err := d.network.Decrypt(swapped)
-	if err != nil {
-		return nil, fmt.Errorf("feistel decrypt failed: %w", err)
+	if len(block) != DESBlockSize {
+		return nil, &interfaces.BlockSizeError{Cipher: "DES", Size: len(block), Valid: []int{DESBlockSize}}
 	}
-
-	final, err := permutations.BitPermutations(result, FPTable, permutations.HighToLow, permutations.FirstBit)
-	if err != nil {
-		return nil, fmt.Errorf("FP failed: %w", err)
+	if len(d.roundKeys) == 0 {
+		return nil, fmt.Errorf("round keys not set: %w", interfaces.ErrKeyNotSet)
 	}
 
-	return final, nil
+	result := make([]byte, DESBlockSize)
+	d.tables.crypt(result, block, d.roundKeys, decrypt)
+	return result, nil
 }
 
 func (d *DES) BlockSize() int {
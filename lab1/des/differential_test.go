@@ -0,0 +1,56 @@
+package des
+
+import (
+	"crypto/des"
+	"lab1/internal/differential"
+	"testing"
+)
+
+func FuzzDESBlockDifferential(f *testing.F) {
+	f.Add([]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}, []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF})
+	f.Add(make([]byte, DESKeySize), make([]byte, DESBlockSize))
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+
+	cipher, err := NewDES()
+	if err != nil {
+		f.Fatalf("NewDES: %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, key, block []byte) {
+		key, block = differential.Fit(key, DESKeySize), differential.Fit(block, DESBlockSize)
+
+		reference, err := des.NewCipher(key)
+		if err != nil {
+			t.Fatalf("crypto/des: %v", err)
+		}
+
+		if err := differential.CompareBlock(cipher, reference, key, block); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzDESModesDifferential(f *testing.F) {
+	f.Add([]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, []byte{0xFE, 0xDC, 0xBA, 0x98, 0x76, 0x54, 0x32, 0x10}, []byte("Now is the time for all "))
+	f.Add(make([]byte, DESKeySize), make([]byte, DESBlockSize), []byte{})
+	f.Add([]byte{0x0E, 0x32, 0x92, 0x32, 0xEA, 0x6D, 0x0D, 0x73}, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, []byte("odd length input"))
+
+	cipher, err := NewDES()
+	if err != nil {
+		f.Fatalf("NewDES: %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, key, iv, plaintext []byte) {
+		key, iv = differential.Fit(key, DESKeySize), differential.Fit(iv, DESBlockSize)
+		plaintext = plaintext[:min(len(plaintext), 256)]
+
+		reference, err := des.NewCipher(key)
+		if err != nil {
+			t.Fatalf("crypto/des: %v", err)
+		}
+
+		if err := differential.CompareModes(cipher, reference, key, iv, plaintext); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package des
+
+import (
+	"bytes"
+	"errors"
+	"lab1/feistel"
+	"lab1/interfaces"
+	"testing"
+)
+
+func TestDESErrors(t *testing.T) {
+	cipher, err := NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+
+	if _, err := cipher.Encrypt(make([]byte, DESBlockSize)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Encrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, DESBlockSize)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Decrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+
+	var keyErr *interfaces.KeySizeError
+	if err := cipher.SetKey(make([]byte, 6)); !errors.As(err, &keyErr) || keyErr.Size != 6 {
+		t.Errorf("6-byte key: got %v, want KeySizeError with size 6", err)
+	}
+
+	if err := cipher.SetKey(make([]byte, DESKeySize)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	var blockErr *interfaces.BlockSizeError
+	if _, err := cipher.Encrypt(make([]byte, 9)); !errors.As(err, &blockErr) || blockErr.Size != 9 {
+		t.Errorf("Encrypt 9-byte block: got %v, want BlockSizeError with size 9", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, 4)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Decrypt 4-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+
+	f := NewDESFFunction()
+	if _, err := f.Apply(make([]byte, 3), make([]byte, 6)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Apply 3-byte half: got %v, want ErrInvalidBlockSize", err)
+	}
+	if _, err := f.Apply(make([]byte, 4), make([]byte, 8)); !errors.Is(err, interfaces.ErrInvalidKeySize) {
+		t.Errorf("Apply 8-byte round key: got %v, want ErrInvalidKeySize", err)
+	}
+}
+
+type capturingKeySchedule struct {
+	*DESKeySchedule
+	roundKeys [][]byte
+}
+
+func (cks *capturingKeySchedule) ExpandKey(key []byte) ([][]byte, error) {
+	roundKeys, err := cks.DESKeySchedule.ExpandKey(key)
+	cks.roundKeys = roundKeys
+	return roundKeys, err
+}
+
+func TestDESDestroy(t *testing.T) {
+	schedule := &capturingKeySchedule{DESKeySchedule: NewDESKeySchedule()}
+	network, err := feistel.NewFeistelNetwork(NewDESFFunction(), schedule)
+	if err != nil {
+		t.Fatalf("NewFeistelNetwork: %v", err)
+	}
+
+	if err := network.SetKey([]byte("8bytekey")); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	first := schedule.roundKeys
+
+	if err := network.SetKey([]byte("otherkey")); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	second := schedule.roundKeys
+
+	network.Destroy()
+
+	for name, roundKeys := range map[string][][]byte{"replaced": first, "destroyed": second} {
+		for i, roundKey := range roundKeys {
+			if !bytes.Equal(roundKey, make([]byte, len(roundKey))) {
+				t.Errorf("%s round key %d not wiped: %x", name, i, roundKey)
+			}
+		}
+	}
+
+	cipher, err := NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+	if err := cipher.SetKey([]byte("8bytekey")); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	cipher.Destroy()
+
+	if _, err := cipher.Encrypt(make([]byte, DESBlockSize)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Encrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, DESBlockSize)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Decrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if err := cipher.SetKey([]byte("8bytekey")); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("SetKey after Destroy: got %v, want ErrDestroyed", err)
+	}
+}
@@ -0,0 +1,121 @@
+package des
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// byteTables maps each input byte of a bit permutation to its contribution
+// to the output, so a permutation costs one lookup per input byte.
+type byteTables [][256]uint64
+
+// newByteTables compiles a 1-based, high-to-low permutation table over
+// inputBits bits. Output bit j lands at bit len(table)-1-j.
+func newByteTables(table []int, inputBits int) byteTables {
+	masks := make([]uint64, inputBits)
+	for j, position := range table {
+		masks[position-1] |= 1 << (len(table) - 1 - j)
+	}
+
+	tables := make(byteTables, inputBits/8)
+	for i := range tables {
+		for value := range 256 {
+			var out uint64
+			for bit := 0; bit < 8; bit++ {
+				if value>>(7-bit)&1 != 0 {
+					out |= masks[8*i+bit]
+				}
+			}
+			tables[i][value] = out
+		}
+	}
+	return tables
+}
+
+// apply permutes the low 8*len(t) bits of v.
+func (t byteTables) apply(v uint64) uint64 {
+	var out uint64
+	shift := 8 * (len(t) - 1)
+	for i := range t {
+		out |= t[i][byte(v>>shift)]
+		shift -= 8
+	}
+	return out
+}
+
+// desTables holds a DES variant compiled for uint64 state: IP and FP, the
+// expansion, the S-boxes merged with P, and the key schedule permutations.
+type desTables struct {
+	ip, fp    byteTables
+	expansion byteTables
+	sp        [8][64]uint32
+	pc1, pc2  byteTables
+	rotations []int
+}
+
+var defaultTables = sync.OnceValue(func() *desTables {
+	return newDESTables(DefaultConfig())
+})
+
+func newDESTables(config Config) *desTables {
+	t := &desTables{
+		ip:        newByteTables(config.IP, 64),
+		fp:        newByteTables(invertPermutation(config.IP), 64),
+		expansion: newByteTables(config.Expansion, 32),
+		pc1:       newByteTables(config.PC1, 64),
+		pc2:       newByteTables(config.PC2, 56),
+		rotations: clone(config.Rotations),
+	}
+
+	permutation := newByteTables(config.Permutation, 32)
+	for i := range t.sp {
+		for x := range 64 {
+			row := x>>4&2 | x&1
+			col := x >> 1 & 0xF
+			value := uint64(config.SBoxes[i][row][col]) << (28 - 4*i)
+			t.sp[i][x] = uint32(permutation.apply(value))
+		}
+	}
+	return t
+}
+
+func (t *desTables) feistel(right uint32, roundKey uint64) uint32 {
+	x := t.expansion.apply(uint64(right)) ^ roundKey
+	return t.sp[0][x>>42&0x3F] | t.sp[1][x>>36&0x3F] |
+		t.sp[2][x>>30&0x3F] | t.sp[3][x>>24&0x3F] |
+		t.sp[4][x>>18&0x3F] | t.sp[5][x>>12&0x3F] |
+		t.sp[6][x>>6&0x3F] | t.sp[7][x&0x3F]
+}
+
+// crypt runs IP, the rounds with the given keys in order, the final swap
+// and FP. Decryption passes the round keys reversed.
+func (t *desTables) crypt(dst, src []byte, roundKeys []uint64, decrypt bool) {
+	block := t.ip.apply(binary.BigEndian.Uint64(src))
+	left, right := uint32(block>>32), uint32(block)
+
+	n := len(roundKeys)
+	for i := range n {
+		k := roundKeys[i]
+		if decrypt {
+			k = roundKeys[n-1-i]
+		}
+		left, right = right, left^t.feistel(right, k)
+	}
+
+	binary.BigEndian.PutUint64(dst, t.fp.apply(uint64(right)<<32|uint64(left)))
+}
+
+// expandKey derives rounds 48-bit round keys from a normalized 8-byte key.
+func (t *desTables) expandKey(key []byte, rounds int) []uint64 {
+	cd := t.pc1.apply(binary.BigEndian.Uint64(key))
+	c, d := uint32(cd>>28), uint32(cd&0x0FFFFFFF)
+
+	roundKeys := make([]uint64, rounds)
+	for i := range roundKeys {
+		shift := t.rotations[i]
+		c = (c<<shift | c>>(28-shift)) & 0x0FFFFFFF
+		d = (d<<shift | d>>(28-shift)) & 0x0FFFFFFF
+		roundKeys[i] = t.pc2.apply(uint64(c)<<28 | uint64(d))
+	}
+	return roundKeys
+}
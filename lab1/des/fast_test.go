@@ -0,0 +1,129 @@
+package des
+
+import (
+	"bytes"
+	"lab1/feistel"
+	"lab1/permutations"
+	"math/rand/v2"
+	"testing"
+)
+
+// referenceDES is the bit-level implementation DES used before the tables:
+// DESFFunction and DESKeySchedule in a Feistel network between IP and FP.
+type referenceDES struct {
+	network *feistel.FeistelNetwork
+	ip, fp  []int
+}
+
+func newReferenceDES(t testing.TB, config Config) *referenceDES {
+	t.Helper()
+
+	config = config.withDefaults()
+	fFunc := &DESFFunction{expansion: config.Expansion, permutation: config.Permutation, sboxes: newSBoxes(config.SBoxes)}
+	keySchedule := &DESKeySchedule{rounds: config.Rounds, pc1: config.PC1, pc2: config.PC2, rotations: config.Rotations}
+	network, err := feistel.NewFeistelNetwork(fFunc, keySchedule)
+	if err != nil {
+		t.Fatalf("NewFeistelNetwork: %v", err)
+	}
+	return &referenceDES{network: network, ip: config.IP, fp: invertPermutation(config.IP)}
+}
+
+func (r *referenceDES) crypt(t testing.TB, block []byte, decrypt bool) []byte {
+	t.Helper()
+
+	permuted, err := permutations.BitPermutations(block, r.ip, permutations.HighToLow, permutations.FirstBit)
+	if err != nil {
+		t.Fatalf("IP: %v", err)
+	}
+	swap := func(b []byte) []byte { return append(append([]byte(nil), b[4:]...), b[:4]...) }
+
+	var result []byte
+	if decrypt {
+		result, err = r.network.Decrypt(swap(permuted))
+	} else {
+		result, err = r.network.Encrypt(permuted)
+		result = swap(result)
+	}
+	if err != nil {
+		t.Fatalf("network: %v", err)
+	}
+
+	final, err := permutations.BitPermutations(result, r.fp, permutations.HighToLow, permutations.FirstBit)
+	if err != nil {
+		t.Fatalf("FP: %v", err)
+	}
+	return final
+}
+
+func TestDESMatchesReference(t *testing.T) {
+	random := rand.New(rand.NewChaCha8([32]byte{46}))
+	shuffled := append([]int(nil), IPTable...)
+	random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	configs := map[string]Config{
+		"DES":            {},
+		"5 rounds":       {Rounds: 5},
+		"random S-boxes": {SBoxes: RandomSBoxes(random)},
+		"random IP":      {IP: shuffled, Rotations: []int{3, 0, 27}, Rounds: 3},
+	}
+	for name, config := range configs {
+		cipher, err := NewDESWithConfig(config)
+		if err != nil {
+			t.Fatalf("%s: NewDESWithConfig: %v", name, err)
+		}
+		reference := newReferenceDES(t, config)
+
+		for i := 0; i < 32; i++ {
+			key := make([]byte, DESKeySize)
+			block := make([]byte, DESBlockSize)
+			for j := range key {
+				key[j], block[j] = byte(random.Uint32()), byte(random.Uint32())
+			}
+			if err := cipher.SetKey(key); err != nil {
+				t.Fatalf("SetKey: %v", err)
+			}
+			if err := reference.network.SetKey(key); err != nil {
+				t.Fatalf("reference SetKey: %v", err)
+			}
+
+			for _, decrypt := range []bool{false, true} {
+				var got []byte
+				if decrypt {
+					got, err = cipher.Decrypt(block)
+				} else {
+					got, err = cipher.Encrypt(block)
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if want := reference.crypt(t, block, decrypt); !bytes.Equal(got, want) {
+					t.Errorf("%s: key %X block %X decrypt %v: got %X, want %X", name, key, block, decrypt, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestExpandKeyMatchesReference(t *testing.T) {
+	random := rand.New(rand.NewChaCha8([32]byte{47}))
+	for i := 0; i < 32; i++ {
+		key := make([]byte, DESKeySize)
+		for j := range key {
+			key[j] = byte(random.Uint32())
+		}
+
+		roundKeys, err := NewDESKeySchedule().ExpandKey(key)
+		if err != nil {
+			t.Fatalf("ExpandKey: %v", err)
+		}
+		for round, k := range defaultTables().expandKey(key, DESRounds) {
+			var want uint64
+			for _, b := range roundKeys[round] {
+				want = want<<8 | uint64(b)
+			}
+			if k != want {
+				t.Errorf("key %X round %d: got %012x, want %012x", key, round, k, want)
+			}
+		}
+	}
+}
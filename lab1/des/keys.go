@@ -0,0 +1,240 @@
+package des
+
+import (
+	"errors"
+	"fmt"
+	"lab1/interfaces"
+	"math/bits"
+)
+
+const (
+	// DESKey56Size is the size of a key given without parity bits.
+	DESKey56Size = 7
+	// ParityBits are the low bits of every key byte, which DES ignores.
+	ParityBits = 0x01010101_01010101
+)
+
+var (
+	ErrWeakKey   = errors.New("weak DES key")
+	ErrKeyParity = errors.New("DES key parity error")
+)
+
+// KeyClass ranks keys by how badly the key schedule treats them.
+type KeyClass int
+
+const (
+	NormalKey KeyClass = iota
+	// PossiblyWeakKey keys yield only four distinct round keys. This is
+	// wider than the 48 keys usually published: every key whose halves both
+	// repeat with period 4 counts, 240 in all up to parity.
+	PossiblyWeakKey
+	// SemiWeakKey keys come in pairs: encrypting with one decrypts with the other.
+	SemiWeakKey
+	// WeakKey keys make encryption an involution: all round keys are equal.
+	WeakKey
+)
+
+func (c KeyClass) String() string {
+	switch c {
+	case NormalKey:
+		return "normal"
+	case PossiblyWeakKey:
+		return "possibly weak"
+	case SemiWeakKey:
+		return "semi-weak"
+	case WeakKey:
+		return "weak"
+	default:
+		return fmt.Sprintf("KeyClass(%d)", int(c))
+	}
+}
+
+// KeyPolicy selects the keys SetKey refuses. The zero value accepts every key.
+type KeyPolicy struct {
+	// RejectWeak refuses weak and semi-weak keys.
+	RejectWeak         bool
+	RejectPossiblyWeak bool
+	// RequireParity refuses 8-byte keys whose bytes do not have odd parity.
+	RequireParity bool
+	// RejectDegenerate applies to TripleDES: it refuses K1 == K2 and
+	// K2 == K3, which reduce EDE to single DES.
+	RejectDegenerate bool
+}
+
+var StrictKeyPolicy = KeyPolicy{
+	RejectWeak:         true,
+	RejectPossiblyWeak: true,
+	RequireParity:      true,
+	RejectDegenerate:   true,
+}
+
+// Check applies the policy to a 7- or 8-byte key.
+func (p KeyPolicy) Check(key []byte) error {
+	if p.RequireParity && len(key) == DESKeySize {
+		if err := CheckParity(key); err != nil {
+			return err
+		}
+	}
+
+	if !p.RejectWeak && !p.RejectPossiblyWeak {
+		return nil
+	}
+
+	class, err := ClassifyKey(key)
+	if err != nil {
+		return err
+	}
+	switch {
+	case class >= SemiWeakKey && p.RejectWeak,
+		class == PossiblyWeakKey && p.RejectPossiblyWeak:
+		return fmt.Errorf("%s key rejected: %w", class, ErrWeakKey)
+	}
+	return nil
+}
+
+// ClassifyKey looks at the two 28-bit halves C and D that PC1 selects: a
+// half that repeats with period 1, 2 or 4 is unchanged or cycles through
+// few values under the rotations of the key schedule. Up to parity there
+// are 4 weak, 12 semi-weak and 240 possibly weak keys.
+func ClassifyKey(key []byte) (KeyClass, error) {
+	key, err := normalizeKey(key)
+	if err != nil {
+		return NormalKey, err
+	}
+	defer interfaces.Zeroize(key)
+
+	var value uint64
+	for _, b := range key {
+		value = value<<8 | uint64(b)
+	}
+
+	var cd uint64
+	for _, position := range PC1Table {
+		cd = cd<<1 | value>>(64-position)&1
+	}
+	c, d := uint32(cd>>28), uint32(cd&0x0FFFFFFF)
+
+	switch max(halfPeriod(c), halfPeriod(d)) {
+	case 1:
+		return WeakKey, nil
+	case 2:
+		return SemiWeakKey, nil
+	case 4:
+		return PossiblyWeakKey, nil
+	}
+	return NormalKey, nil
+}
+
+func IsWeakKey(key []byte) bool {
+	class, err := ClassifyKey(key)
+	return err == nil && class == WeakKey
+}
+
+func IsSemiWeakKey(key []byte) bool {
+	class, err := ClassifyKey(key)
+	return err == nil && class == SemiWeakKey
+}
+
+func IsPossiblyWeakKey(key []byte) bool {
+	class, err := ClassifyKey(key)
+	return err == nil && class == PossiblyWeakKey
+}
+
+// halfPeriod returns the smallest of 1, 2 and 4 that the 28-bit half
+// repeats with, or 28.
+func halfPeriod(half uint32) int {
+	for _, period := range []int{1, 2, 4} {
+		rotated := (half<<period | half>>(28-period)) & 0x0FFFFFFF
+		if rotated == half {
+			return period
+		}
+	}
+	return 28
+}
+
+// CheckParity reports the first byte of an 8-byte key without odd parity.
+func CheckParity(key []byte) error {
+	if len(key) != DESKeySize {
+		return &interfaces.KeySizeError{Cipher: "DES", Size: len(key), Valid: []int{DESKeySize}}
+	}
+	for i, b := range key {
+		if bits.OnesCount8(b)%2 == 0 {
+			return fmt.Errorf("byte %d: %w", i, ErrKeyParity)
+		}
+	}
+	return nil
+}
+
+// FixParity returns a copy of the key with the low bit of every byte set
+// for odd parity.
+func FixParity(key []byte) []byte {
+	fixed := make([]byte, len(key))
+	for i, b := range key {
+		fixed[i] = withParity(b)
+	}
+	return fixed
+}
+
+// SetParity is FixParity for a key held in a uint64.
+func SetParity(key uint64) uint64 {
+	for shift := 0; shift < 64; shift += 8 {
+		key = key&^(0xFF<<shift) | uint64(withParity(byte(key>>shift)))<<shift
+	}
+	return key
+}
+
+// CandidateKey numbers the keys of a search: it spreads the bits of index
+// over the unknown positions, from the least significant up, adds the
+// known bits and sets the parity bits.
+func CandidateKey(known, unknown, index uint64) uint64 {
+	key := known
+	for unknown != 0 {
+		low := unknown & -unknown
+		if index&1 == 1 {
+			key |= low
+		}
+		index >>= 1
+		unknown ^= low
+	}
+	return SetParity(key)
+}
+
+// ExpandKey56 spreads a 7-byte key over the high seven bits of eight bytes
+// and sets the parity bits.
+func ExpandKey56(key []byte) ([]byte, error) {
+	if len(key) != DESKey56Size {
+		return nil, &interfaces.KeySizeError{Cipher: "DES", Size: len(key), Valid: []int{DESKey56Size}}
+	}
+
+	var value uint64
+	for _, b := range key {
+		value = value<<8 | uint64(b)
+	}
+
+	expanded := make([]byte, DESKeySize)
+	for i := range expanded {
+		expanded[i] = withParity(byte(value>>(49-7*i)) << 1)
+	}
+	return expanded, nil
+}
+
+func withParity(b byte) byte {
+	b &^= 1
+	if bits.OnesCount8(b)%2 == 0 {
+		b |= 1
+	}
+	return b
+}
+
+// normalizeKey returns a fresh 8-byte copy of a 7- or 8-byte key, which
+// the caller zeroizes.
+func normalizeKey(key []byte) ([]byte, error) {
+	switch len(key) {
+	case DESKey56Size:
+		return ExpandKey56(key)
+	case DESKeySize:
+		return append([]byte(nil), key...), nil
+	default:
+		return nil, &interfaces.KeySizeError{Cipher: "DES", Size: len(key), Valid: []int{DESKey56Size, DESKeySize}}
+	}
+}
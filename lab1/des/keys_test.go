@@ -0,0 +1,252 @@
+package des
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+var weakKeys = []string{
+	"0101010101010101", "FEFEFEFEFEFEFEFE", "E0E0E0E0F1F1F1F1", "1F1F1F1F0E0E0E0E",
+}
+
+// Semi-weak keys in pairs: encryption with one is decryption with the other.
+var semiWeakKeyPairs = [][2]string{
+	{"011F011F010E010E", "1F011F010E010E01"},
+	{"01E001E001F101F1", "E001E001F101F101"},
+	{"01FE01FE01FE01FE", "FE01FE01FE01FE01"},
+	{"1FE01FE00EF10EF1", "E01FE01FF10EF10E"},
+	{"1FFE1FFE0EFE0EFE", "FE1FFE1FFE0EFE0E"},
+	{"E0FEE0FEF1FEF1FE", "FEE0FEE0FEF1FEF1"},
+}
+
+var possiblyWeakKeys = []string{
+	"01011F1F01010E0E", "1F1F01010E0E0101", "E0E01F1FF1F10E0E", "FEFE1F1FFEFE0E0E",
+}
+
+func TestClassifyKey(t *testing.T) {
+	for _, k := range weakKeys {
+		if class, err := ClassifyKey(mustHex(t, k)); err != nil || class != WeakKey {
+			t.Errorf("%s: class %v, %v; want weak", k, class, err)
+		}
+	}
+	for _, pair := range semiWeakKeyPairs {
+		for _, k := range pair {
+			if !IsSemiWeakKey(mustHex(t, k)) {
+				t.Errorf("%s is not semi-weak", k)
+			}
+		}
+	}
+	for _, k := range possiblyWeakKeys {
+		if !IsPossiblyWeakKey(mustHex(t, k)) {
+			t.Errorf("%s is not possibly weak", k)
+		}
+
+		roundKeys, err := NewDESKeySchedule().ExpandKey(mustHex(t, k))
+		if err != nil {
+			t.Fatalf("ExpandKey: %v", err)
+		}
+		distinct := make(map[string]bool)
+		for _, roundKey := range roundKeys {
+			distinct[string(roundKey)] = true
+		}
+		if len(distinct) > 4 {
+			t.Errorf("%s: %d distinct round keys", k, len(distinct))
+		}
+	}
+
+	for _, k := range []string{"133457799BBCDFF1", "0123456789ABCDEF"} {
+		if class, _ := ClassifyKey(mustHex(t, k)); class != NormalKey || IsWeakKey(mustHex(t, k)) {
+			t.Errorf("%s: class %v, want normal", k, class)
+		}
+	}
+
+	// Parity bits do not change the class, and 7-byte keys are classified too.
+	if !IsWeakKey(mustHex(t, "0000000000000000")) || !IsWeakKey(make([]byte, DESKey56Size)) {
+		t.Error("all-zero keys are not weak")
+	}
+}
+
+func TestWeakKeyCounts(t *testing.T) {
+	// A half repeats with period 4 exactly when it is a nibble repeated, so
+	// the 16 nibbles for C and D give every such key; build them by
+	// inverting PC1.
+	schedule := NewDESKeySchedule()
+	counts := make(map[KeyClass]int)
+	for c := uint32(0); c < 16; c++ {
+		for d := uint32(0); d < 16; d++ {
+			cd := uint64(repeatNibble(c))<<28 | uint64(repeatNibble(d))
+			key := keyFromCD(cd)
+			class, err := ClassifyKey(key)
+			if err != nil {
+				t.Fatalf("ClassifyKey: %v", err)
+			}
+			counts[class]++
+
+			roundKeys, err := schedule.ExpandKey(key)
+			if err != nil {
+				t.Fatalf("ExpandKey: %v", err)
+			}
+			distinct := make(map[string]bool)
+			for _, roundKey := range roundKeys {
+				distinct[string(roundKey)] = true
+			}
+			if len(distinct) > 4 {
+				t.Errorf("%X (%v): %d distinct round keys", key, class, len(distinct))
+			}
+		}
+	}
+	if counts[WeakKey] != 4 || counts[SemiWeakKey] != 12 || counts[PossiblyWeakKey] != 240 {
+		t.Errorf("counts %v, want 4 weak, 12 semi-weak, 240 possibly weak", counts)
+	}
+}
+
+func repeatNibble(n uint32) uint32 {
+	var half uint32
+	for i := 0; i < 7; i++ {
+		half = half<<4 | n
+	}
+	return half
+}
+
+// keyFromCD inverts PC1, setting parity bits.
+func keyFromCD(cd uint64) []byte {
+	var key uint64
+	for i, position := range PC1Table {
+		key |= (cd >> (55 - i) & 1) << (64 - position)
+	}
+	b := make([]byte, DESKeySize)
+	for i := range b {
+		b[i] = byte(key >> (56 - 8*i))
+	}
+	return FixParity(b)
+}
+
+func TestSemiWeakKeyPairs(t *testing.T) {
+	plaintext := []byte("weak key")
+	for _, pair := range semiWeakKeyPairs {
+		first, _ := NewDES()
+		second, _ := NewDES()
+		first.SetKey(mustHex(t, pair[0]))
+		second.SetKey(mustHex(t, pair[1]))
+
+		ciphertext, _ := first.Encrypt(plaintext)
+		if result, _ := second.Encrypt(ciphertext); !bytes.Equal(result, plaintext) {
+			t.Errorf("%s/%s: E2(E1(p)) = %X, want %X", pair[0], pair[1], result, plaintext)
+		}
+	}
+}
+
+func TestParity(t *testing.T) {
+	key := mustHex(t, "133457799BBCDFF1")
+	if err := CheckParity(key); err != nil {
+		t.Errorf("CheckParity(%X): %v", key, err)
+	}
+
+	broken := mustHex(t, "123457799BBCDFF1")
+	if err := CheckParity(broken); !errors.Is(err, ErrKeyParity) {
+		t.Errorf("CheckParity(%X): got %v, want ErrKeyParity", broken, err)
+	}
+	if fixed := FixParity(broken); !bytes.Equal(fixed, key) || CheckParity(fixed) != nil {
+		t.Errorf("FixParity(%X) = %X, want %X", broken, fixed, key)
+	}
+
+	if err := CheckParity(make([]byte, 7)); err == nil {
+		t.Error("CheckParity accepted a 7-byte key")
+	}
+
+	if got := SetParity(0x12345779_9BBCDFF1); got != 0x13345779_9BBCDFF1 {
+		t.Errorf("SetParity = %016x", got)
+	}
+	if got := SetParity(0); got != ParityBits {
+		t.Errorf("SetParity(0) = %016x", got)
+	}
+}
+
+func TestCandidateKey(t *testing.T) {
+	if got, want := CandidateKey(0, 0xFE00, 0x7F), SetParity(0xFE00); got != want {
+		t.Errorf("CandidateKey = %016x, want %016x", got, want)
+	}
+	if got, want := CandidateKey(0x10000000_00000000, 0x0E00, 0b101), SetParity(0x10000000_00000A00); got != want {
+		t.Errorf("CandidateKey = %016x, want %016x", got, want)
+	}
+}
+
+func TestKey56(t *testing.T) {
+	// The 56 key bits of 133457799BBCDFF1 without the parity bits.
+	key56 := mustHex(t, "12695BC9B7B7F8")
+	key := mustHex(t, "133457799BBCDFF1")
+
+	expanded, err := ExpandKey56(key56)
+	if err != nil {
+		t.Fatalf("ExpandKey56: %v", err)
+	}
+	if !bytes.Equal(expanded, key) {
+		t.Errorf("ExpandKey56(%X) = %X, want %X", key56, expanded, key)
+	}
+
+	short, _ := NewDES()
+	full, _ := NewDES()
+	if err := short.SetKey(key56); err != nil {
+		t.Fatalf("SetKey 7 bytes: %v", err)
+	}
+	full.SetKey(key)
+
+	plaintext := mustHex(t, "0123456789ABCDEF")
+	a, _ := short.Encrypt(plaintext)
+	b, _ := full.Encrypt(plaintext)
+	if !bytes.Equal(a, b) || !bytes.Equal(a, mustHex(t, "85E813540F0AB405")) {
+		t.Errorf("7-byte key: %X, 8-byte key: %X", a, b)
+	}
+}
+
+func TestKeyPolicy(t *testing.T) {
+	cipher, _ := NewDES()
+
+	// The default policy accepts everything.
+	for _, k := range []string{weakKeys[0], semiWeakKeyPairs[0][0], possiblyWeakKeys[0], "123457799BBCDFF1"} {
+		if err := cipher.SetKey(mustHex(t, k)); err != nil {
+			t.Errorf("default policy rejected %s: %v", k, err)
+		}
+	}
+
+	cipher.SetKeyPolicy(KeyPolicy{RejectWeak: true})
+	for _, k := range []string{weakKeys[1], semiWeakKeyPairs[1][1]} {
+		if err := cipher.SetKey(mustHex(t, k)); !errors.Is(err, ErrWeakKey) {
+			t.Errorf("RejectWeak: %s: got %v, want ErrWeakKey", k, err)
+		}
+	}
+	if err := cipher.SetKey(mustHex(t, possiblyWeakKeys[1])); err != nil {
+		t.Errorf("RejectWeak rejected possibly weak key: %v", err)
+	}
+
+	cipher.SetKeyPolicy(StrictKeyPolicy)
+	if err := cipher.SetKey(mustHex(t, possiblyWeakKeys[1])); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("strict: possibly weak key: got %v, want ErrWeakKey", err)
+	}
+	if err := cipher.SetKey(mustHex(t, "123457799BBCDFF1")); !errors.Is(err, ErrKeyParity) {
+		t.Errorf("strict: bad parity: got %v, want ErrKeyParity", err)
+	}
+	if err := cipher.SetKey(make([]byte, DESKey56Size)); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("strict: 7-byte weak key: got %v, want ErrWeakKey", err)
+	}
+	if err := cipher.SetKey(mustHex(t, "133457799BBCDFF1")); err != nil {
+		t.Errorf("strict: normal key: %v", err)
+	}
+}
+
+func TestKeyClassString(t *testing.T) {
+	if WeakKey.String() != "weak" || KeyClass(9).String() != "KeyClass(9)" {
+		t.Errorf("String: %q, %q", WeakKey, KeyClass(9))
+	}
+}
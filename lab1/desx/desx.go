@@ -0,0 +1,169 @@
+package desx
+
+import (
+	"errors"
+	"fmt"
+	"lab1/des"
+	"lab1/interfaces"
+)
+
+// DESXKeySize is the DES key followed by the pre- and post-whitening keys.
+const DESXKeySize = 3 * des.DESKeySize
+
+// WhitenedCipher is the FX construction C = K2 ^ E_K(P ^ K1). Its key is
+// the inner cipher's key followed by K1 and K2, one block each.
+type WhitenedCipher struct {
+	cipher    interfaces.BlockCipher
+	pre       []byte
+	post      []byte
+	destroyed bool
+}
+
+// Whitened wraps any block cipher, e.g. DEAL or Rijndael, in key whitening.
+func Whitened(cipher interfaces.BlockCipher) (*WhitenedCipher, error) {
+	if cipher == nil {
+		return nil, errors.New("cipher cannot be nil")
+	}
+	return &WhitenedCipher{cipher: cipher}, nil
+}
+
+func (w *WhitenedCipher) SetKey(key []byte) error {
+	if w.destroyed {
+		return interfaces.ErrDestroyed
+	}
+
+	blockSize := w.cipher.BlockSize()
+	if len(key) <= 2*blockSize {
+		return fmt.Errorf("whitened key must be longer than %d bytes (got %d): %w", 2*blockSize, len(key), interfaces.ErrInvalidKeySize)
+	}
+
+	split := len(key) - 2*blockSize
+	if err := w.cipher.SetKey(key[:split]); err != nil {
+		return err
+	}
+
+	interfaces.Zeroize(w.pre, w.post)
+	w.pre = append([]byte(nil), key[split:split+blockSize]...)
+	w.post = append([]byte(nil), key[split+blockSize:]...)
+	return nil
+}
+
+func (w *WhitenedCipher) Destroy() {
+	interfaces.Zeroize(w.pre, w.post)
+	w.pre, w.post = nil, nil
+	w.destroyed = true
+	if destroyer, ok := w.cipher.(interfaces.Destroyer); ok {
+		destroyer.Destroy()
+	}
+}
+
+func (w *WhitenedCipher) Encrypt(block []byte) ([]byte, error) {
+	if err := w.check(block); err != nil {
+		return nil, err
+	}
+
+	whitened := xorBlocks(block, w.pre)
+	defer interfaces.Zeroize(whitened)
+
+	result, err := w.cipher.Encrypt(whitened)
+	if err != nil {
+		return nil, err
+	}
+	xorInPlace(result, w.post)
+	return result, nil
+}
+
+func (w *WhitenedCipher) Decrypt(block []byte) ([]byte, error) {
+	if err := w.check(block); err != nil {
+		return nil, err
+	}
+
+	whitened := xorBlocks(block, w.post)
+	defer interfaces.Zeroize(whitened)
+
+	result, err := w.cipher.Decrypt(whitened)
+	if err != nil {
+		return nil, err
+	}
+	xorInPlace(result, w.pre)
+	return result, nil
+}
+
+func (w *WhitenedCipher) BlockSize() int {
+	return w.cipher.BlockSize()
+}
+
+func (w *WhitenedCipher) check(block []byte) error {
+	if w.destroyed {
+		return interfaces.ErrDestroyed
+	}
+	if w.pre == nil {
+		return fmt.Errorf("whitening keys not set: %w", interfaces.ErrKeyNotSet)
+	}
+	if len(block) != w.cipher.BlockSize() {
+		return &interfaces.BlockSizeError{Cipher: "whitened", Size: len(block), Valid: []int{w.cipher.BlockSize()}}
+	}
+	return nil
+}
+
+// DESX is Rivest's DES-X: DES with 64-bit pre- and post-whitening keys and
+// the key layout used by RSA BSAFE and OpenSSL (K, K1, K2).
+type DESX struct {
+	whitened *WhitenedCipher
+}
+
+func NewDESX() (*DESX, error) {
+	cipher, err := des.NewDES()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DES: %w", err)
+	}
+
+	whitened, err := Whitened(cipher)
+	if err != nil {
+		return nil, err
+	}
+	return &DESX{whitened: whitened}, nil
+}
+
+func (d *DESX) SetKey(key []byte) error {
+	if len(key) != DESXKeySize {
+		return &interfaces.KeySizeError{Cipher: "DESX", Size: len(key), Valid: []int{DESXKeySize}}
+	}
+	return d.whitened.SetKey(key)
+}
+
+func (d *DESX) Destroy() {
+	d.whitened.Destroy()
+}
+
+func (d *DESX) Encrypt(block []byte) ([]byte, error) {
+	if len(block) != des.DESBlockSize {
+		return nil, &interfaces.BlockSizeError{Cipher: "DESX", Size: len(block), Valid: []int{des.DESBlockSize}}
+	}
+	return d.whitened.Encrypt(block)
+}
+
+func (d *DESX) Decrypt(block []byte) ([]byte, error) {
+	if len(block) != des.DESBlockSize {
+		return nil, &interfaces.BlockSizeError{Cipher: "DESX", Size: len(block), Valid: []int{des.DESBlockSize}}
+	}
+	return d.whitened.Decrypt(block)
+}
+
+func (d *DESX) BlockSize() int {
+	return des.DESBlockSize
+}
+
+func xorBlocks(a, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+	return result
+}
+
+func xorInPlace(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
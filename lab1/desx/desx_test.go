@@ -0,0 +1,169 @@
+package desx
+
+import (
+	"bytes"
+	"crypto/des"
+	"encoding/hex"
+	"errors"
+	"lab1/deal"
+	"lab1/interfaces"
+	"math/rand/v2"
+	"testing"
+)
+
+type desxVector struct {
+	key        string
+	plaintext  string
+	ciphertext string
+}
+
+// Computed with OpenSSL's DESX-CBC (legacy provider) on single blocks
+// under a zero IV, which is the same as ECB.
+var desxVectors = []desxVector{
+	{"0123456789ABCDEFF1E0D3C2B5A49786FEDCBA9876543210", "0123456789ABCDEF", "E6768BFD0A80EC81"},
+	{"0123456789ABCDEFF1E0D3C2B5A49786FEDCBA9876543210", "0000000000000000", "EABAF4B2A532D45F"},
+	{"0123456789ABCDEFF1E0D3C2B5A49786FEDCBA9876543210", "4E6F772069732074", "C327092461E40219"},
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+func TestDESXVectors(t *testing.T) {
+	cipher, err := NewDESX()
+	if err != nil {
+		t.Fatalf("NewDESX: %v", err)
+	}
+
+	for _, v := range desxVectors {
+		if err := cipher.SetKey(decodeHex(t, v.key)); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		plaintext, expected := decodeHex(t, v.plaintext), decodeHex(t, v.ciphertext)
+
+		encrypted, err := cipher.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if !bytes.Equal(encrypted, expected) {
+			t.Errorf("key %s plaintext %s: got %X, want %s", v.key, v.plaintext, encrypted, v.ciphertext)
+		}
+
+		decrypted, err := cipher.Decrypt(expected)
+		if err != nil {
+			t.Fatalf("Decrypt: %v", err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("key %s ciphertext %s: got %X, want %s", v.key, v.ciphertext, decrypted, v.plaintext)
+		}
+	}
+}
+
+func TestDESXMatchesDefinition(t *testing.T) {
+	cipher, err := NewDESX()
+	if err != nil {
+		t.Fatalf("NewDESX: %v", err)
+	}
+	random := rand.NewChaCha8([32]byte{8})
+
+	for i := 0; i < 16; i++ {
+		key := make([]byte, DESXKeySize)
+		plaintext := make([]byte, 8)
+		random.Read(key)
+		random.Read(plaintext)
+
+		reference, _ := des.NewCipher(key[:8])
+		expected := xorBlocks(plaintext, key[8:16])
+		reference.Encrypt(expected, expected)
+		xorInPlace(expected, key[16:])
+
+		if err := cipher.SetKey(key); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		if encrypted, _ := cipher.Encrypt(plaintext); !bytes.Equal(encrypted, expected) {
+			t.Errorf("key %X: got %X, want %X", key, encrypted, expected)
+		}
+	}
+}
+
+func TestWhitenedDEAL(t *testing.T) {
+	inner, err := deal.NewDEAL(6)
+	if err != nil {
+		t.Fatalf("NewDEAL: %v", err)
+	}
+	cipher, err := Whitened(inner)
+	if err != nil {
+		t.Fatalf("Whitened: %v", err)
+	}
+
+	key := bytes.Repeat([]byte("0123456789abcdef"), 3)
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	plaintext := []byte("sixteen byte msg")
+	encrypted, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	// Without whitening the same DEAL key gives a different ciphertext.
+	plain, _ := deal.NewDEAL(6)
+	plain.SetKey(key[:16])
+	if unwhitened, _ := plain.Encrypt(plaintext); bytes.Equal(unwhitened, encrypted) {
+		t.Error("whitening did not change the ciphertext")
+	}
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("round trip: got %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestWhitenedErrors(t *testing.T) {
+	if _, err := Whitened(nil); err == nil {
+		t.Error("Whitened(nil) succeeded")
+	}
+
+	cipher, _ := NewDESX()
+	if _, err := cipher.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Encrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+
+	var keyErr *interfaces.KeySizeError
+	for _, size := range []int{8, 16, 23, 32} {
+		if err := cipher.SetKey(make([]byte, size)); !errors.As(err, &keyErr) || keyErr.Size != size {
+			t.Errorf("%d-byte key: got %v, want KeySizeError", size, err)
+		}
+	}
+
+	key := bytes.Repeat([]byte{0x5A}, DESXKeySize)
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, 16)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Decrypt 16-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+
+	cipher.Destroy()
+	if _, err := cipher.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Encrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if err := cipher.SetKey(key); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("SetKey after Destroy: got %v, want ErrDestroyed", err)
+	}
+
+	inner, _ := deal.NewDEAL(6)
+	whitened, _ := Whitened(inner)
+	if err := whitened.SetKey(make([]byte, 32)); !errors.Is(err, interfaces.ErrInvalidKeySize) {
+		t.Errorf("key without room for the inner key: got %v, want ErrInvalidKeySize", err)
+	}
+}
@@ -24,6 +24,7 @@ type FeistelNetwork struct {
 	roundKeys     [][]byte
 	numRounds     int
 	halfBlockSize int
+	destroyed     bool
 }
 
 func NewFeistelNetwork(fFunc FeistelFunction, keySched FeistelKeySchedule) (*FeistelNetwork, error) {
@@ -42,7 +43,7 @@ func NewFeistelNetwork(fFunc FeistelFunction, keySched FeistelKeySchedule) (*Fei
 func (fn *FeistelNetwork) Transform(inputBlock []byte, roundKey []byte) ([]byte, error) {
 	expectedSize := fn.halfBlockSize * 2
 	if len(inputBlock) != expectedSize {
-		return nil, fmt.Errorf("input block must be %d bytes", expectedSize)
+		return nil, &interfaces.BlockSizeError{Cipher: "Feistel", Size: len(inputBlock), Valid: []int{expectedSize}}
 	}
 
 	left := make([]byte, fn.halfBlockSize)
@@ -71,7 +72,7 @@ func (fn *FeistelNetwork) Transform(inputBlock []byte, roundKey []byte) ([]byte,
 func (fn *FeistelNetwork) reverseTransform(inputBlock []byte, roundKey []byte) ([]byte, error) {
 	expectedSize := fn.halfBlockSize * 2
 	if len(inputBlock) != expectedSize {
-		return nil, fmt.Errorf("input block must be %d bytes", expectedSize)
+		return nil, &interfaces.BlockSizeError{Cipher: "Feistel", Size: len(inputBlock), Valid: []int{expectedSize}}
 	}
 
 	left := make([]byte, fn.halfBlockSize)
@@ -98,21 +99,35 @@ func (fn *FeistelNetwork) reverseTransform(inputBlock []byte, roundKey []byte) (
 }
 
 func (fn *FeistelNetwork) SetKey(key []byte) error {
+	if fn.destroyed {
+		return interfaces.ErrDestroyed
+	}
+
 	roundKeys, err := fn.keySchedule.ExpandKey(key)
 	if err != nil {
 		return err
 	}
+	interfaces.Zeroize(fn.roundKeys...)
 	fn.roundKeys = roundKeys
 	return nil
 }
 
+func (fn *FeistelNetwork) Destroy() {
+	interfaces.Zeroize(fn.roundKeys...)
+	fn.roundKeys = nil
+	fn.destroyed = true
+}
+
 func (fn *FeistelNetwork) BlockSize() int {
 	return fn.halfBlockSize * 2
 }
 
 func (fn *FeistelNetwork) Encrypt(block []byte) ([]byte, error) {
+	if fn.destroyed {
+		return nil, interfaces.ErrDestroyed
+	}
 	if len(fn.roundKeys) == 0 {
-		return nil, errors.New("round keys not set")
+		return nil, fmt.Errorf("round keys not set: %w", interfaces.ErrKeyNotSet)
 	}
 
 	result := make([]byte, len(block))
@@ -129,8 +144,11 @@ func (fn *FeistelNetwork) Encrypt(block []byte) ([]byte, error) {
 }
 
 func (fn *FeistelNetwork) Decrypt(block []byte) ([]byte, error) {
+	if fn.destroyed {
+		return nil, interfaces.ErrDestroyed
+	}
 	if len(fn.roundKeys) == 0 {
-		return nil, errors.New("round keys not set")
+		return nil, fmt.Errorf("round keys not set: %w", interfaces.ErrKeyNotSet)
 	}
 
 	result := make([]byte, len(block))
@@ -0,0 +1,79 @@
+package interfaces_test
+
+import (
+	"context"
+	"fmt"
+	"lab1/deal"
+	"lab1/des"
+	"lab1/interfaces"
+	tripledes "lab1/tripleDes"
+	"testing"
+)
+
+var benchmarkSizes = []int{1024, 16 * 1024}
+
+func benchmarkCipherContext(b *testing.B, name string, cipher interfaces.BlockCipher, key []byte) {
+	ctx := context.Background()
+
+	for _, mode := range roundTripModes {
+		for _, size := range benchmarkSizes {
+			cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+				Key:     key,
+				Mode:    mode,
+				Padding: interfaces.PKCS7,
+			})
+			if err != nil {
+				b.Fatalf("NewCipherContext: %v", err)
+			}
+
+			plaintext := make([]byte, size)
+			ciphertext, err := cc.EncryptBytes(ctx, plaintext)
+			if err != nil {
+				b.Fatalf("EncryptBytes: %v", err)
+			}
+
+			b.Run(fmt.Sprintf("%s/%v/Encrypt/%dB", name, mode, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := cc.EncryptBytes(ctx, plaintext); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+
+			b.Run(fmt.Sprintf("%s/%v/Decrypt/%dB", name, mode, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := cc.DecryptBytes(ctx, ciphertext); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCipherContext(b *testing.B) {
+	desCipher, err := des.NewDES()
+	if err != nil {
+		b.Fatalf("NewDES: %v", err)
+	}
+	benchmarkCipherContext(b, "DES", desCipher, []byte("8bytekey"))
+
+	tdes, err := tripledes.NewTripleDES(tripledes.EDE)
+	if err != nil {
+		b.Fatalf("NewTripleDES: %v", err)
+	}
+	benchmarkCipherContext(b, "3DES", tdes, []byte("0123456789ABCDEF01234567"))
+
+	dealCipher, err := deal.NewDEAL(6)
+	if err != nil {
+		b.Fatalf("NewDEAL: %v", err)
+	}
+	benchmarkCipherContext(b, "DEAL", dealCipher, []byte("0123456789ABCDEF"))
+
+	simple := interfaces.NewSimpleCipher(16, &interfaces.SimpleKeyExpander{}, &interfaces.SimpleRoundTransformer{})
+	benchmarkCipherContext(b, "Simple", simple, []byte("0123456789ABCDEF"))
+}
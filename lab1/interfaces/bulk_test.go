@@ -0,0 +1,99 @@
+package interfaces_test
+
+import (
+	"bytes"
+	"context"
+	"lab1/des"
+	"lab1/interfaces"
+	"testing"
+)
+
+// bulkDES is DES with a BulkCipher interface, to drive the bulk path of
+// CipherContext.
+type bulkDES struct {
+	*des.DES
+	calls int
+}
+
+func newBulkDES() *bulkDES {
+	cipher, _ := des.NewDES()
+	return &bulkDES{DES: cipher}
+}
+
+func (b *bulkDES) EncryptBlocks(dst, src []byte) error {
+	return b.cryptBlocks(dst, src, b.Encrypt)
+}
+
+func (b *bulkDES) DecryptBlocks(dst, src []byte) error {
+	return b.cryptBlocks(dst, src, b.Decrypt)
+}
+
+func (b *bulkDES) cryptBlocks(dst, src []byte, crypt func([]byte) ([]byte, error)) error {
+	b.calls++
+	for i := 0; i < len(src); i += des.DESBlockSize {
+		block, err := crypt(src[i : i+des.DESBlockSize])
+		if err != nil {
+			return err
+		}
+		copy(dst[i:], block)
+	}
+	return nil
+}
+
+func TestBulkCipherMatchesBlockCipher(t *testing.T) {
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	iv := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xF0}
+	// Several bulk chunks and a partial group of 64 blocks.
+	plaintext := make([]byte, 2500*des.DESBlockSize+3)
+	for i := range plaintext {
+		plaintext[i] = byte(i * 7)
+	}
+
+	for _, mode := range []interfaces.CipherMode{interfaces.ECB, interfaces.CTR} {
+		bulkCipher := newBulkDES()
+		table, _ := des.NewDES()
+		config := interfaces.CipherContextConfig{Key: key, Mode: mode, Padding: interfaces.PKCS7, IV: iv, Workers: 3}
+
+		bulk, err := interfaces.NewCipherContext(bulkCipher, config)
+		if err != nil {
+			t.Fatalf("NewCipherContext: %v", err)
+		}
+		blockwise, err := interfaces.NewCipherContext(table, config)
+		if err != nil {
+			t.Fatalf("NewCipherContext: %v", err)
+		}
+
+		got, err := bulk.EncryptBytes(context.Background(), plaintext)
+		if err != nil {
+			t.Fatalf("%v: bulk EncryptBytes: %v", mode, err)
+		}
+		want, err := blockwise.EncryptBytes(context.Background(), plaintext)
+		if err != nil {
+			t.Fatalf("%v: EncryptBytes: %v", mode, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%v: bulk ciphertext differs from the block-by-block one", mode)
+		}
+		if bulkCipher.calls == 0 {
+			t.Errorf("%v: CipherContext did not use the bulk cipher", mode)
+		}
+
+		decrypted, err := bulk.DecryptBytes(context.Background(), got)
+		if err != nil || !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("%v: bulk round trip failed: %v", mode, err)
+		}
+	}
+}
+
+func TestBulkCipherCancelled(t *testing.T) {
+	cc, err := interfaces.NewCipherContext(newBulkDES(), interfaces.CipherContextConfig{Key: make([]byte, 8), Mode: interfaces.ECB})
+	if err != nil {
+		t.Fatalf("NewCipherContext: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cc.EncryptBytes(ctx, make([]byte, 64)); err == nil {
+		t.Error("EncryptBytes with a cancelled context succeeded")
+	}
+}
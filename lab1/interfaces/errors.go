@@ -0,0 +1,62 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrKeyNotSet        = errors.New("key not set")
+	ErrInvalidKeySize   = errors.New("invalid key size")
+	ErrInvalidBlockSize = errors.New("invalid block size")
+	ErrInvalidPadding   = errors.New("invalid padding")
+	ErrAuthFailed       = errors.New("authentication failed")
+	ErrNonceReuse       = errors.New("nonce reuse")
+	ErrDestroyed        = errors.New("key material destroyed")
+)
+
+type KeySizeError struct {
+	Cipher string
+	Size   int
+	Valid  []int
+}
+
+func (e *KeySizeError) Error() string {
+	return fmt.Sprintf("%s key must be %s bytes (got %d)", e.Cipher, joinSizes(e.Valid), e.Size)
+}
+
+func (e *KeySizeError) Unwrap() error {
+	return ErrInvalidKeySize
+}
+
+type BlockSizeError struct {
+	Cipher string
+	Size   int
+	Valid  []int
+}
+
+func (e *BlockSizeError) Error() string {
+	return fmt.Sprintf("%s block must be %s bytes (got %d)", e.Cipher, joinSizes(e.Valid), e.Size)
+}
+
+func (e *BlockSizeError) Unwrap() error {
+	return ErrInvalidBlockSize
+}
+
+func joinSizes(sizes []int) string {
+	parts := make([]string, len(sizes))
+	for i, size := range sizes {
+		parts[i] = strconv.Itoa(size)
+	}
+
+	switch len(parts) {
+	case 0:
+		return "?"
+	case 1:
+		return parts[0]
+	default:
+		return strings.Join(parts[:len(parts)-1], ", ") + " or " + parts[len(parts)-1]
+	}
+}
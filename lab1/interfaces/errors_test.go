@@ -0,0 +1,291 @@
+package interfaces_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"lab1/des"
+	"lab1/interfaces"
+	"testing"
+)
+
+func newDESContext(t *testing.T, config interfaces.CipherContextConfig) *interfaces.CipherContext {
+	t.Helper()
+
+	cipher, err := des.NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+
+	config.Key = []byte("8bytekey")
+	cc, err := interfaces.NewCipherContext(cipher, config)
+	if err != nil {
+		t.Fatalf("NewCipherContext: %v", err)
+	}
+	return cc
+}
+
+func TestSizeErrors(t *testing.T) {
+	keyErr := error(&interfaces.KeySizeError{Cipher: "TripleDES", Size: 7, Valid: []int{8, 16, 24}})
+	if got, want := keyErr.Error(), "TripleDES key must be 8, 16 or 24 bytes (got 7)"; got != want {
+		t.Errorf("KeySizeError: got %q, want %q", got, want)
+	}
+	if !errors.Is(keyErr, interfaces.ErrInvalidKeySize) {
+		t.Errorf("KeySizeError does not match ErrInvalidKeySize")
+	}
+
+	blockErr := error(&interfaces.BlockSizeError{Cipher: "DES", Size: 3, Valid: []int{8}})
+	if got, want := blockErr.Error(), "DES block must be 8 bytes (got 3)"; got != want {
+		t.Errorf("BlockSizeError: got %q, want %q", got, want)
+	}
+	if !errors.Is(blockErr, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("BlockSizeError does not match ErrInvalidBlockSize")
+	}
+	if errors.Is(blockErr, interfaces.ErrInvalidKeySize) {
+		t.Errorf("BlockSizeError matches ErrInvalidKeySize")
+	}
+}
+
+func TestCipherContextErrors(t *testing.T) {
+	ctx := context.Background()
+
+	cipher, err := des.NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+
+	_, err = interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{Key: []byte("short")})
+	var keyErr *interfaces.KeySizeError
+	if !errors.As(err, &keyErr) || keyErr.Size != 5 {
+		t.Errorf("short key: got %v, want KeySizeError with size 5", err)
+	}
+
+	_, err = interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+		Key:  []byte("8bytekey"),
+		Mode: interfaces.CBC,
+		IV:   []byte("iv"),
+	})
+	if !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("short IV: got %v, want ErrInvalidBlockSize", err)
+	}
+
+	cases := []struct {
+		name       string
+		mode       interfaces.CipherMode
+		padding    interfaces.PaddingMode
+		ciphertext func() []byte
+		want       error
+	}{
+		{
+			name: "misaligned ECB",
+			mode: interfaces.ECB,
+			ciphertext: func() []byte {
+				return make([]byte, 12)
+			},
+			want: interfaces.ErrInvalidBlockSize,
+		},
+		{
+			name: "misaligned RandomDelta",
+			mode: interfaces.RandomDelta,
+			ciphertext: func() []byte {
+				return make([]byte, 24)
+			},
+			want: interfaces.ErrInvalidBlockSize,
+		},
+		{
+			name: "empty",
+			mode: interfaces.CBC,
+			ciphertext: func() []byte {
+				return nil
+			},
+			want: interfaces.ErrInvalidPadding,
+		},
+		{
+			name:    "PKCS7 length",
+			mode:    interfaces.ECB,
+			padding: interfaces.PKCS7,
+			ciphertext: func() []byte {
+				return encryptRaw(t, []byte{1, 2, 3, 4, 5, 6, 7, 0})
+			},
+			want: interfaces.ErrInvalidPadding,
+		},
+		{
+			name:    "PKCS7 bytes",
+			mode:    interfaces.ECB,
+			padding: interfaces.PKCS7,
+			ciphertext: func() []byte {
+				return encryptRaw(t, []byte{1, 2, 3, 4, 5, 3, 2, 3})
+			},
+			want: interfaces.ErrInvalidPadding,
+		},
+		{
+			name:    "ANSIX923 fill",
+			mode:    interfaces.ECB,
+			padding: interfaces.ANSIX923,
+			ciphertext: func() []byte {
+				return encryptRaw(t, []byte{1, 2, 3, 4, 5, 1, 0, 3})
+			},
+			want: interfaces.ErrInvalidPadding,
+		},
+	}
+
+	for _, c := range cases {
+		cc := newDESContext(t, interfaces.CipherContextConfig{Mode: c.mode, Padding: c.padding})
+		if _, err := cc.DecryptBytes(ctx, c.ciphertext()); !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+// encryptRaw encrypts a single block under the context key so the
+// decrypted plaintext carries exactly the given padding bytes.
+func encryptRaw(t *testing.T, block []byte) []byte {
+	t.Helper()
+
+	cipher, err := des.NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+	if err := cipher.SetKey([]byte("8bytekey")); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	ciphertext, err := cipher.Encrypt(block)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	return ciphertext
+}
+
+func TestSingleUseIV(t *testing.T) {
+	ctx := context.Background()
+	plaintext := []byte("attack at dawn")
+
+	for _, mode := range roundTripModes {
+		cc := newDESContext(t, interfaces.CipherContextConfig{
+			Mode:        mode,
+			Padding:     interfaces.PKCS7,
+			SingleUseIV: true,
+		})
+
+		ciphertext, err := cc.EncryptBytes(ctx, plaintext)
+		if err != nil {
+			t.Fatalf("%v: first encryption: %v", mode, err)
+		}
+
+		_, err = cc.EncryptBytes(ctx, plaintext)
+		usesIV := mode != interfaces.ECB && mode != interfaces.RandomDelta
+		if usesIV && !errors.Is(err, interfaces.ErrNonceReuse) {
+			t.Errorf("%v: second encryption: got %v, want ErrNonceReuse", mode, err)
+		}
+		if !usesIV && err != nil {
+			t.Errorf("%v: second encryption: %v", mode, err)
+		}
+
+		if _, err := cc.DecryptBytes(ctx, ciphertext); err != nil {
+			t.Errorf("%v: decryption after single use: %v", mode, err)
+		}
+	}
+}
+
+func TestSingleUseIVAfterFailure(t *testing.T) {
+	plaintext := []byte("attack at dawn")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cc := newDESContext(t, interfaces.CipherContextConfig{
+		Mode:        interfaces.CBC,
+		Padding:     interfaces.PaddingMode(99),
+		SingleUseIV: true,
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := cc.EncryptBytes(context.Background(), plaintext); err == nil || errors.Is(err, interfaces.ErrNonceReuse) {
+			t.Fatalf("encryption %d with an unknown padding: %v", i, err)
+		}
+	}
+
+	// None of the failures produce ciphertext, so the IV is still unused.
+	var out bytes.Buffer
+	cc = newDESContext(t, interfaces.CipherContextConfig{Mode: interfaces.CBC, Padding: interfaces.PKCS7, SingleUseIV: true})
+	if _, err := cc.EncryptBytes(cancelled, plaintext); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled encryption: %v", err)
+	}
+	if err := cc.EncryptStream(cancelled, bytes.NewReader(plaintext), &out); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled stream: %v", err)
+	}
+	if err := cc.EncryptStream(context.Background(), bytes.NewReader(plaintext), &out); err != nil {
+		t.Fatalf("encryption after failures: %v", err)
+	}
+	if _, err := cc.EncryptBytes(context.Background(), plaintext); !errors.Is(err, interfaces.ErrNonceReuse) {
+		t.Errorf("second encryption: got %v, want ErrNonceReuse", err)
+	}
+}
+
+func TestSimpleCipherErrors(t *testing.T) {
+	sc := interfaces.NewSimpleCipher(8, nil, nil)
+
+	if _, err := sc.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Encrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+	if _, err := sc.Decrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Decrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+
+	var blockErr *interfaces.BlockSizeError
+	if _, err := sc.Encrypt(make([]byte, 5)); !errors.As(err, &blockErr) || blockErr.Size != 5 {
+		t.Errorf("short block: got %v, want BlockSizeError with size 5", err)
+	}
+}
+
+func TestCipherContextClose(t *testing.T) {
+	ctx := context.Background()
+	iv := []byte("initvect")
+
+	cc := newDESContext(t, interfaces.CipherContextConfig{
+		Mode:    interfaces.CBC,
+		Padding: interfaces.PKCS7,
+		IV:      iv,
+	})
+
+	ciphertext, err := cc.EncryptBytes(ctx, []byte("attack at dawn"))
+	if err != nil {
+		t.Fatalf("EncryptBytes: %v", err)
+	}
+
+	if err := cc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := cc.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+
+	if string(iv) != "initvect" {
+		t.Errorf("Close wiped the caller's IV: %x", iv)
+	}
+
+	if _, err := cc.EncryptBytes(ctx, []byte("attack at dawn")); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("EncryptBytes after Close: got %v, want ErrDestroyed", err)
+	}
+	if _, err := cc.DecryptBytes(ctx, ciphertext); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("DecryptBytes after Close: got %v, want ErrDestroyed", err)
+	}
+}
+
+func TestCipherContextCloseDestroysCipher(t *testing.T) {
+	cipher, err := des.NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+
+	cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{Key: []byte("8bytekey")})
+	if err != nil {
+		t.Fatalf("NewCipherContext: %v", err)
+	}
+	if err := cc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := cipher.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Encrypt after Close: got %v, want ErrDestroyed", err)
+	}
+}
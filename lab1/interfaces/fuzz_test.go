@@ -0,0 +1,132 @@
+package interfaces
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+var (
+	allModes    = []CipherMode{ECB, CBC, PCBC, CFB, OFB, CTR, RandomDelta}
+	allPaddings = []PaddingMode{Zeros, ANSIX923, PKCS7, ISO10126}
+)
+
+func newFuzzContext(t *testing.T, blockSize int, mode CipherMode, padding PaddingMode) *CipherContext {
+	t.Helper()
+
+	cipher := NewSimpleCipher(blockSize, &SimpleKeyExpander{}, &SimpleRoundTransformer{})
+	cc, err := NewCipherContext(cipher, CipherContextConfig{
+		Key:     bytes.Repeat([]byte{0x5A}, blockSize),
+		Mode:    mode,
+		Padding: padding,
+		IV:      bytes.Repeat([]byte{0xA5}, blockSize),
+	})
+	if err != nil {
+		t.Fatalf("NewCipherContext: %v", err)
+	}
+	return cc
+}
+
+func fuzzBlockSize(selector byte) int {
+	return []int{8, 16, 24, 32}[int(selector)%4]
+}
+
+func FuzzRemovePadding(f *testing.F) {
+	for padding := range allPaddings {
+		f.Add(byte(padding), byte(0), []byte{1, 2, 3, 4, 5, 6, 7, 8})
+		f.Add(byte(padding), byte(0), []byte{1, 2, 3, 4, 5, 6, 7, 0})
+		f.Add(byte(padding), byte(1), bytes.Repeat([]byte{0x10}, 16))
+		f.Add(byte(padding), byte(0), bytes.Repeat([]byte{0xFF}, 8))
+	}
+
+	f.Fuzz(func(t *testing.T, paddingSelector, blockSelector byte, data []byte) {
+		padding := allPaddings[int(paddingSelector)%len(allPaddings)]
+		cc := newFuzzContext(t, fuzzBlockSize(blockSelector), ECB, padding)
+
+		unpadded, err := cc.removePadding(data)
+		if err != nil {
+			return
+		}
+
+		if len(unpadded) > len(data) || !bytes.Equal(unpadded, data[:len(unpadded)]) {
+			t.Fatalf("removePadding returned %x, which is not a prefix of %x", unpadded, data)
+		}
+
+		if padding != Zeros && len(data)-len(unpadded) == 0 {
+			t.Fatalf("removePadding accepted %x without stripping any padding", data)
+		}
+
+		if padding == PKCS7 || padding == ANSIX923 {
+			repadded, err := cc.applyPadding(unpadded)
+			if err != nil {
+				t.Fatalf("applyPadding: %v", err)
+			}
+			if !bytes.Equal(repadded, data) {
+				t.Fatalf("accepted padding %x does not match canonical padding %x", data, repadded)
+			}
+		}
+	})
+}
+
+func FuzzDecryptBytes(f *testing.F) {
+	for mode := range allModes {
+		for padding := range allPaddings {
+			f.Add(byte(mode), byte(padding), byte(0), []byte{})
+			f.Add(byte(mode), byte(padding), byte(0), []byte{0x01, 0x02, 0x03})
+			f.Add(byte(mode), byte(padding), byte(1), bytes.Repeat([]byte{0x00}, 32))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, modeSelector, paddingSelector, blockSelector byte, ciphertext []byte) {
+		mode := allModes[int(modeSelector)%len(allModes)]
+		padding := allPaddings[int(paddingSelector)%len(allPaddings)]
+		cc := newFuzzContext(t, fuzzBlockSize(blockSelector), mode, padding)
+
+		plaintext, err := cc.DecryptBytes(context.Background(), ciphertext)
+		if err != nil && plaintext != nil {
+			t.Fatalf("DecryptBytes returned both data and error %v", err)
+		}
+	})
+}
+
+func FuzzCipherContextRoundTrip(f *testing.F) {
+	for mode := range allModes {
+		for padding := range allPaddings {
+			f.Add(byte(mode), byte(padding), byte(0), []byte{})
+			f.Add(byte(mode), byte(padding), byte(1), []byte("sixteen bytes!!!"))
+			f.Add(byte(mode), byte(padding), byte(2), []byte("an input that is not block aligned"))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, modeSelector, paddingSelector, blockSelector byte, plaintext []byte) {
+		mode := allModes[int(modeSelector)%len(allModes)]
+		padding := allPaddings[int(paddingSelector)%len(allPaddings)]
+		cc := newFuzzContext(t, fuzzBlockSize(blockSelector), mode, padding)
+		checkRoundTrip(t, cc, padding, plaintext)
+	})
+}
+
+func checkRoundTrip(t *testing.T, cc *CipherContext, padding PaddingMode, plaintext []byte) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	encrypted, err := cc.EncryptBytes(ctx, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptBytes: %v", err)
+	}
+
+	decrypted, err := cc.DecryptBytes(ctx, encrypted)
+	if err != nil {
+		t.Fatalf("DecryptBytes: %v", err)
+	}
+
+	expected := plaintext
+	if padding == Zeros {
+		expected = bytes.TrimRight(plaintext, "\x00")
+	}
+
+	if !bytes.Equal(decrypted, expected) {
+		t.Fatalf("%v/%v round trip: got %x, want %x", cc.mode, padding, decrypted, expected)
+	}
+}
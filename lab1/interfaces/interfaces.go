@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type KeyExpander interface {
@@ -25,6 +27,32 @@ type BlockCipher interface {
 	BlockSize() int
 }
 
+// Destroyer is implemented by ciphers that can wipe their key material.
+// After Destroy every further SetKey, Encrypt or Decrypt fails with
+// ErrDestroyed.
+type Destroyer interface {
+	Destroy()
+}
+
+// BulkCipher is implemented by ciphers that process many independent blocks
+// faster together than one at a time. CipherContext uses it for ECB and
+// CTR. src is a whole number of blocks and dst, at least as long, may be src
+// itself.
+type BulkCipher interface {
+	EncryptBlocks(dst, src []byte) error
+	DecryptBlocks(dst, src []byte) error
+}
+
+// bulkChunkBlocks is the number of blocks a worker hands to a BulkCipher at
+// once.
+const bulkChunkBlocks = 1024
+
+func Zeroize(buffers ...[]byte) {
+	for _, buffer := range buffers {
+		clear(buffer)
+	}
+}
+
 type CipherMode int
 
 const (
@@ -58,6 +86,25 @@ func (cm CipherMode) String() string {
 	}
 }
 
+func ParseCipherMode(s string) (CipherMode, error) {
+	for mode := ECB; mode <= RandomDelta; mode++ {
+		if strings.EqualFold(s, mode.String()) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown cipher mode: %q", s)
+}
+
+// RequiresIV reports whether the mode chains from an IV or counter block.
+func (cm CipherMode) RequiresIV() bool {
+	switch cm {
+	case CBC, PCBC, CFB, OFB, CTR:
+		return true
+	default:
+		return false
+	}
+}
+
 type PaddingMode int
 
 const (
@@ -82,11 +129,26 @@ func (pm PaddingMode) String() string {
 	}
 }
 
+func ParsePaddingMode(s string) (PaddingMode, error) {
+	for padding := Zeros; padding <= ISO10126; padding++ {
+		if strings.EqualFold(s, padding.String()) {
+			return padding, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown padding mode: %q", s)
+}
+
+const DefaultWorkers = 8
+
 type CipherContextConfig struct {
-	Key            []byte
-	Mode           CipherMode
-	Padding        PaddingMode
-	IV             []byte
+	Key     []byte
+	Mode    CipherMode
+	Padding PaddingMode
+	IV      []byte
+	Workers int
+	// SingleUseIV refuses to encrypt twice under the IV. An encryption that
+	// fails without producing ciphertext does not use it up.
+	SingleUseIV    bool
 	AdditionalArgs []interface{}
 }
 
@@ -97,6 +159,10 @@ type CipherContext struct {
 	iv             []byte
 	additionalArgs []interface{}
 	blockSize      int
+	workers        int
+	singleUseIV    bool
+	ivUsed         atomic.Bool
+	closed         atomic.Bool
 }
 
 func NewCipherContext(cipher BlockCipher, config CipherContextConfig) (*CipherContext, error) {
@@ -110,8 +176,10 @@ func NewCipherContext(cipher BlockCipher, config CipherContextConfig) (*CipherCo
 
 	blockSize := cipher.BlockSize()
 
-	iv := config.IV
-	if iv == nil && requiresIV(config.Mode) {
+	var iv []byte
+	if config.IV != nil {
+		iv = append([]byte(nil), config.IV...)
+	} else if config.Mode.RequiresIV() {
 		iv = make([]byte, blockSize)
 		if _, err := io.ReadFull(rand.Reader, iv); err != nil {
 			return nil, fmt.Errorf("failed to generate IV: %w", err)
@@ -119,7 +187,15 @@ func NewCipherContext(cipher BlockCipher, config CipherContextConfig) (*CipherCo
 	}
 
 	if iv != nil && len(iv) != blockSize {
-		return nil, fmt.Errorf("IV length must be equal to block size (%d bytes)", blockSize)
+		return nil, fmt.Errorf("IV length must be equal to block size (%d bytes): %w", blockSize, ErrInvalidBlockSize)
+	}
+
+	workers := config.Workers
+	if workers < 0 {
+		return nil, errors.New("number of workers cannot be negative")
+	}
+	if workers == 0 {
+		workers = DefaultWorkers
 	}
 
 	return &CipherContext{
@@ -129,16 +205,23 @@ func NewCipherContext(cipher BlockCipher, config CipherContextConfig) (*CipherCo
 		iv:             iv,
 		additionalArgs: config.AdditionalArgs,
 		blockSize:      blockSize,
+		workers:        workers,
+		singleUseIV:    config.SingleUseIV,
 	}, nil
 }
 
-func requiresIV(mode CipherMode) bool {
-	switch mode {
-	case CBC, PCBC, CFB, OFB, CTR:
-		return true
-	default:
-		return false
+// Close wipes the IV and destroys the underlying cipher if it implements
+// Destroyer. The context must not be in use by other goroutines.
+func (cc *CipherContext) Close() error {
+	if cc.closed.Swap(true) {
+		return nil
 	}
+
+	Zeroize(cc.iv)
+	if destroyer, ok := cc.cipher.(Destroyer); ok {
+		destroyer.Destroy()
+	}
+	return nil
 }
 
 type encryptResult struct {
@@ -159,32 +242,28 @@ func (cc *CipherContext) EncryptBytes(ctx context.Context, plaintext []byte) ([]
 		default:
 		}
 
-		paddedData, err := cc.applyPadding(plaintext)
+		if cc.closed.Load() {
+			resultCh <- encryptResult{err: fmt.Errorf("cipher context closed: %w", ErrDestroyed)}
+			return
+		}
+
+		release, err := cc.reserveIV()
 		if err != nil {
 			resultCh <- encryptResult{err: err}
 			return
 		}
 
-		var ciphertext []byte
-		switch cc.mode {
-		case ECB:
-			ciphertext, err = cc.encryptECB(ctx, paddedData)
-		case CBC:
-			ciphertext, err = cc.encryptCBC(ctx, paddedData)
-		case PCBC:
-			ciphertext, err = cc.encryptPCBC(ctx, paddedData)
-		case CFB:
-			ciphertext, err = cc.encryptCFB(ctx, paddedData)
-		case OFB:
-			ciphertext, err = cc.encryptOFB(ctx, paddedData)
-		case CTR:
-			ciphertext, err = cc.encryptCTR(ctx, paddedData)
-		case RandomDelta:
-			ciphertext, err = cc.encryptRandomDelta(ctx, paddedData)
-		default:
-			err = fmt.Errorf("unsupported cipher mode: %v", cc.mode)
+		paddedData, err := cc.applyPadding(plaintext)
+		if err != nil {
+			release()
+			resultCh <- encryptResult{err: err}
+			return
 		}
 
+		ciphertext, err := cc.encryptBlocks(ctx, paddedData)
+		if err != nil {
+			release()
+		}
 		resultCh <- encryptResult{data: ciphertext, err: err}
 	}()
 
@@ -196,6 +275,19 @@ func (cc *CipherContext) EncryptBytes(ctx context.Context, plaintext []byte) ([]
 	}
 }
 
+// reserveIV marks a single-use IV as used. Encryptions that fail before
+// producing any ciphertext call release to make it available again; one
+// that finishes after its context was cancelled keeps it used.
+func (cc *CipherContext) reserveIV() (release func(), err error) {
+	if !cc.singleUseIV || !cc.mode.RequiresIV() {
+		return func() {}, nil
+	}
+	if !cc.ivUsed.CompareAndSwap(false, true) {
+		return nil, fmt.Errorf("IV already used for encryption: %w", ErrNonceReuse)
+	}
+	return func() { cc.ivUsed.Store(false) }, nil
+}
+
 func (cc *CipherContext) EncryptBytesTo(ctx context.Context, plaintext []byte, result *[]byte) error {
 	ciphertext, err := cc.EncryptBytes(ctx, plaintext)
 	if err != nil {
@@ -218,27 +310,12 @@ func (cc *CipherContext) DecryptBytes(ctx context.Context, ciphertext []byte) ([
 		default:
 		}
 
-		var plaintext []byte
-		var err error
-		switch cc.mode {
-		case ECB:
-			plaintext, err = cc.decryptECB(ctx, ciphertext)
-		case CBC:
-			plaintext, err = cc.decryptCBC(ctx, ciphertext)
-		case PCBC:
-			plaintext, err = cc.decryptPCBC(ctx, ciphertext)
-		case CFB:
-			plaintext, err = cc.decryptCFB(ctx, ciphertext)
-		case OFB:
-			plaintext, err = cc.decryptOFB(ctx, ciphertext)
-		case CTR:
-			plaintext, err = cc.decryptCTR(ctx, ciphertext)
-		case RandomDelta:
-			plaintext, err = cc.decryptRandomDelta(ctx, ciphertext)
-		default:
-			err = fmt.Errorf("unsupported cipher mode: %v", cc.mode)
+		if cc.closed.Load() {
+			resultCh <- encryptResult{err: fmt.Errorf("cipher context closed: %w", ErrDestroyed)}
+			return
 		}
 
+		plaintext, err := cc.decryptBlocks(ctx, ciphertext)
 		if err != nil {
 			resultCh <- encryptResult{err: err}
 			return
@@ -265,6 +342,50 @@ func (cc *CipherContext) DecryptBytesTo(ctx context.Context, ciphertext []byte,
 	return nil
 }
 
+// encryptBlocks runs the mode over already padded data.
+func (cc *CipherContext) encryptBlocks(ctx context.Context, data []byte) ([]byte, error) {
+	switch cc.mode {
+	case ECB:
+		return cc.encryptECB(ctx, data)
+	case CBC:
+		return cc.encryptCBC(ctx, data)
+	case PCBC:
+		return cc.encryptPCBC(ctx, data)
+	case CFB:
+		return cc.encryptCFB(ctx, data)
+	case OFB:
+		return cc.encryptOFB(ctx, data)
+	case CTR:
+		return cc.encryptCTR(ctx, data)
+	case RandomDelta:
+		return cc.encryptRandomDelta(ctx, data)
+	default:
+		return nil, fmt.Errorf("unsupported cipher mode: %v", cc.mode)
+	}
+}
+
+// decryptBlocks runs the mode without removing the padding.
+func (cc *CipherContext) decryptBlocks(ctx context.Context, data []byte) ([]byte, error) {
+	switch cc.mode {
+	case ECB:
+		return cc.decryptECB(ctx, data)
+	case CBC:
+		return cc.decryptCBC(ctx, data)
+	case PCBC:
+		return cc.decryptPCBC(ctx, data)
+	case CFB:
+		return cc.decryptCFB(ctx, data)
+	case OFB:
+		return cc.decryptOFB(ctx, data)
+	case CTR:
+		return cc.decryptCTR(ctx, data)
+	case RandomDelta:
+		return cc.decryptRandomDelta(ctx, data)
+	default:
+		return nil, fmt.Errorf("unsupported cipher mode: %v", cc.mode)
+	}
+}
+
 func (cc *CipherContext) EncryptFile(ctx context.Context, inputPath, outputPath string) error {
 	resultCh := make(chan error, 1)
 
@@ -334,13 +455,17 @@ func (cc *CipherContext) DecryptFile(ctx context.Context, inputPath, outputPath
 }
 
 func (cc *CipherContext) encryptECB(ctx context.Context, data []byte) ([]byte, error) {
+	if bulk, ok := cc.cipher.(BulkCipher); ok {
+		return cc.processBulk(ctx, data, bulk.EncryptBlocks)
+	}
+
 	numBlocks := len(data) / cc.blockSize
 	ciphertext := make([]byte, len(data))
 
 	var wg sync.WaitGroup
 	errCh := make(chan error, numBlocks)
 
-	maxWorkers := min(numBlocks, 8)
+	maxWorkers := min(numBlocks, cc.workers)
 	blocksCh := make(chan int, numBlocks)
 
 	for i := 0; i < numBlocks; i++ {
@@ -388,7 +513,10 @@ func (cc *CipherContext) encryptECB(ctx context.Context, data []byte) ([]byte, e
 
 func (cc *CipherContext) decryptECB(ctx context.Context, data []byte) ([]byte, error) {
 	if len(data)%cc.blockSize != 0 {
-		return nil, errors.New("ciphertext length must be multiple of block size")
+		return nil, fmt.Errorf("ciphertext length must be multiple of block size: %w", ErrInvalidBlockSize)
+	}
+	if bulk, ok := cc.cipher.(BulkCipher); ok {
+		return cc.processBulk(ctx, data, bulk.DecryptBlocks)
 	}
 
 	numBlocks := len(data) / cc.blockSize
@@ -397,7 +525,7 @@ func (cc *CipherContext) decryptECB(ctx context.Context, data []byte) ([]byte, e
 	var wg sync.WaitGroup
 	errCh := make(chan error, numBlocks)
 
-	maxWorkers := min(numBlocks, 8)
+	maxWorkers := min(numBlocks, cc.workers)
 	blocksCh := make(chan int, numBlocks)
 
 	for i := 0; i < numBlocks; i++ {
@@ -445,7 +573,7 @@ func (cc *CipherContext) decryptECB(ctx context.Context, data []byte) ([]byte, e
 
 func (cc *CipherContext) encryptCBC(ctx context.Context, data []byte) ([]byte, error) {
 	if len(data)%cc.blockSize != 0 {
-		return nil, errors.New("data length must be multiple of block size")
+		return nil, fmt.Errorf("data length must be multiple of block size: %w", ErrInvalidBlockSize)
 	}
 
 	numBlocks := len(data) / cc.blockSize
@@ -481,7 +609,7 @@ func (cc *CipherContext) encryptCBC(ctx context.Context, data []byte) ([]byte, e
 
 func (cc *CipherContext) decryptCBC(ctx context.Context, data []byte) ([]byte, error) {
 	if len(data)%cc.blockSize != 0 {
-		return nil, errors.New("ciphertext length must be multiple of block size")
+		return nil, fmt.Errorf("ciphertext length must be multiple of block size: %w", ErrInvalidBlockSize)
 	}
 
 	numBlocks := len(data) / cc.blockSize
@@ -489,7 +617,7 @@ func (cc *CipherContext) decryptCBC(ctx context.Context, data []byte) ([]byte, e
 	var wg sync.WaitGroup
 	errCh := make(chan error, numBlocks)
 
-	maxWorkers := min(numBlocks, 8)
+	maxWorkers := min(numBlocks, cc.workers)
 	blocksCh := make(chan int, numBlocks)
 
 	for i := 0; i < numBlocks; i++ {
@@ -548,7 +676,7 @@ func (cc *CipherContext) decryptCBC(ctx context.Context, data []byte) ([]byte, e
 
 func (cc *CipherContext) encryptPCBC(ctx context.Context, data []byte) ([]byte, error) {
 	if len(data)%cc.blockSize != 0 {
-		return nil, errors.New("data length must be multiple of block size")
+		return nil, fmt.Errorf("data length must be multiple of block size: %w", ErrInvalidBlockSize)
 	}
 
 	numBlocks := len(data) / cc.blockSize
@@ -585,7 +713,7 @@ func (cc *CipherContext) encryptPCBC(ctx context.Context, data []byte) ([]byte,
 
 func (cc *CipherContext) decryptPCBC(ctx context.Context, data []byte) ([]byte, error) {
 	if len(data)%cc.blockSize != 0 {
-		return nil, errors.New("ciphertext length must be multiple of block size")
+		return nil, fmt.Errorf("ciphertext length must be multiple of block size: %w", ErrInvalidBlockSize)
 	}
 
 	numBlocks := len(data) / cc.blockSize
@@ -657,7 +785,7 @@ func (cc *CipherContext) decryptCFB(ctx context.Context, data []byte) ([]byte, e
 	var wg sync.WaitGroup
 	errCh := make(chan error, numFullBlocks)
 
-	maxWorkers := min(numFullBlocks, 8)
+	maxWorkers := min(numFullBlocks, cc.workers)
 	blocksCh := make(chan int, numFullBlocks)
 
 	for i := 0; i < numFullBlocks; i++ {
@@ -737,8 +865,10 @@ func (cc *CipherContext) encryptOFB(ctx context.Context, data []byte) ([]byte, e
 	ciphertext := make([]byte, len(data))
 
 	keystream := make([][]byte, numBlocks)
+	defer func() { Zeroize(keystream...) }()
 	iv := make([]byte, cc.blockSize)
 	copy(iv, cc.iv)
+	defer Zeroize(iv)
 
 	for i := 0; i < numBlocks; i++ {
 		select {
@@ -752,15 +882,14 @@ func (cc *CipherContext) encryptOFB(ctx context.Context, data []byte) ([]byte, e
 			return nil, err
 		}
 
-		keystream[i] = make([]byte, cc.blockSize)
-		copy(keystream[i], encrypted)
+		keystream[i] = encrypted
 		copy(iv, encrypted)
 	}
 
 	var wg sync.WaitGroup
 	errCh := make(chan error, numBlocks)
 
-	maxWorkers := min(numBlocks, 8)
+	maxWorkers := min(numBlocks, cc.workers)
 	blocksCh := make(chan int, numBlocks)
 
 	for i := 0; i < numBlocks; i++ {
@@ -808,6 +937,10 @@ func (cc *CipherContext) decryptOFB(ctx context.Context, data []byte) ([]byte, e
 }
 
 func (cc *CipherContext) encryptCTR(ctx context.Context, data []byte) ([]byte, error) {
+	if bulk, ok := cc.cipher.(BulkCipher); ok {
+		return cc.encryptCTRBulk(ctx, data, bulk)
+	}
+
 	ciphertext := make([]byte, len(data))
 
 	counter := make([]byte, cc.blockSize)
@@ -818,7 +951,7 @@ func (cc *CipherContext) encryptCTR(ctx context.Context, data []byte) ([]byte, e
 	var wg sync.WaitGroup
 	errCh := make(chan error, numBlocks)
 
-	maxWorkers := min(numBlocks, 8)
+	maxWorkers := min(numBlocks, cc.workers)
 	blocksCh := make(chan int, numBlocks)
 
 	for i := 0; i < numBlocks; i++ {
@@ -853,6 +986,7 @@ func (cc *CipherContext) encryptCTR(ctx context.Context, data []byte) ([]byte, e
 				for j := start; j < end; j++ {
 					ciphertext[j] = data[j] ^ encrypted[j-start]
 				}
+				Zeroize(encrypted)
 			}
 		}()
 	}
@@ -873,9 +1007,103 @@ func (cc *CipherContext) decryptCTR(ctx context.Context, data []byte) ([]byte, e
 	return cc.encryptCTR(ctx, data)
 }
 
+// encryptCTRBulk builds the counter blocks of each chunk in the worker
+// that encrypts it and XORs the keystream in right away, so at most one
+// chunk of keystream per worker exists at a time.
+func (cc *CipherContext) encryptCTRBulk(ctx context.Context, data []byte, bulk BulkCipher) ([]byte, error) {
+	ciphertext := make([]byte, len(data))
+	err := cc.bulkChunks(ctx, len(data), func(start, end int) error {
+		numBlocks := (end - start + cc.blockSize - 1) / cc.blockSize
+		keystream := make([]byte, numBlocks*cc.blockSize)
+		defer Zeroize(keystream)
+
+		for i := 0; i < numBlocks; i++ {
+			counter := keystream[i*cc.blockSize : (i+1)*cc.blockSize]
+			copy(counter, cc.iv)
+			incrementCounter(counter, start/cc.blockSize+i)
+		}
+		if err := bulk.EncryptBlocks(keystream, keystream); err != nil {
+			return err
+		}
+
+		copy(ciphertext[start:end], data[start:end])
+		XorBytes(ciphertext[start:end], keystream)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ciphertext, nil
+}
+
+// processBulk passes whole blocks to a BulkCipher operation chunk by chunk.
+func (cc *CipherContext) processBulk(ctx context.Context, data []byte, operation func(dst, src []byte) error) ([]byte, error) {
+	if len(data)%cc.blockSize != 0 {
+		return nil, fmt.Errorf("data length must be multiple of block size: %w", ErrInvalidBlockSize)
+	}
+
+	result := make([]byte, len(data))
+	err := cc.bulkChunks(ctx, len(data), func(start, end int) error {
+		return operation(result[start:end], data[start:end])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// bulkChunks splits length bytes into chunks of bulkChunkBlocks blocks and
+// has the workers call process on the byte range of each.
+func (cc *CipherContext) bulkChunks(ctx context.Context, length int, process func(start, end int) error) error {
+	chunkSize := bulkChunkBlocks * cc.blockSize
+	numChunks := (length + chunkSize - 1) / chunkSize
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, numChunks)
+
+	maxWorkers := min(numChunks, cc.workers)
+	chunksCh := make(chan int, numChunks)
+
+	for i := 0; i < numChunks; i++ {
+		chunksCh <- i
+	}
+	close(chunksCh)
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunkIdx := range chunksCh {
+				select {
+				case <-ctx.Done():
+					errCh <- ctx.Err()
+					return
+				default:
+				}
+
+				start := chunkIdx * chunkSize
+				if err := process(start, min(start+chunkSize, length)); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (cc *CipherContext) encryptRandomDelta(ctx context.Context, data []byte) ([]byte, error) {
 	if len(data)%cc.blockSize != 0 {
-		return nil, errors.New("data length must be multiple of block size")
+		return nil, fmt.Errorf("data length must be multiple of block size: %w", ErrInvalidBlockSize)
 	}
 
 	numBlocks := len(data) / cc.blockSize
@@ -915,7 +1143,7 @@ func (cc *CipherContext) encryptRandomDelta(ctx context.Context, data []byte) ([
 
 func (cc *CipherContext) decryptRandomDelta(ctx context.Context, data []byte) ([]byte, error) {
 	if len(data)%(cc.blockSize*2) != 0 {
-		return nil, errors.New("ciphertext length must be multiple of 2*block size")
+		return nil, fmt.Errorf("ciphertext length must be multiple of 2*block size: %w", ErrInvalidBlockSize)
 	}
 
 	numBlocks := len(data) / (cc.blockSize * 2)
@@ -984,11 +1212,11 @@ func (cc *CipherContext) applyPadding(data []byte) ([]byte, error) {
 
 func (cc *CipherContext) removePadding(data []byte) ([]byte, error) {
 	if len(data) == 0 {
-		return nil, errors.New("cannot remove padding from empty data")
+		return nil, fmt.Errorf("cannot remove padding from empty data: %w", ErrInvalidPadding)
 	}
 
 	if len(data)%cc.blockSize != 0 {
-		return nil, errors.New("data length must be multiple of block size")
+		return nil, fmt.Errorf("data length must be multiple of block size: %w", ErrInvalidBlockSize)
 	}
 
 	switch cc.padding {
@@ -1001,20 +1229,28 @@ func (cc *CipherContext) removePadding(data []byte) ([]byte, error) {
 
 	case ANSIX923, ISO10126:
 		paddingLen := int(data[len(data)-1])
-		if paddingLen > cc.blockSize || paddingLen > len(data) {
-			return nil, errors.New("invalid padding length")
+		if paddingLen == 0 || paddingLen > cc.blockSize || paddingLen > len(data) {
+			return nil, fmt.Errorf("%w: bad padding length", ErrInvalidPadding)
+		}
+
+		if cc.padding == ANSIX923 {
+			for i := len(data) - paddingLen; i < len(data)-1; i++ {
+				if data[i] != 0 {
+					return nil, fmt.Errorf("%w: ANSIX923 fill bytes must be zero", ErrInvalidPadding)
+				}
+			}
 		}
 		return data[:len(data)-paddingLen], nil
 
 	case PKCS7:
 		paddingLen := int(data[len(data)-1])
-		if paddingLen > cc.blockSize || paddingLen > len(data) {
-			return nil, errors.New("invalid padding length")
+		if paddingLen == 0 || paddingLen > cc.blockSize || paddingLen > len(data) {
+			return nil, fmt.Errorf("%w: bad padding length", ErrInvalidPadding)
 		}
 
 		for i := len(data) - paddingLen; i < len(data); i++ {
 			if data[i] != byte(paddingLen) {
-				return nil, errors.New("invalid PKCS7 padding")
+				return nil, fmt.Errorf("%w: PKCS7 bytes do not match padding length", ErrInvalidPadding)
 			}
 		}
 		return data[:len(data)-paddingLen], nil
@@ -1051,6 +1287,7 @@ type SimpleCipher struct {
 	blockSize   int
 	expander    KeyExpander
 	transformer RoundTransformer
+	destroyed   bool
 }
 
 func NewSimpleCipher(blockSize int, expander KeyExpander, transformer RoundTransformer) *SimpleCipher {
@@ -1062,17 +1299,34 @@ func NewSimpleCipher(blockSize int, expander KeyExpander, transformer RoundTrans
 }
 
 func (sc *SimpleCipher) SetKey(key []byte) error {
+	if sc.destroyed {
+		return ErrDestroyed
+	}
+
 	roundKeys, err := sc.expander.ExpandKey(key)
 	if err != nil {
 		return err
 	}
+	Zeroize(sc.roundKeys...)
 	sc.roundKeys = roundKeys
 	return nil
 }
 
+func (sc *SimpleCipher) Destroy() {
+	Zeroize(sc.roundKeys...)
+	sc.roundKeys = nil
+	sc.destroyed = true
+}
+
 func (sc *SimpleCipher) Encrypt(block []byte) ([]byte, error) {
+	if sc.destroyed {
+		return nil, ErrDestroyed
+	}
 	if len(block) != sc.blockSize {
-		return nil, fmt.Errorf("block size must be %d bytes", sc.blockSize)
+		return nil, &BlockSizeError{Cipher: "SimpleCipher", Size: len(block), Valid: []int{sc.blockSize}}
+	}
+	if len(sc.roundKeys) == 0 {
+		return nil, fmt.Errorf("round keys not set: %w", ErrKeyNotSet)
 	}
 
 	result := make([]byte, sc.blockSize)
@@ -1090,8 +1344,14 @@ func (sc *SimpleCipher) Encrypt(block []byte) ([]byte, error) {
 }
 
 func (sc *SimpleCipher) Decrypt(block []byte) ([]byte, error) {
+	if sc.destroyed {
+		return nil, ErrDestroyed
+	}
 	if len(block) != sc.blockSize {
-		return nil, fmt.Errorf("block size must be %d bytes", sc.blockSize)
+		return nil, &BlockSizeError{Cipher: "SimpleCipher", Size: len(block), Valid: []int{sc.blockSize}}
+	}
+	if len(sc.roundKeys) == 0 {
+		return nil, fmt.Errorf("round keys not set: %w", ErrKeyNotSet)
 	}
 
 	result := make([]byte, sc.blockSize)
@@ -0,0 +1,114 @@
+package interfaces_test
+
+import (
+	"bytes"
+	"context"
+	"lab1/deal"
+	"lab1/des"
+	"lab1/interfaces"
+	tripledes "lab1/tripleDes"
+	"testing"
+)
+
+var (
+	roundTripModes = []interfaces.CipherMode{
+		interfaces.ECB, interfaces.CBC, interfaces.PCBC, interfaces.CFB,
+		interfaces.OFB, interfaces.CTR, interfaces.RandomDelta,
+	}
+	roundTripPaddings = []interfaces.PaddingMode{
+		interfaces.Zeros, interfaces.ANSIX923, interfaces.PKCS7, interfaces.ISO10126,
+	}
+)
+
+type roundTripCipher struct {
+	name    string
+	keySize int
+	cipher  interfaces.BlockCipher
+}
+
+func newRoundTripCiphers(f *testing.F) []roundTripCipher {
+	desCipher, err := des.NewDES()
+	if err != nil {
+		f.Fatalf("NewDES: %v", err)
+	}
+
+	ede, err := tripledes.NewTripleDES(tripledes.EDE)
+	if err != nil {
+		f.Fatalf("NewTripleDES(EDE): %v", err)
+	}
+
+	eee, err := tripledes.NewTripleDES(tripledes.EEE)
+	if err != nil {
+		f.Fatalf("NewTripleDES(EEE): %v", err)
+	}
+
+	dealCipher, err := deal.NewDEAL(6)
+	if err != nil {
+		f.Fatalf("NewDEAL: %v", err)
+	}
+
+	bitsliced, err := des.NewBitslicedDES()
+	if err != nil {
+		f.Fatalf("NewBitslicedDES: %v", err)
+	}
+
+	return []roundTripCipher{
+		{"DES", 8, desCipher},
+		{"3DES-EDE", 24, ede},
+		{"3DES-EEE", 16, eee},
+		{"DEAL", 16, dealCipher},
+		{"bitsliced DES", 8, bitsliced},
+	}
+}
+
+func FuzzBlockCipherRoundTrip(f *testing.F) {
+	ciphers := newRoundTripCiphers(f)
+
+	for c := range ciphers {
+		for mode := range roundTripModes {
+			for padding := range roundTripPaddings {
+				f.Add(byte(c), byte(mode), byte(padding), []byte("0123456789abcdef01234567"), []byte("round trip input"))
+			}
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, cipherSelector, modeSelector, paddingSelector byte, keyMaterial, plaintext []byte) {
+		c := ciphers[int(cipherSelector)%len(ciphers)]
+		mode := roundTripModes[int(modeSelector)%len(roundTripModes)]
+		padding := roundTripPaddings[int(paddingSelector)%len(roundTripPaddings)]
+
+		if len(keyMaterial) < c.keySize || len(plaintext) > 256 {
+			t.Skip()
+		}
+
+		cc, err := interfaces.NewCipherContext(c.cipher, interfaces.CipherContextConfig{
+			Key:     keyMaterial[:c.keySize],
+			Mode:    mode,
+			Padding: padding,
+		})
+		if err != nil {
+			t.Fatalf("%s: NewCipherContext: %v", c.name, err)
+		}
+
+		ctx := context.Background()
+
+		encrypted, err := cc.EncryptBytes(ctx, plaintext)
+		if err != nil {
+			t.Fatalf("%s %v/%v: EncryptBytes: %v", c.name, mode, padding, err)
+		}
+
+		decrypted, err := cc.DecryptBytes(ctx, encrypted)
+		if err != nil {
+			t.Fatalf("%s %v/%v: DecryptBytes: %v", c.name, mode, padding, err)
+		}
+
+		expected := plaintext
+		if padding == interfaces.Zeros {
+			expected = bytes.TrimRight(plaintext, "\x00")
+		}
+
+		if !bytes.Equal(decrypted, expected) {
+			t.Fatalf("%s %v/%v: got %x, want %x", c.name, mode, padding, decrypted, expected)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package interfaces
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// streamChunkBlocks is how many blocks EncryptStream and DecryptStream
+// hold in memory at a time.
+const streamChunkBlocks = 4096
+
+// EncryptStream encrypts r into w in chunks of whole blocks and pads the
+// last one, so memory use does not grow with the input. The output is the
+// same as EncryptBytes on all of r. A single-use IV stays used once the
+// first chunk has been written, even if a later chunk fails.
+func (cc *CipherContext) EncryptStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	if cc.closed.Load() {
+		return fmt.Errorf("cipher context closed: %w", ErrDestroyed)
+	}
+	release, err := cc.reserveIV()
+	if err != nil {
+		return err
+	}
+	// Once ciphertext has been written the IV stays used, even on failure.
+	written := false
+	defer func() {
+		if !written {
+			release()
+		}
+	}()
+
+	buffer := make([]byte, streamChunkBlocks*cc.blockSize)
+	defer Zeroize(buffer)
+	iv := bytes.Clone(cc.iv)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := io.ReadFull(r, buffer)
+		final := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !final {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		plaintext := buffer[:n]
+		if final {
+			if plaintext, err = cc.applyPadding(plaintext); err != nil {
+				return err
+			}
+		}
+
+		ciphertext, err := cc.withIV(iv).encryptBlocks(ctx, plaintext)
+		if err != nil {
+			return err
+		}
+		written = true
+		if _, err := w.Write(ciphertext); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		iv = cc.nextIV(iv, plaintext, ciphertext)
+
+		if final {
+			return nil
+		}
+	}
+}
+
+// DecryptStream decrypts r into w chunk by chunk. The last block is held
+// back until r ends, since only it carries the padding; with Zeros padding
+// trailing zero bytes are held back as well. Plaintext before a padding
+// error has already been written.
+func (cc *CipherContext) DecryptStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	if cc.closed.Load() {
+		return fmt.Errorf("cipher context closed: %w", ErrDestroyed)
+	}
+
+	// RandomDelta stores a delta block after every ciphertext block.
+	unit := cc.blockSize
+	if cc.mode == RandomDelta {
+		unit *= 2
+	}
+
+	buffer := make([]byte, streamChunkBlocks*unit)
+	defer Zeroize(buffer)
+	iv := bytes.Clone(cc.iv)
+	held, zeros := 0, 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := io.ReadFull(r, buffer[held:])
+		final := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !final {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		ciphertext := buffer[:held+n]
+		if !final {
+			ciphertext = ciphertext[:len(ciphertext)-unit]
+		}
+
+		plaintext, err := cc.withIV(iv).decryptBlocks(ctx, ciphertext)
+		if err != nil {
+			return err
+		}
+
+		if final {
+			if plaintext, err = cc.removePadding(plaintext); err != nil {
+				return err
+			}
+			// Zeros padding strips every trailing zero, including held ones.
+			if len(plaintext) == 0 {
+				zeros = 0
+			}
+		} else {
+			iv = cc.nextIV(iv, plaintext, ciphertext)
+			copy(buffer, buffer[len(ciphertext):held+n])
+			held = unit
+		}
+
+		if err := writeHoldingZeros(w, plaintext, &zeros, cc.padding == Zeros && !final); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		Zeroize(plaintext)
+
+		if final {
+			return nil
+		}
+	}
+}
+
+// writeHoldingZeros writes the zero bytes held back so far and then data;
+// with hold, a trailing run of zeros in data is held back instead.
+func writeHoldingZeros(w io.Writer, data []byte, zeros *int, hold bool) error {
+	end := len(data)
+	if hold {
+		for end > 0 && data[end-1] == 0 {
+			end--
+		}
+		if end == 0 {
+			*zeros += len(data)
+			return nil
+		}
+	}
+
+	if *zeros > 0 {
+		if _, err := w.Write(make([]byte, *zeros)); err != nil {
+			return err
+		}
+		*zeros = 0
+	}
+	if _, err := w.Write(data[:end]); err != nil {
+		return err
+	}
+	*zeros = len(data) - end
+	return nil
+}
+
+// withIV returns a context that shares the cipher and settings of cc but
+// starts from iv, for continuing a chained mode in the next chunk.
+func (cc *CipherContext) withIV(iv []byte) *CipherContext {
+	return &CipherContext{
+		cipher:         cc.cipher,
+		mode:           cc.mode,
+		padding:        cc.padding,
+		iv:             iv,
+		additionalArgs: cc.additionalArgs,
+		blockSize:      cc.blockSize,
+		workers:        cc.workers,
+	}
+}
+
+// nextIV returns the chaining value after a chunk of whole blocks.
+func (cc *CipherContext) nextIV(iv, plaintext, ciphertext []byte) []byte {
+	if len(plaintext) == 0 || !cc.mode.RequiresIV() {
+		return iv
+	}
+
+	last := len(plaintext) - cc.blockSize
+	next := make([]byte, cc.blockSize)
+	switch cc.mode {
+	case CBC, CFB:
+		copy(next, ciphertext[last:])
+	case PCBC, OFB:
+		// PCBC chains P xor C; in OFB that is the last keystream block.
+		copy(next, plaintext[last:])
+		XorBytes(next, ciphertext[last:])
+	case CTR:
+		copy(next, iv)
+		incrementCounter(next, len(plaintext)/cc.blockSize)
+	}
+	return next
+}
@@ -0,0 +1,96 @@
+package interfaces_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"lab1/des"
+	"lab1/interfaces"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStreamMatchesBytes(t *testing.T) {
+	key := []byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	iv := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	ctx := context.Background()
+
+	// Sizes around the 4096-block stream chunk, and a plaintext whose
+	// trailing zeros cross it, which Zeros padding must still strip.
+	var plaintexts [][]byte
+	for _, size := range []int{0, 5, 8, 4096*8 - 1, 4096 * 8, 4096*8 + 8, 2*4096*8 + 13} {
+		plaintext := make([]byte, size)
+		for i := range plaintext {
+			plaintext[i] = byte(i*13 + 1)
+		}
+		plaintexts = append(plaintexts, plaintext)
+	}
+	zeros := make([]byte, 4096*8+40)
+	zeros[100] = 1
+	plaintexts = append(plaintexts, zeros)
+
+	for mode := interfaces.ECB; mode <= interfaces.RandomDelta; mode++ {
+		for _, padding := range []interfaces.PaddingMode{interfaces.Zeros, interfaces.PKCS7, interfaces.ISO10126} {
+			cipher, _ := des.NewDES()
+			cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{Key: key, Mode: mode, Padding: padding, IV: iv})
+			if err != nil {
+				t.Fatalf("NewCipherContext: %v", err)
+			}
+
+			for _, plaintext := range plaintexts {
+				var ciphertext bytes.Buffer
+				if err := cc.EncryptStream(ctx, iotest.HalfReader(bytes.NewReader(plaintext)), &ciphertext); err != nil {
+					t.Fatalf("%v/%v, %d bytes: EncryptStream: %v", mode, padding, len(plaintext), err)
+				}
+
+				// Random padding and deltas only allow a round trip check.
+				if mode != interfaces.RandomDelta && padding != interfaces.ISO10126 {
+					want, _ := cc.EncryptBytes(ctx, plaintext)
+					if !bytes.Equal(ciphertext.Bytes(), want) {
+						t.Errorf("%v/%v, %d bytes: stream ciphertext differs from EncryptBytes", mode, padding, len(plaintext))
+					}
+				}
+
+				want, err := cc.DecryptBytes(ctx, ciphertext.Bytes())
+				if err != nil {
+					t.Fatalf("%v/%v: DecryptBytes: %v", mode, padding, err)
+				}
+				var decrypted bytes.Buffer
+				if err := cc.DecryptStream(ctx, iotest.HalfReader(bytes.NewReader(ciphertext.Bytes())), &decrypted); err != nil {
+					t.Fatalf("%v/%v, %d bytes: DecryptStream: %v", mode, padding, len(plaintext), err)
+				}
+				if !bytes.Equal(decrypted.Bytes(), want) {
+					t.Errorf("%v/%v, %d bytes: DecryptStream gives %d bytes, DecryptBytes %d", mode, padding, len(plaintext), decrypted.Len(), len(want))
+				}
+			}
+		}
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	cipher, _ := des.NewDES()
+	cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{Key: make([]byte, 8), Mode: interfaces.CBC, Padding: interfaces.PKCS7})
+	if err != nil {
+		t.Fatalf("NewCipherContext: %v", err)
+	}
+
+	ctx := context.Background()
+	var out bytes.Buffer
+	if err := cc.DecryptStream(ctx, bytes.NewReader(make([]byte, 12)), &out); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("DecryptStream of 12 bytes: %v", err)
+	}
+	if err := cc.DecryptStream(ctx, bytes.NewReader(nil), &out); !errors.Is(err, interfaces.ErrInvalidPadding) {
+		t.Errorf("DecryptStream of nothing: %v", err)
+	}
+
+	readErr := errors.New("disk on fire")
+	if err := cc.EncryptStream(ctx, iotest.ErrReader(readErr), &out); !errors.Is(err, readErr) {
+		t.Errorf("EncryptStream with a failing reader: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := cc.EncryptStream(cancelled, bytes.NewReader(make([]byte, 64)), &out); !errors.Is(err, context.Canceled) {
+		t.Errorf("EncryptStream with a cancelled context: %v", err)
+	}
+}
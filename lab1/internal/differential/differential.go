@@ -0,0 +1,172 @@
+// Package differential compares the ciphers and modes of this module with
+// the standard library, for the fuzz tests of the cipher packages.
+package differential
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"fmt"
+	"lab1/interfaces"
+)
+
+var ComparedModes = []interfaces.CipherMode{
+	interfaces.ECB,
+	interfaces.CBC,
+	interfaces.CFB,
+	interfaces.OFB,
+	interfaces.CTR,
+}
+
+type Divergence struct {
+	Operation string
+	Mode      string
+	Key       []byte
+	IV        []byte
+	Input     []byte
+	Got       []byte
+	Want      []byte
+}
+
+func (d *Divergence) Error() string {
+	return fmt.Sprintf("%s %s diverges from reference: key=%x iv=%x input=%x got=%x want=%x",
+		d.Mode, d.Operation, d.Key, d.IV, d.Input, d.Got, d.Want)
+}
+
+func CompareBlock(ours interfaces.BlockCipher, reference cipher.Block, key, block []byte) error {
+	if ours.BlockSize() != reference.BlockSize() {
+		return fmt.Errorf("block size mismatch: %d vs %d", ours.BlockSize(), reference.BlockSize())
+	}
+
+	if len(block) != ours.BlockSize() {
+		return fmt.Errorf("block must be %d bytes (got %d)", ours.BlockSize(), len(block))
+	}
+
+	if err := ours.SetKey(key); err != nil {
+		return fmt.Errorf("failed to set key: %w", err)
+	}
+
+	want := make([]byte, len(block))
+	reference.Encrypt(want, block)
+
+	got, err := ours.Encrypt(block)
+	if err != nil {
+		return fmt.Errorf("encryption failed: %w", err)
+	}
+	if !bytes.Equal(got, want) {
+		return &Divergence{Operation: "encrypt", Mode: "block", Key: key, Input: block, Got: got, Want: want}
+	}
+
+	reference.Decrypt(want, block)
+
+	got, err = ours.Decrypt(block)
+	if err != nil {
+		return fmt.Errorf("decryption failed: %w", err)
+	}
+	if !bytes.Equal(got, want) {
+		return &Divergence{Operation: "decrypt", Mode: "block", Key: key, Input: block, Got: got, Want: want}
+	}
+
+	return nil
+}
+
+func CompareModes(ours interfaces.BlockCipher, reference cipher.Block, key, iv, plaintext []byte) error {
+	for _, mode := range ComparedModes {
+		if err := CompareMode(ours, reference, mode, key, iv, plaintext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CompareMode(ours interfaces.BlockCipher, reference cipher.Block, mode interfaces.CipherMode, key, iv, plaintext []byte) error {
+	blockSize := reference.BlockSize()
+	if len(iv) != blockSize {
+		return fmt.Errorf("IV must be %d bytes (got %d)", blockSize, len(iv))
+	}
+
+	cc, err := interfaces.NewCipherContext(ours, interfaces.CipherContextConfig{
+		Key:     key,
+		Mode:    mode,
+		Padding: interfaces.PKCS7,
+		IV:      iv,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cipher context: %w", err)
+	}
+
+	padded := pkcs7Pad(plaintext, blockSize)
+	want, err := referenceEncrypt(reference, mode, iv, padded)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	got, err := cc.EncryptBytes(ctx, plaintext)
+	if err != nil {
+		return fmt.Errorf("%v encryption failed: %w", mode, err)
+	}
+	if !bytes.Equal(got, want) {
+		return &Divergence{Operation: "encrypt", Mode: mode.String(), Key: key, IV: iv, Input: plaintext, Got: got, Want: want}
+	}
+
+	decrypted, err := cc.DecryptBytes(ctx, want)
+	if err != nil {
+		return fmt.Errorf("%v decryption failed: %w", mode, err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		return &Divergence{Operation: "decrypt", Mode: mode.String(), Key: key, IV: iv, Input: want, Got: decrypted, Want: plaintext}
+	}
+
+	return nil
+}
+
+func referenceEncrypt(reference cipher.Block, mode interfaces.CipherMode, iv, padded []byte) ([]byte, error) {
+	blockSize := reference.BlockSize()
+	result := make([]byte, len(padded))
+
+	switch mode {
+	case interfaces.ECB:
+		for i := 0; i < len(padded); i += blockSize {
+			reference.Encrypt(result[i:i+blockSize], padded[i:i+blockSize])
+		}
+	case interfaces.CBC:
+		cipher.NewCBCEncrypter(reference, iv).CryptBlocks(result, padded)
+	case interfaces.CFB:
+		cipher.NewCFBEncrypter(reference, iv).XORKeyStream(result, padded)
+	case interfaces.OFB:
+		cipher.NewOFB(reference, iv).XORKeyStream(result, padded)
+	case interfaces.CTR:
+		cipher.NewCTR(reference, iv).XORKeyStream(result, padded)
+	default:
+		return nil, fmt.Errorf("no reference implementation for mode %v", mode)
+	}
+
+	return result, nil
+}
+
+// Fit zero-pads or truncates data to the smallest of sizes that holds it,
+// or to the largest, so that every fuzz input gives a valid key or block.
+func Fit(data []byte, sizes ...int) []byte {
+	size := sizes[len(sizes)-1]
+	for _, s := range sizes {
+		if len(data) <= s {
+			size = s
+			break
+		}
+	}
+	fitted := make([]byte, size)
+	copy(fitted, data)
+	return fitted
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	paddingLen := blockSize - len(data)%blockSize
+	padded := make([]byte, len(data)+paddingLen)
+	copy(padded, data)
+	for i := len(data); i < len(padded); i++ {
+		padded[i] = byte(paddingLen)
+	}
+	return padded
+}
@@ -0,0 +1,25 @@
+package differential
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	cases := []struct {
+		data  []byte
+		sizes []int
+		want  []byte
+	}{
+		{nil, []int{4}, []byte{0, 0, 0, 0}},
+		{[]byte{1, 2}, []int{4}, []byte{1, 2, 0, 0}},
+		{[]byte{1, 2, 3, 4, 5}, []int{4}, []byte{1, 2, 3, 4}},
+		{[]byte{1, 2, 3}, []int{2, 4, 6}, []byte{1, 2, 3, 0}},
+		{[]byte{1, 2, 3, 4, 5, 6, 7}, []int{2, 4, 6}, []byte{1, 2, 3, 4, 5, 6}},
+	}
+	for _, c := range cases {
+		if got := Fit(c.data, c.sizes...); !bytes.Equal(got, c.want) {
+			t.Errorf("Fit(%v, %v) = %v, want %v", c.data, c.sizes, got, c.want)
+		}
+	}
+}
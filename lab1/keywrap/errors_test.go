@@ -0,0 +1,85 @@
+package keywrap
+
+import (
+	"bytes"
+	"errors"
+	"lab1/deal"
+	"lab1/des"
+	"lab1/interfaces"
+	"testing"
+)
+
+func TestKeyWrapErrors(t *testing.T) {
+	desCipher, err := des.NewDES()
+	if err != nil {
+		t.Fatalf("NewDES: %v", err)
+	}
+	var blockErr *interfaces.BlockSizeError
+	if _, err := NewKeyWrapper(desCipher, make([]byte, 8)); !errors.As(err, &blockErr) || blockErr.Size != 8 {
+		t.Errorf("DES KEK cipher: got %v, want BlockSizeError with size 8", err)
+	}
+
+	dealCipher, err := deal.NewDEAL(6)
+	if err != nil {
+		t.Fatalf("NewDEAL: %v", err)
+	}
+	kw, err := NewKeyWrapper(dealCipher, []byte("0123456789ABCDEF"))
+	if err != nil {
+		t.Fatalf("NewKeyWrapper: %v", err)
+	}
+
+	keyData := []byte("sixteen byte key")
+	for _, c := range []struct {
+		name   string
+		wrap   func([]byte) ([]byte, error)
+		unwrap func([]byte) ([]byte, error)
+		data   []byte
+	}{
+		{"Wrap", kw.Wrap, kw.Unwrap, keyData},
+		{"WrapWithPadding", kw.WrapWithPadding, kw.UnwrapWithPadding, keyData[:5]},
+		{"WrapWithPadding", kw.WrapWithPadding, kw.UnwrapWithPadding, keyData[:13]},
+	} {
+		wrapped, err := c.wrap(c.data)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		unwrapped, err := c.unwrap(wrapped)
+		if err != nil || !bytes.Equal(unwrapped, c.data) {
+			t.Fatalf("%s round trip: got %x, %v", c.name, unwrapped, err)
+		}
+
+		wrapped[len(wrapped)-1] ^= 0x01
+		if _, err := c.unwrap(wrapped); !errors.Is(err, interfaces.ErrAuthFailed) {
+			t.Errorf("%s tampered: got %v, want ErrAuthFailed", c.name, err)
+		}
+
+		if _, err := c.unwrap(wrapped[:len(wrapped)-1]); !errors.Is(err, interfaces.ErrInvalidKeySize) {
+			t.Errorf("%s truncated: got %v, want ErrInvalidKeySize", c.name, err)
+		}
+	}
+
+	if _, err := kw.Wrap(keyData[:8]); !errors.Is(err, interfaces.ErrInvalidKeySize) {
+		t.Errorf("Wrap 8 bytes: got %v, want ErrInvalidKeySize", err)
+	}
+	if _, err := kw.WrapWithPadding(nil); !errors.Is(err, interfaces.ErrInvalidKeySize) {
+		t.Errorf("WrapWithPadding empty: got %v, want ErrInvalidKeySize", err)
+	}
+}
+
+func TestKeyWrapDestroy(t *testing.T) {
+	cipher, err := deal.NewDEAL(6)
+	if err != nil {
+		t.Fatalf("NewDEAL: %v", err)
+	}
+	kw, err := NewKeyWrapper(cipher, []byte("0123456789ABCDEF"))
+	if err != nil {
+		t.Fatalf("NewKeyWrapper: %v", err)
+	}
+
+	kw.Destroy()
+
+	if _, err := kw.Wrap([]byte("sixteen byte key")); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Wrap after Destroy: got %v, want ErrDestroyed", err)
+	}
+}
@@ -0,0 +1,212 @@
+package keywrap
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"lab1/interfaces"
+)
+
+const (
+	KeyWrapBlockSize = 16
+	semiblockSize    = 8
+	wrapRounds       = 6
+)
+
+var DefaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+var AlternativeIVPrefix = []byte{0xA6, 0x59, 0x59, 0xA6}
+
+type KeyWrapper struct {
+	cipher interfaces.BlockCipher
+}
+
+func NewKeyWrapper(cipher interfaces.BlockCipher, kek []byte) (*KeyWrapper, error) {
+	if cipher == nil {
+		return nil, errors.New("cipher cannot be nil")
+	}
+
+	if cipher.BlockSize() != KeyWrapBlockSize {
+		return nil, &interfaces.BlockSizeError{Cipher: "key wrap", Size: cipher.BlockSize(), Valid: []int{KeyWrapBlockSize}}
+	}
+
+	if err := cipher.SetKey(kek); err != nil {
+		return nil, fmt.Errorf("failed to set KEK: %w", err)
+	}
+
+	return &KeyWrapper{cipher: cipher}, nil
+}
+
+func (kw *KeyWrapper) Destroy() {
+	if destroyer, ok := kw.cipher.(interfaces.Destroyer); ok {
+		destroyer.Destroy()
+	}
+}
+
+func (kw *KeyWrapper) Wrap(keyData []byte) ([]byte, error) {
+	if len(keyData) < 2*semiblockSize || len(keyData)%semiblockSize != 0 {
+		return nil, fmt.Errorf("Wrap: key data must be a multiple of %d bytes and at least %d bytes: %w", semiblockSize, 2*semiblockSize, interfaces.ErrInvalidKeySize)
+	}
+
+	return kw.wrap(DefaultIV, keyData)
+}
+
+func (kw *KeyWrapper) Unwrap(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 3*semiblockSize || len(wrapped)%semiblockSize != 0 {
+		return nil, fmt.Errorf("Unwrap: wrapped key must be a multiple of %d bytes and at least %d bytes: %w", semiblockSize, 3*semiblockSize, interfaces.ErrInvalidKeySize)
+	}
+
+	iv, keyData, err := kw.unwrap(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare(iv, DefaultIV) != 1 {
+		interfaces.Zeroize(keyData)
+		return nil, fmt.Errorf("Unwrap: integrity check failed: %w", interfaces.ErrAuthFailed)
+	}
+
+	return keyData, nil
+}
+
+func (kw *KeyWrapper) WrapWithPadding(keyData []byte) ([]byte, error) {
+	if len(keyData) == 0 {
+		return nil, fmt.Errorf("WrapWithPadding: key data cannot be empty: %w", interfaces.ErrInvalidKeySize)
+	}
+
+	if uint64(len(keyData)) > 0xFFFFFFFF {
+		return nil, fmt.Errorf("WrapWithPadding: key data is too long: %w", interfaces.ErrInvalidKeySize)
+	}
+
+	aiv := make([]byte, semiblockSize)
+	copy(aiv[:4], AlternativeIVPrefix)
+	binary.BigEndian.PutUint32(aiv[4:], uint32(len(keyData)))
+
+	paddedLen := (len(keyData) + semiblockSize - 1) / semiblockSize * semiblockSize
+	padded := make([]byte, paddedLen)
+	copy(padded, keyData)
+	defer interfaces.Zeroize(padded)
+
+	if paddedLen == semiblockSize {
+		block := make([]byte, KeyWrapBlockSize)
+		defer interfaces.Zeroize(block)
+		copy(block[:semiblockSize], aiv)
+		copy(block[semiblockSize:], padded)
+		return kw.cipher.Encrypt(block)
+	}
+
+	return kw.wrap(aiv, padded)
+}
+
+func (kw *KeyWrapper) UnwrapWithPadding(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 2*semiblockSize || len(wrapped)%semiblockSize != 0 {
+		return nil, fmt.Errorf("UnwrapWithPadding: wrapped key must be a multiple of %d bytes and at least %d bytes: %w", semiblockSize, 2*semiblockSize, interfaces.ErrInvalidKeySize)
+	}
+
+	var aiv, padded []byte
+	if len(wrapped) == 2*semiblockSize {
+		block, err := kw.cipher.Decrypt(wrapped)
+		if err != nil {
+			return nil, err
+		}
+		aiv, padded = block[:semiblockSize], block[semiblockSize:]
+	} else {
+		var err error
+		aiv, padded, err = kw.unwrap(wrapped)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	valid := subtle.ConstantTimeCompare(aiv[:4], AlternativeIVPrefix)
+
+	length := int(binary.BigEndian.Uint32(aiv[4:]))
+	if length <= len(padded)-semiblockSize || length > len(padded) {
+		valid = 0
+		length = len(padded)
+	}
+
+	var paddingBits byte
+	for _, b := range padded[length:] {
+		paddingBits |= b
+	}
+	valid &= subtle.ConstantTimeByteEq(paddingBits, 0)
+
+	if valid != 1 {
+		interfaces.Zeroize(padded)
+		return nil, fmt.Errorf("UnwrapWithPadding: integrity check failed: %w", interfaces.ErrAuthFailed)
+	}
+
+	return padded[:length], nil
+}
+
+func (kw *KeyWrapper) wrap(iv, keyData []byte) ([]byte, error) {
+	n := len(keyData) / semiblockSize
+
+	result := make([]byte, len(keyData)+semiblockSize)
+	copy(result[:semiblockSize], iv)
+	copy(result[semiblockSize:], keyData)
+
+	a := result[:semiblockSize]
+	block := make([]byte, KeyWrapBlockSize)
+	defer interfaces.Zeroize(block)
+
+	for j := 0; j < wrapRounds; j++ {
+		for i := 1; i <= n; i++ {
+			r := result[i*semiblockSize : (i+1)*semiblockSize]
+			copy(block[:semiblockSize], a)
+			copy(block[semiblockSize:], r)
+
+			encrypted, err := kw.cipher.Encrypt(block)
+			if err != nil {
+				return nil, err
+			}
+
+			copy(a, encrypted[:semiblockSize])
+			xorCounter(a, uint64(n*j+i))
+			copy(r, encrypted[semiblockSize:])
+			interfaces.Zeroize(encrypted)
+		}
+	}
+
+	return result, nil
+}
+
+func (kw *KeyWrapper) unwrap(wrapped []byte) ([]byte, []byte, error) {
+	n := len(wrapped)/semiblockSize - 1
+
+	result := make([]byte, len(wrapped))
+	copy(result, wrapped)
+
+	a := result[:semiblockSize]
+	block := make([]byte, KeyWrapBlockSize)
+	defer interfaces.Zeroize(block)
+
+	for j := wrapRounds - 1; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			r := result[i*semiblockSize : (i+1)*semiblockSize]
+			copy(block[:semiblockSize], a)
+			xorCounter(block[:semiblockSize], uint64(n*j+i))
+			copy(block[semiblockSize:], r)
+
+			decrypted, err := kw.cipher.Decrypt(block)
+			if err != nil {
+				interfaces.Zeroize(result)
+				return nil, nil, err
+			}
+
+			copy(a, decrypted[:semiblockSize])
+			copy(r, decrypted[semiblockSize:])
+			interfaces.Zeroize(decrypted)
+		}
+	}
+
+	return a, result[semiblockSize:], nil
+}
+
+func xorCounter(a []byte, t uint64) {
+	var counter [semiblockSize]byte
+	binary.BigEndian.PutUint64(counter[:], t)
+	interfaces.XorBytes(a, counter[:])
+}
@@ -0,0 +1,95 @@
+package keywrap
+
+import (
+	"bytes"
+	"lab1/deal"
+	"lab1/interfaces"
+	"math/rand/v2"
+	"testing"
+)
+
+// referenceWrap is the wrapping function W of NIST SP 800-38F, which keeps
+// the semiblocks in a shift register instead of indexing them as RFC 3394
+// does; both must give the same bytes.
+func referenceWrap(t *testing.T, cipher interfaces.BlockCipher, iv, keyData []byte) []byte {
+	t.Helper()
+	a := append([]byte(nil), iv...)
+	var r [][]byte
+	for i := 0; i < len(keyData); i += semiblockSize {
+		r = append(r, append([]byte(nil), keyData[i:i+semiblockSize]...))
+	}
+
+	for step := 1; step <= wrapRounds*len(r); step++ {
+		block, err := cipher.Encrypt(append(append([]byte(nil), a...), r[0]...))
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		a = block[:semiblockSize]
+		xorCounter(a, uint64(step))
+		r = append(r[1:], block[semiblockSize:])
+	}
+	return bytes.Join(append([][]byte{a}, r...), nil)
+}
+
+func TestKeyWrapDEAL(t *testing.T) {
+	random := rand.NewChaCha8([32]byte{26})
+	kek := make([]byte, 16)
+	random.Read(kek)
+
+	cipher, err := deal.NewDEAL(6)
+	if err != nil {
+		t.Fatalf("NewDEAL: %v", err)
+	}
+	kw, err := NewKeyWrapper(cipher, kek)
+	if err != nil {
+		t.Fatalf("NewKeyWrapper: %v", err)
+	}
+	reference, _ := deal.NewDEAL(6)
+	if err := reference.SetKey(kek); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	for _, size := range []int{16, 24, 32, 64} {
+		keyData := make([]byte, size)
+		random.Read(keyData)
+
+		wrapped, err := kw.Wrap(keyData)
+		if err != nil {
+			t.Fatalf("Wrap %d bytes: %v", size, err)
+		}
+		if want := referenceWrap(t, reference, DefaultIV, keyData); !bytes.Equal(wrapped, want) {
+			t.Errorf("Wrap %d bytes: got %X, want %X", size, wrapped, want)
+		}
+		unwrapped, err := kw.Unwrap(wrapped)
+		if err != nil || !bytes.Equal(unwrapped, keyData) {
+			t.Errorf("Unwrap %d bytes: got %X, %v", size, unwrapped, err)
+		}
+	}
+
+	for _, size := range []int{1, 7, 8, 9, 20, 31} {
+		keyData := make([]byte, size)
+		random.Read(keyData)
+
+		aiv := append(append([]byte(nil), AlternativeIVPrefix...), 0, 0, 0, byte(size))
+		padded := make([]byte, (size+7)/8*8)
+		copy(padded, keyData)
+		var want []byte
+		if len(padded) == semiblockSize {
+			want, _ = reference.Encrypt(append(aiv, padded...))
+		} else {
+			want = referenceWrap(t, reference, aiv, padded)
+		}
+
+		wrapped, err := kw.WrapWithPadding(keyData)
+		if err != nil {
+			t.Fatalf("WrapWithPadding %d bytes: %v", size, err)
+		}
+		if !bytes.Equal(wrapped, want) {
+			t.Errorf("WrapWithPadding %d bytes: got %X, want %X", size, wrapped, want)
+		}
+		unwrapped, err := kw.UnwrapWithPadding(wrapped)
+		if err != nil || !bytes.Equal(unwrapped, keyData) {
+			t.Errorf("UnwrapWithPadding %d bytes: got %X, %v", size, unwrapped, err)
+		}
+	}
+}
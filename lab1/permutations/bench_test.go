@@ -0,0 +1,29 @@
+package permutations
+
+import "testing"
+
+func BenchmarkBitPermutations(b *testing.B) {
+	src := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := BitPermutations(src, desIP, HighToLow, FirstBit); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPermutationApply(b *testing.B) {
+	p, err := NewPermutation(desIP, 8, HighToLow, FirstBit)
+	if err != nil {
+		b.Fatalf("NewPermutation: %v", err)
+	}
+	src, dst := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, make([]byte, 8)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := p.Apply(dst, src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package permutations
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Permutation is a P-block compiled for a fixed input size. It gives the
+// same result as BitPermutations with the same arguments, but every input
+// byte selects a precomputed slice of output bytes, so Apply costs one
+// lookup and OR per input and output byte and does not allocate.
+type Permutation struct {
+	pBlock     []int
+	inputSize  int
+	indexMode  IndexMode
+	initialBit InitialBit
+
+	outputSize int
+	// sources[p] is the input bit, counted from the most significant bit
+	// of the input, that lands on output bit p, or -1 for padding.
+	sources []int
+	// tables[(i*256+v)*outputSize:] is what input byte i with value v
+	// contributes to the output. Bytes that feed no output bit are skipped.
+	tables []byte
+	used   []int
+}
+
+// NewPermutation compiles pBlock for inputs of inputSize bytes. The output
+// has len(pBlock) bits; when that is not a multiple of 8, the unused bits
+// of the last byte (HighToLow) or the first byte (LowToHigh) are zero.
+func NewPermutation(pBlock []int, inputSize int, indexMode IndexMode, initialBit InitialBit) (*Permutation, error) {
+	if inputSize <= 0 {
+		return nil, fmt.Errorf("input size must be positive (got %d)", inputSize)
+	}
+	if len(pBlock) == 0 {
+		return nil, errors.New("p-block is empty")
+	}
+	if indexMode != LowToHigh && indexMode != HighToLow {
+		return nil, fmt.Errorf("unknown index mode %d", indexMode)
+	}
+	if initialBit != ZeroBit && initialBit != FirstBit {
+		return nil, fmt.Errorf("initial bit must be 0 or 1 (got %d)", initialBit)
+	}
+
+	totalBits := inputSize * 8
+	outputSize := (len(pBlock) + 7) / 8
+	p := &Permutation{
+		pBlock:     append([]int(nil), pBlock...),
+		inputSize:  inputSize,
+		indexMode:  indexMode,
+		initialBit: initialBit,
+		outputSize: outputSize,
+		sources:    make([]int, outputSize*8),
+	}
+	for i := range p.sources {
+		p.sources[i] = -1
+	}
+
+	for index, bit := range pBlock {
+		if initialBit == FirstBit {
+			bit--
+		}
+		if bit < 0 || bit >= totalBits {
+			return nil, fmt.Errorf("pBlock[%d] = %d out of range for %d input bits", index, pBlock[index], totalBits)
+		}
+		p.sources[physicalIndex(indexMode, index, outputSize*8)] = physicalIndex(indexMode, bit, totalBits)
+	}
+
+	p.compile()
+	return p, nil
+}
+
+func physicalIndex(indexMode IndexMode, bit, totalBits int) int {
+	if indexMode == LowToHigh {
+		return totalBits - bit - 1
+	}
+	return bit
+}
+
+func (p *Permutation) compile() {
+	p.tables = make([]byte, p.inputSize*256*p.outputSize)
+	used := make([]bool, p.inputSize)
+
+	for output, source := range p.sources {
+		if source < 0 {
+			continue
+		}
+		inputByte, inputBit := source/8, source%8
+		used[inputByte] = true
+		for value := range 256 {
+			if value>>(7-inputBit)&1 != 0 {
+				p.tables[(inputByte*256+value)*p.outputSize+output/8] |= 1 << (7 - output%8)
+			}
+		}
+	}
+
+	for i, u := range used {
+		if u {
+			p.used = append(p.used, i)
+		}
+	}
+}
+
+// Apply writes the permuted src to dst[:OutputSize()].
+func (p *Permutation) Apply(dst, src []byte) error {
+	if len(src) != p.inputSize {
+		return fmt.Errorf("input has %d bytes, want %d", len(src), p.inputSize)
+	}
+	if len(dst) < p.outputSize {
+		return fmt.Errorf("output has %d bytes, want at least %d", len(dst), p.outputSize)
+	}
+
+	dst = dst[:p.outputSize]
+	clear(dst)
+	for _, i := range p.used {
+		offset := (i*256 + int(src[i])) * p.outputSize
+		row := p.tables[offset : offset+p.outputSize]
+		for j := range dst {
+			dst[j] |= row[j]
+		}
+	}
+	return nil
+}
+
+func (p *Permutation) InputSize() int {
+	return p.inputSize
+}
+
+// OutputSize is the output length in bytes, rounded up from OutputBits.
+func (p *Permutation) OutputSize() int {
+	return p.outputSize
+}
+
+func (p *Permutation) OutputBits() int {
+	return len(p.pBlock)
+}
+
+// PBlock returns a copy of the P-block the permutation was compiled from.
+func (p *Permutation) PBlock() []int {
+	return append([]int(nil), p.pBlock...)
+}
+
+// IsBijective reports whether every input bit reaches exactly one output
+// bit and the output is as wide as the input.
+func (p *Permutation) IsBijective() bool {
+	if len(p.pBlock) != p.inputSize*8 {
+		return false
+	}
+	seen := make([]bool, p.inputSize*8)
+	for _, source := range p.sources {
+		if source < 0 || seen[source] {
+			return false
+		}
+		seen[source] = true
+	}
+	return true
+}
+
+// Inverse returns the permutation that undoes p, in the same index mode
+// and initial bit.
+func (p *Permutation) Inverse() (*Permutation, error) {
+	if !p.IsBijective() {
+		return nil, errors.New("only a bijective permutation has an inverse")
+	}
+
+	offset := 0
+	if p.initialBit == FirstBit {
+		offset = 1
+	}
+	inverse := make([]int, len(p.pBlock))
+	for index, bit := range p.pBlock {
+		inverse[bit-offset] = index + offset
+	}
+	return NewPermutation(inverse, p.inputSize, p.indexMode, p.initialBit)
+}
+
+// Compose returns the permutation that applies first and then second, in
+// the index mode and initial bit of second. second must read exactly the
+// output of first and may not read its padding bits.
+func Compose(first, second *Permutation) (*Permutation, error) {
+	if second.inputSize != first.outputSize {
+		return nil, fmt.Errorf("second permutation reads %d bytes, first writes %d", second.inputSize, first.outputSize)
+	}
+
+	totalBits := first.inputSize * 8
+	pBlock := make([]int, len(second.pBlock))
+	for index := range pBlock {
+		middle := second.sources[physicalIndex(second.indexMode, index, second.outputSize*8)]
+		source := first.sources[middle]
+		if source < 0 {
+			return nil, fmt.Errorf("output bit %d reads a padding bit of the first permutation", index)
+		}
+		pBlock[index] = physicalIndex(second.indexMode, source, totalBits)
+		if second.initialBit == FirstBit {
+			pBlock[index]++
+		}
+	}
+	return NewPermutation(pBlock, first.inputSize, second.indexMode, second.initialBit)
+}
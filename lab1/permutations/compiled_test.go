@@ -0,0 +1,201 @@
+package permutations
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestPermutationMatchesBitPermutations(t *testing.T) {
+	random := rand.New(rand.NewChaCha8([32]byte{48}))
+
+	for i := 0; i < 200; i++ {
+		inputSize := 1 + random.IntN(8)
+		outputBits := 1 + random.IntN(70)
+		indexMode := IndexMode(random.IntN(2))
+		initialBit := InitialBit(random.IntN(2))
+
+		pBlock := make([]int, outputBits)
+		for j := range pBlock {
+			pBlock[j] = random.IntN(inputSize*8) + int(initialBit)
+		}
+		src := make([]byte, inputSize)
+		for j := range src {
+			src[j] = byte(random.Uint32())
+		}
+
+		p, err := NewPermutation(pBlock, inputSize, indexMode, initialBit)
+		if err != nil {
+			t.Fatalf("NewPermutation: %v", err)
+		}
+		want, err := BitPermutations(src, pBlock, indexMode, initialBit)
+		if err != nil {
+			t.Fatalf("BitPermutations: %v", err)
+		}
+
+		// Stale bytes in dst must be overwritten.
+		dst := bytes.Repeat([]byte{0xA5}, p.OutputSize()+1)
+		if err := p.Apply(dst, src); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+		if !bytes.Equal(dst[:p.OutputSize()], want) || p.OutputBits() != outputBits {
+			t.Fatalf("pBlock %v mode %d initial %d on %X: got %X, want %X", pBlock, indexMode, initialBit, src, dst[:p.OutputSize()], want)
+		}
+	}
+}
+
+// The DES initial and final permutations are inverses.
+var (
+	desIP = []int{
+		58, 50, 42, 34, 26, 18, 10, 2, 60, 52, 44, 36, 28, 20, 12, 4,
+		62, 54, 46, 38, 30, 22, 14, 6, 64, 56, 48, 40, 32, 24, 16, 8,
+		57, 49, 41, 33, 25, 17, 9, 1, 59, 51, 43, 35, 27, 19, 11, 3,
+		61, 53, 45, 37, 29, 21, 13, 5, 63, 55, 47, 39, 31, 23, 15, 7,
+	}
+	desFP = []int{
+		40, 8, 48, 16, 56, 24, 64, 32, 39, 7, 47, 15, 55, 23, 63, 31,
+		38, 6, 46, 14, 54, 22, 62, 30, 37, 5, 45, 13, 53, 21, 61, 29,
+		36, 4, 44, 12, 52, 20, 60, 28, 35, 3, 43, 11, 51, 19, 59, 27,
+		34, 2, 42, 10, 50, 18, 58, 26, 33, 1, 41, 9, 49, 17, 57, 25,
+	}
+)
+
+func TestPermutationInverse(t *testing.T) {
+	ip, err := NewPermutation(desIP, 8, HighToLow, FirstBit)
+	if err != nil {
+		t.Fatalf("NewPermutation: %v", err)
+	}
+	if !ip.IsBijective() {
+		t.Fatal("IP is not bijective")
+	}
+
+	fp, err := ip.Inverse()
+	if err != nil {
+		t.Fatalf("Inverse: %v", err)
+	}
+	if !slices.Equal(fp.PBlock(), desFP) {
+		t.Errorf("inverse of IP is %v", fp.PBlock())
+	}
+
+	identity, err := Compose(ip, fp)
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	for i, bit := range identity.PBlock() {
+		if bit != i+1 {
+			t.Fatalf("IP then FP moves bit %d to %d", bit, i+1)
+		}
+	}
+
+	// Inverting in LowToHigh mode and with zero-based indices works too.
+	shifted := make([]int, len(desIP))
+	for i, bit := range desIP {
+		shifted[i] = bit - 1
+	}
+	low, _ := NewPermutation(shifted, 8, LowToHigh, ZeroBit)
+	inverse, err := low.Inverse()
+	if err != nil {
+		t.Fatalf("Inverse: %v", err)
+	}
+	src := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	middle, out := make([]byte, 8), make([]byte, 8)
+	low.Apply(middle, src)
+	inverse.Apply(out, middle)
+	if !bytes.Equal(out, src) {
+		t.Errorf("LowToHigh round trip: %X", out)
+	}
+}
+
+func TestPermutationCompose(t *testing.T) {
+	random := rand.New(rand.NewChaCha8([32]byte{49}))
+
+	for i := 0; i < 50; i++ {
+		mode := IndexMode(random.IntN(2))
+		// 40 bits to 36 to 20; in both modes bits 0..35 of the middle are real.
+		firstBlock := make([]int, 36)
+		for j := range firstBlock {
+			firstBlock[j] = random.IntN(40)
+		}
+		secondBlock := make([]int, 20)
+		for j := range secondBlock {
+			secondBlock[j] = random.IntN(36)
+		}
+
+		first, err := NewPermutation(firstBlock, 5, mode, ZeroBit)
+		if err != nil {
+			t.Fatalf("NewPermutation: %v", err)
+		}
+		second, err := NewPermutation(secondBlock, 5, mode, ZeroBit)
+		if err != nil {
+			t.Fatalf("NewPermutation: %v", err)
+		}
+		composed, err := Compose(first, second)
+		if err != nil {
+			t.Fatalf("Compose: %v", err)
+		}
+		if composed.IsBijective() {
+			t.Error("a 40 to 20 bit permutation is bijective")
+		}
+
+		src := make([]byte, 5)
+		for j := range src {
+			src[j] = byte(random.Uint32())
+		}
+		middle, want, got := make([]byte, 5), make([]byte, 3), make([]byte, 3)
+		first.Apply(middle, src)
+		second.Apply(want, middle)
+		composed.Apply(got, src)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("mode %d: composed %X, step by step %X", mode, got, want)
+		}
+	}
+}
+
+func TestPermutationErrors(t *testing.T) {
+	for name, build := range map[string]func() (*Permutation, error){
+		"empty p-block":  func() (*Permutation, error) { return NewPermutation(nil, 1, HighToLow, ZeroBit) },
+		"zero input":     func() (*Permutation, error) { return NewPermutation([]int{0}, 0, HighToLow, ZeroBit) },
+		"index mode":     func() (*Permutation, error) { return NewPermutation([]int{0}, 1, 2, ZeroBit) },
+		"initial bit":    func() (*Permutation, error) { return NewPermutation([]int{0}, 1, HighToLow, 2) },
+		"out of range":   func() (*Permutation, error) { return NewPermutation([]int{8}, 1, HighToLow, ZeroBit) },
+		"zero one-based": func() (*Permutation, error) { return NewPermutation([]int{0}, 1, HighToLow, FirstBit) },
+	} {
+		if _, err := build(); err == nil {
+			t.Errorf("%s: NewPermutation succeeded", name)
+		}
+	}
+
+	p, _ := NewPermutation([]int{0, 1, 2, 3, 4, 5, 6, 7, 0}, 1, HighToLow, ZeroBit)
+	if err := p.Apply(make([]byte, 2), make([]byte, 2)); err == nil {
+		t.Error("Apply accepted a 2-byte input")
+	}
+	if err := p.Apply(make([]byte, 1), make([]byte, 1)); err == nil {
+		t.Error("Apply accepted a 1-byte output for 9 bits")
+	}
+	if _, err := p.Inverse(); err == nil {
+		t.Error("Inverse of a 9-bit expansion succeeded")
+	}
+
+	repeated, _ := NewPermutation([]int{0, 0, 2, 3, 4, 5, 6, 7}, 1, HighToLow, ZeroBit)
+	if repeated.IsBijective() {
+		t.Error("a permutation repeating bit 0 is bijective")
+	}
+	if _, err := Compose(p, repeated); err == nil {
+		t.Error("Compose accepted mismatched sizes")
+	}
+
+	// The second permutation reads the padding of the 9-bit output.
+	padding, _ := NewPermutation([]int{15}, 2, HighToLow, ZeroBit)
+	if _, err := Compose(p, padding); err == nil {
+		t.Error("Compose read padding bits")
+	}
+}
+
+func TestPermutationApplyAllocations(t *testing.T) {
+	p, _ := NewPermutation(desIP, 8, HighToLow, FirstBit)
+	src, dst := make([]byte, 8), make([]byte, 8)
+	if allocs := testing.AllocsPerRun(100, func() { p.Apply(dst, src) }); allocs != 0 {
+		t.Errorf("Apply allocates %.0f times", allocs)
+	}
+}
@@ -0,0 +1,39 @@
+package permutations
+
+import "testing"
+
+func FuzzBitPermutations(f *testing.F) {
+	f.Add([]byte{0b11001010, 0b00100110}, []byte{10, 15, 0, 2, 5, 6, 3, 9, 11, 14, 8, 12, 13, 7, 4, 1}, 1, 0)
+	f.Add([]byte{0b11001010, 0b00100110}, []byte{11, 16, 1, 3, 6, 7, 4, 10, 12, 15, 9, 13, 14, 8, 5, 2}, 0, 1)
+	f.Add([]byte{0xFF}, []byte{0, 8, 200}, 1, 1)
+	f.Add([]byte{}, []byte{1}, 2, -1)
+
+	f.Fuzz(func(t *testing.T, data []byte, rawPBlock []byte, indexMode, initialBit int) {
+		pBlock := make([]int, len(rawPBlock))
+		for i, b := range rawPBlock {
+			pBlock[i] = int(int8(b))
+		}
+
+		result, err := BitPermutations(data, pBlock, IndexMode(indexMode), InitialBit(initialBit))
+		if err != nil {
+			if result != nil {
+				t.Fatalf("BitPermutations returned both data and error %v", err)
+			}
+			return
+		}
+
+		if len(result) != (len(pBlock)+7)/8 {
+			t.Fatalf("result has %d bytes, want %d", len(result), (len(pBlock)+7)/8)
+		}
+
+		totalBits := len(data) * 8
+		for index, bit := range pBlock {
+			if InitialBit(initialBit) == FirstBit {
+				bit--
+			}
+			if getBit(result, IndexMode(indexMode), index, len(result)*8) != getBit(data, IndexMode(indexMode), bit, totalBits) {
+				t.Fatalf("output bit %d does not match input bit %d", index, bit)
+			}
+		}
+	})
+}
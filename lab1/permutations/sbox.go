@@ -0,0 +1,213 @@
+package permutations
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxSBoxBits bounds the input and output width of an SBox.
+const MaxSBoxBits = 16
+
+// SBox is an n-to-m bit substitution box. Inputs and outputs are read from
+// the most significant bit: for a DES box the input b1..b6 is the value
+// b1<<5 | ... | b6.
+type SBox struct {
+	inputBits  int
+	outputBits int
+	table      []uint16
+}
+
+// NewSBox builds a directly indexed box: table[x] is the output for x.
+func NewSBox(inputBits, outputBits int, table []int) (*SBox, error) {
+	if err := checkSBoxBits(inputBits, outputBits); err != nil {
+		return nil, err
+	}
+	if len(table) != 1<<inputBits {
+		return nil, fmt.Errorf("%d-bit S-box needs %d entries (got %d)", inputBits, 1<<inputBits, len(table))
+	}
+
+	s := &SBox{inputBits: inputBits, outputBits: outputBits, table: make([]uint16, len(table))}
+	for x, value := range table {
+		if value < 0 || value >= 1<<outputBits {
+			return nil, fmt.Errorf("S-box entry %d is %d, want 0..%d", x, value, 1<<outputBits-1)
+		}
+		s.table[x] = uint16(value)
+	}
+	return s, nil
+}
+
+// NewSBoxFromRows builds a DES-style box from four rows: the first and last
+// input bits select the row and the bits between them the column.
+func NewSBoxFromRows(outputBits int, rows [][]int) (*SBox, error) {
+	if len(rows) != 4 {
+		return nil, fmt.Errorf("row/column S-box needs 4 rows (got %d)", len(rows))
+	}
+	columns := len(rows[0])
+	if columns < 1 || columns&(columns-1) != 0 {
+		return nil, fmt.Errorf("row/column S-box needs a power of two columns (got %d)", columns)
+	}
+
+	inputBits := 2
+	for 1<<(inputBits-2) < columns {
+		inputBits++
+	}
+	table := make([]int, 1<<inputBits)
+	for row, values := range rows {
+		if len(values) != columns {
+			return nil, fmt.Errorf("S-box row %d has %d columns, want %d", row, len(values), columns)
+		}
+		for column, value := range values {
+			table[rowColumnIndex(inputBits, row, column)] = value
+		}
+	}
+	return NewSBox(inputBits, outputBits, table)
+}
+
+// NewSBoxFromFunc tabulates f over every input. Outputs must fit in
+// outputBits bits.
+func NewSBoxFromFunc(inputBits, outputBits int, f func(x uint32) uint32) (*SBox, error) {
+	if err := checkSBoxBits(inputBits, outputBits); err != nil {
+		return nil, err
+	}
+	table := make([]int, 1<<inputBits)
+	for x := range table {
+		value := f(uint32(x))
+		if value >= 1<<outputBits {
+			return nil, fmt.Errorf("S-box function maps %d to %d, which needs more than %d bits", x, value, outputBits)
+		}
+		table[x] = int(value)
+	}
+	return NewSBox(inputBits, outputBits, table)
+}
+
+func checkSBoxBits(inputBits, outputBits int) error {
+	if inputBits < 1 || inputBits > MaxSBoxBits || outputBits < 1 || outputBits > MaxSBoxBits {
+		return fmt.Errorf("S-box widths must be 1..%d bits (got %d to %d)", MaxSBoxBits, inputBits, outputBits)
+	}
+	return nil
+}
+
+func rowColumnIndex(inputBits, row, column int) int {
+	return (row>>1)<<(inputBits-1) | column<<1 | row&1
+}
+
+func (s *SBox) InputBits() int {
+	return s.inputBits
+}
+
+func (s *SBox) OutputBits() int {
+	return s.outputBits
+}
+
+// Lookup substitutes x, of which only the low InputBits bits are used.
+func (s *SBox) Lookup(x uint32) uint32 {
+	return uint32(s.table[x&(1<<s.inputBits-1)])
+}
+
+// LookupRowColumn addresses the box the DES way; row is 0..3 and column
+// fits in InputBits-2 bits.
+func (s *SBox) LookupRowColumn(row, column int) uint32 {
+	return s.Lookup(uint32(rowColumnIndex(s.inputBits, row&3, column)))
+}
+
+// Table returns the outputs for inputs 0, 1, 2, ...
+func (s *SBox) Table() []int {
+	table := make([]int, len(s.table))
+	for x, value := range s.table {
+		table[x] = int(value)
+	}
+	return table
+}
+
+// IsBijective reports whether the box is a permutation of its inputs.
+func (s *SBox) IsBijective() bool {
+	if s.inputBits != s.outputBits {
+		return false
+	}
+	seen := make([]bool, len(s.table))
+	for _, value := range s.table {
+		if seen[value] {
+			return false
+		}
+		seen[value] = true
+	}
+	return true
+}
+
+func (s *SBox) Inverse() (*SBox, error) {
+	if !s.IsBijective() {
+		return nil, errors.New("only a bijective S-box has an inverse")
+	}
+	inverse := make([]int, len(s.table))
+	for x, value := range s.table {
+		inverse[value] = x
+	}
+	return NewSBox(s.outputBits, s.inputBits, inverse)
+}
+
+// Apply substitutes every InputBits-wide chunk of the bit string src and
+// packs the outputs into dst, most significant bit first. len(src)*8 must
+// be a multiple of InputBits; bits of dst after the last output are zeroed,
+// so dst must not overlap src.
+func (s *SBox) Apply(dst, src []byte) error {
+	if len(src)*8%s.inputBits != 0 {
+		return fmt.Errorf("%d input bits are not a whole number of %d-bit chunks", len(src)*8, s.inputBits)
+	}
+	chunks := len(src) * 8 / s.inputBits
+	if chunks*s.outputBits > len(dst)*8 {
+		return fmt.Errorf("S-box writes %d bits, output has %d", chunks*s.outputBits, len(dst)*8)
+	}
+
+	clear(dst)
+	for i := range chunks {
+		WriteBits(dst, i*s.outputBits, s.outputBits, uint64(s.Lookup(uint32(ReadBits(src, i*s.inputBits, s.inputBits)))))
+	}
+	return nil
+}
+
+// ApplySBoxes substitutes consecutive chunks of src with one box each, as
+// the eight DES S-boxes do. The boxes must consume src exactly, and dst
+// must not overlap src.
+func ApplySBoxes(dst, src []byte, boxes []*SBox) error {
+	inputBits, outputBits := 0, 0
+	for _, box := range boxes {
+		inputBits += box.inputBits
+		outputBits += box.outputBits
+	}
+	if inputBits != len(src)*8 {
+		return fmt.Errorf("S-boxes read %d bits, input has %d", inputBits, len(src)*8)
+	}
+	if outputBits > len(dst)*8 {
+		return fmt.Errorf("S-boxes write %d bits, output has %d", outputBits, len(dst)*8)
+	}
+
+	clear(dst)
+	in, out := 0, 0
+	for _, box := range boxes {
+		value := box.Lookup(uint32(ReadBits(src, in, box.inputBits)))
+		WriteBits(dst, out, box.outputBits, uint64(value))
+		in += box.inputBits
+		out += box.outputBits
+	}
+	return nil
+}
+
+// ReadBits returns n <= 64 bits of data starting at bit start, counted
+// from the most significant bit of data[0].
+func ReadBits(data []byte, start, n int) uint64 {
+	var value uint64
+	for i := start; i < start+n; i++ {
+		value = value<<1 | uint64(data[i/8]>>(7-i%8)&1)
+	}
+	return value
+}
+
+// WriteBits replaces n bits of data at bit start with the low n bits of
+// value.
+func WriteBits(data []byte, start, n int, value uint64) {
+	for i := 0; i < n; i++ {
+		bit := start + i
+		data[bit/8] &^= 1 << (7 - bit%8)
+		data[bit/8] |= byte(value>>(n-1-i)&1) << (7 - bit%8)
+	}
+}
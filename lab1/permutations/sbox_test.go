@@ -0,0 +1,181 @@
+package permutations_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"lab1/des"
+	"lab1/permutations"
+	"slices"
+	"testing"
+)
+
+func desSBoxes(t *testing.T) []*permutations.SBox {
+	t.Helper()
+
+	boxes := make([]*permutations.SBox, len(des.SBoxes))
+	for i, rows := range des.SBoxes {
+		table := make([][]int, len(rows))
+		for r, row := range rows {
+			for _, value := range row {
+				table[r] = append(table[r], int(value))
+			}
+		}
+		box, err := permutations.NewSBoxFromRows(4, table)
+		if err != nil {
+			t.Fatalf("NewSBoxFromRows: %v", err)
+		}
+		boxes[i] = box
+	}
+	return boxes
+}
+
+func TestDESSBoxes(t *testing.T) {
+	boxes := desSBoxes(t)
+	s1 := boxes[0]
+	if s1.InputBits() != 6 || s1.OutputBits() != 4 || s1.IsBijective() {
+		t.Fatalf("S1 is %d to %d bits, bijective %v", s1.InputBits(), s1.OutputBits(), s1.IsBijective())
+	}
+
+	// Input 011011: row 01, column 1101.
+	if got := s1.Lookup(0b011011); got != 5 || s1.LookupRowColumn(1, 13) != 5 {
+		t.Errorf("S1(011011) = %d, want 5", got)
+	}
+
+	// The first round of the worked example with key 133457799BBCDFF1 and
+	// plaintext 0123456789ABCDEF.
+	src, _ := hex.DecodeString("6117BA866527")
+	dst := make([]byte, 4)
+	if err := permutations.ApplySBoxes(dst, src, boxes); err != nil {
+		t.Fatalf("ApplySBoxes: %v", err)
+	}
+	if want, _ := hex.DecodeString("5C82B597"); !bytes.Equal(dst, want) {
+		t.Errorf("S-box layer: got %X, want %X", dst, want)
+	}
+}
+
+// The PRESENT S-box and its inverse.
+var (
+	presentSBox        = []int{0xC, 0x5, 0x6, 0xB, 0x9, 0x0, 0xA, 0xD, 0x3, 0xE, 0xF, 0x8, 0x4, 0x7, 0x1, 0x2}
+	presentInverseSBox = []int{0x5, 0xE, 0xF, 0x8, 0xC, 0x1, 0x2, 0xD, 0xB, 0x4, 0x6, 0x3, 0x0, 0x7, 0x9, 0xA}
+)
+
+func TestSBoxInverse(t *testing.T) {
+	box, err := permutations.NewSBox(4, 4, presentSBox)
+	if err != nil {
+		t.Fatalf("NewSBox: %v", err)
+	}
+	inverse, err := box.Inverse()
+	if err != nil {
+		t.Fatalf("Inverse: %v", err)
+	}
+	if !slices.Equal(inverse.Table(), presentInverseSBox) {
+		t.Errorf("inverse is %X", inverse.Table())
+	}
+
+	// Bulk application over a 64-bit state and back.
+	src, _ := hex.DecodeString("0123456789ABCDEF")
+	substituted, restored := make([]byte, 8), make([]byte, 8)
+	if err := box.Apply(substituted, src); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if want, _ := hex.DecodeString("C56B90AD3EF84712"); !bytes.Equal(substituted, want) {
+		t.Errorf("Apply: got %X, want %X", substituted, want)
+	}
+	inverse.Apply(restored, substituted)
+	if !bytes.Equal(restored, src) {
+		t.Errorf("inverse Apply: got %X", restored)
+	}
+}
+
+func TestSBoxFromFunc(t *testing.T) {
+	// A 3-to-5 bit box, so the outputs are not byte aligned.
+	box, err := permutations.NewSBoxFromFunc(3, 5, func(x uint32) uint32 { return 3*x + 1 })
+	if err != nil {
+		t.Fatalf("NewSBoxFromFunc: %v", err)
+	}
+	if box.Lookup(5) != 16 || box.Lookup(8+5) != 16 {
+		t.Errorf("Lookup(5) = %d, want 16", box.Lookup(5))
+	}
+
+	// The inputs 0..7 as eight 3-bit chunks become 40 output bits.
+	src := packBits([]uint32{0, 1, 2, 3, 4, 5, 6, 7}, 3)
+	dst := bytes.Repeat([]byte{0xFF}, 6)
+	if err := box.Apply(dst, src); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	want := append(packBits([]uint32{1, 4, 7, 10, 13, 16, 19, 22}, 5), 0)
+	if !bytes.Equal(dst, want) {
+		t.Errorf("Apply: got %08b, want %08b", dst, want)
+	}
+
+	if _, err := permutations.NewSBoxFromFunc(3, 5, func(x uint32) uint32 { return 5 * x }); err == nil {
+		t.Error("NewSBoxFromFunc accepted 35 in 5 bits")
+	}
+}
+
+// packBits packs values of the given width, most significant bit first.
+func packBits(values []uint32, width int) []byte {
+	out := make([]byte, (len(values)*width+7)/8)
+	for i, value := range values {
+		for b := 0; b < width; b++ {
+			if value>>(width-1-b)&1 != 0 {
+				bit := i*width + b
+				out[bit/8] |= 1 << (7 - bit%8)
+			}
+		}
+	}
+	return out
+}
+
+func TestSBoxErrors(t *testing.T) {
+	if _, err := permutations.NewSBox(0, 4, nil); err == nil {
+		t.Error("NewSBox accepted 0 input bits")
+	}
+	if _, err := permutations.NewSBox(2, 2, []int{0, 1, 2}); err == nil {
+		t.Error("NewSBox accepted 3 entries for 2 bits")
+	}
+	if _, err := permutations.NewSBox(2, 2, []int{0, 1, 2, 4}); err == nil {
+		t.Error("NewSBox accepted 4 in 2 bits")
+	}
+	if _, err := permutations.NewSBoxFromRows(4, [][]int{{1}, {2}}); err == nil {
+		t.Error("NewSBoxFromRows accepted 2 rows")
+	}
+	if _, err := permutations.NewSBoxFromRows(4, [][]int{{1, 2, 3}, {1, 2, 3}, {1, 2, 3}, {1, 2, 3}}); err == nil {
+		t.Error("NewSBoxFromRows accepted 3 columns")
+	}
+
+	box, _ := permutations.NewSBox(2, 2, []int{0, 0, 1, 2})
+	if _, err := box.Inverse(); err == nil {
+		t.Error("Inverse of a non-bijective box succeeded")
+	}
+	if err := box.Apply(make([]byte, 1), make([]byte, 1)); err != nil {
+		t.Errorf("Apply on 4 chunks: %v", err)
+	}
+
+	wide, _ := permutations.NewSBox(3, 4, make([]int, 8))
+	if err := wide.Apply(make([]byte, 2), make([]byte, 2)); err == nil {
+		t.Error("Apply accepted 16 bits for 3-bit chunks")
+	}
+	if err := wide.Apply(make([]byte, 1), make([]byte, 3)); err == nil {
+		t.Error("Apply accepted a short output")
+	}
+	if err := permutations.ApplySBoxes(make([]byte, 4), make([]byte, 5), desSBoxes(t)); err == nil {
+		t.Error("ApplySBoxes accepted 40 bits for 48")
+	}
+}
+
+func TestReadWriteBits(t *testing.T) {
+	data := []byte{0xA5, 0x0F, 0xFF}
+	if got := permutations.ReadBits(data, 4, 8); got != 0x50 {
+		t.Errorf("ReadBits(4, 8) = %#x, want 0x50", got)
+	}
+	if got := permutations.ReadBits(data, 0, 24); got != 0xA50FFF {
+		t.Errorf("ReadBits(0, 24) = %#x, want 0xA50FFF", got)
+	}
+
+	// WriteBits replaces the bits, whatever was there before.
+	permutations.WriteBits(data, 6, 6, 0b100110)
+	if want := []byte{0xA6, 0x6F, 0xFF}; !bytes.Equal(data, want) {
+		t.Errorf("WriteBits gives %X, want %X", data, want)
+	}
+}
@@ -0,0 +1,145 @@
+package rainbow
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The file format is a fixed header followed by the chains, all big-endian:
+//
+//	magic "DESRBT" | version uint16 | kind uint8 | distinguished bits uint8
+//	plaintext, known, unknown, salt uint64 | chain length uint32
+//	chain count uint32 | chains: start, end, length uint32
+const (
+	fileMagic   = "DESRBT"
+	fileVersion = 1
+)
+
+type fileHeader struct {
+	Magic             [6]byte
+	Version           uint16
+	Kind              uint8
+	DistinguishedBits uint8
+	Plaintext         uint64
+	Known             uint64
+	Unknown           uint64
+	Salt              uint64
+	ChainLength       uint32
+	Chains            uint32
+}
+
+// WriteTo serializes the table.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	header := fileHeader{
+		Version:           fileVersion,
+		Kind:              uint8(t.Kind),
+		DistinguishedBits: uint8(t.DistinguishedBits),
+		Plaintext:         t.Plaintext,
+		Known:             t.Known,
+		Unknown:           t.Unknown,
+		Salt:              t.Salt,
+		ChainLength:       uint32(t.ChainLength),
+		Chains:            uint32(len(t.Chains)),
+	}
+	copy(header.Magic[:], fileMagic)
+
+	bw := bufio.NewWriter(w)
+	if err := binary.Write(bw, binary.BigEndian, header); err != nil {
+		return 0, fmt.Errorf("WriteTo: %w", err)
+	}
+	if err := binary.Write(bw, binary.BigEndian, t.Chains); err != nil {
+		return 0, fmt.Errorf("WriteTo: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("WriteTo: %w", err)
+	}
+	return int64(binary.Size(header) + binary.Size(t.Chains)), nil
+}
+
+// ReadTable reads a table written by WriteTo.
+func ReadTable(r io.Reader) (*Table, error) {
+	br := bufio.NewReader(r)
+
+	var header fileHeader
+	if err := binary.Read(br, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("ReadTable: %w", err)
+	}
+	if string(header.Magic[:]) != fileMagic {
+		return nil, errors.New("ReadTable: not a DES rainbow table")
+	}
+	if header.Version != fileVersion {
+		return nil, fmt.Errorf("ReadTable: unsupported version %d", header.Version)
+	}
+
+	t := &Table{
+		Kind:              Kind(header.Kind),
+		Plaintext:         header.Plaintext,
+		Known:             header.Known,
+		Unknown:           header.Unknown,
+		ChainLength:       int(header.ChainLength),
+		DistinguishedBits: int(header.DistinguishedBits),
+		Salt:              header.Salt,
+	}
+
+	config := Config{
+		Kind:              t.Kind,
+		Unknown:           t.Unknown,
+		Chains:            1,
+		ChainLength:       t.ChainLength,
+		DistinguishedBits: t.DistinguishedBits,
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("ReadTable: %w", err)
+	}
+
+	// Read in bounded steps so that a corrupt count cannot allocate much
+	// more than the file holds.
+	const step = 1 << 16
+	for remaining := int(header.Chains); remaining > 0; remaining -= step {
+		chains := make([]Chain, min(remaining, step))
+		if err := binary.Read(br, binary.BigEndian, chains); err != nil {
+			return nil, fmt.Errorf("ReadTable: %w", err)
+		}
+		t.Chains = append(t.Chains, chains...)
+	}
+
+	for i := 1; i < len(t.Chains); i++ {
+		if t.Chains[i-1].End >= t.Chains[i].End {
+			return nil, errors.New("ReadTable: chains are not sorted by end point")
+		}
+	}
+	return t, nil
+}
+
+func (t *Table) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	if _, err := t.WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("Save: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	return nil
+}
+
+func Load(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Load: %w", err)
+	}
+	defer f.Close()
+
+	t, err := ReadTable(f)
+	if err != nil {
+		return nil, fmt.Errorf("Load: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,505 @@
+package rainbow
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"lab1/des"
+	"math/bits"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Kind selects how chains are built and terminated.
+type Kind int
+
+const (
+	// Rainbow chains have a fixed length and a different reduction function
+	// in every column, so merging chains only collide in the same column.
+	Rainbow Kind = iota
+	// DistinguishedPoints chains use one reduction function per table, as in
+	// Hellman's original tradeoff, and end at the first key index whose low
+	// DistinguishedBits bits are zero.
+	DistinguishedPoints
+)
+
+const (
+	// MaxUnknownBits bounds the key space so that chain points fit in 32 bits.
+	MaxUnknownBits = 32
+	// reductionMultiplier spreads the column or table number over the index.
+	reductionMultiplier = 0x9E3779B97F4A7C15
+)
+
+var ErrNotFound = errors.New("key not in table")
+
+// Config describes a table for the keys that agree with Known outside the
+// Unknown mask, all used to encrypt the same chosen Plaintext.
+type Config struct {
+	Kind      Kind
+	Plaintext uint64
+	Known     uint64
+	Unknown   uint64
+	// Chains is the number of chains started; ChainLength is the length of
+	// rainbow chains or the longest distinguished point chain kept.
+	Chains            int
+	ChainLength       int
+	DistinguishedBits int
+	// Salt tells apart tables built for the same key space, which then use
+	// different reduction functions.
+	Salt    uint64
+	Workers int
+}
+
+type Chain struct {
+	Start  uint32
+	End    uint32
+	Length uint32
+}
+
+type Table struct {
+	Kind              Kind
+	Plaintext         uint64
+	Known             uint64
+	Unknown           uint64
+	ChainLength       int
+	DistinguishedBits int
+	Salt              uint64
+	// Chains are sorted by End and have distinct ends.
+	Chains []Chain
+}
+
+type BuildStats struct {
+	Started int
+	// Discarded counts distinguished point chains that did not end in time,
+	// Merged the chains dropped because another chain had the same end.
+	Discarded   int
+	Merged      int
+	Encryptions int64
+}
+
+func (c Config) validate() error {
+	if c.Kind != Rainbow && c.Kind != DistinguishedPoints {
+		return fmt.Errorf("unknown table kind %d", c.Kind)
+	}
+	if c.Unknown&des.ParityBits != 0 {
+		return fmt.Errorf("unknown key bits %016x include parity bits", c.Unknown)
+	}
+	if n := bits.OnesCount64(c.Unknown); n == 0 || n > MaxUnknownBits {
+		return fmt.Errorf("key space must have between 1 and %d unknown bits (got %d)", MaxUnknownBits, n)
+	}
+	if c.Chains <= 0 {
+		return fmt.Errorf("number of chains must be positive (got %d)", c.Chains)
+	}
+	if c.ChainLength <= 0 {
+		return fmt.Errorf("chain length must be positive (got %d)", c.ChainLength)
+	}
+	if c.Kind == DistinguishedPoints && (c.DistinguishedBits <= 0 || c.DistinguishedBits >= bits.OnesCount64(c.Unknown)) {
+		return fmt.Errorf("distinguished bits must be between 1 and %d (got %d)", bits.OnesCount64(c.Unknown)-1, c.DistinguishedBits)
+	}
+	if c.Workers < 0 {
+		return errors.New("number of workers cannot be negative")
+	}
+	return nil
+}
+
+// Build computes the chains of a table. Start points are spread evenly over
+// the key space; each step encrypts the plaintext under the current key and
+// reduces the ciphertext to the next key index.
+func Build(ctx context.Context, config Config) (*Table, *BuildStats, error) {
+	if err := config.validate(); err != nil {
+		return nil, nil, fmt.Errorf("Build: %w", err)
+	}
+
+	workers := config.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	t := &Table{
+		Kind:              config.Kind,
+		Plaintext:         config.Plaintext,
+		Known:             config.Known &^ config.Unknown,
+		Unknown:           config.Unknown,
+		ChainLength:       config.ChainLength,
+		DistinguishedBits: config.DistinguishedBits,
+		Salt:              config.Salt,
+	}
+
+	space := t.space()
+	chains := make([]Chain, config.Chains)
+	ok := make([]bool, config.Chains)
+	stride := max(space/uint64(config.Chains), 1)
+
+	var encryptions atomic.Int64
+	err := t.parallel(ctx, workers, config.Chains, func(w *walker, i int) error {
+		start := uint32(uint64(i) * stride % space)
+		end, length, done, err := w.walk(start, 0, t.ChainLength)
+		if err != nil {
+			return err
+		}
+		chains[i] = Chain{Start: start, End: end, Length: uint32(length)}
+		ok[i] = done
+		encryptions.Add(int64(length))
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Build: %w", err)
+	}
+
+	stats := &BuildStats{Started: config.Chains, Encryptions: encryptions.Load()}
+	for i, chain := range chains {
+		if !ok[i] {
+			stats.Discarded++
+			continue
+		}
+		t.Chains = append(t.Chains, chain)
+	}
+
+	// Keep one chain per end point; longer distinguished point chains
+	// cover more keys, so they win.
+	slices.SortFunc(t.Chains, func(a, b Chain) int {
+		if a.End != b.End {
+			return compare(a.End, b.End)
+		}
+		return compare(b.Length, a.Length)
+	})
+	before := len(t.Chains)
+	t.Chains = slices.CompactFunc(t.Chains, func(a, b Chain) bool { return a.End == b.End })
+	stats.Merged = before - len(t.Chains)
+
+	return t, stats, nil
+}
+
+// LookupStats counts the work of one or more lookups.
+type LookupStats struct {
+	Lookups     int
+	Found       int
+	FalseAlarms int
+	Encryptions int64
+}
+
+// Lookup searches the key that encrypts the table's plaintext to
+// ciphertext. A chain whose end matches but which does not contain such a
+// key is a false alarm.
+func (t *Table) Lookup(ciphertext uint64) ([]byte, *LookupStats, error) {
+	w, err := newWalker(t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Lookup: %w", err)
+	}
+	defer w.cipher.Destroy()
+
+	stats := &LookupStats{Lookups: 1}
+	key, err := w.lookup(ciphertext, stats)
+	if err != nil {
+		return nil, stats, fmt.Errorf("Lookup: %w", err)
+	}
+	stats.Found = 1
+	return key, stats, nil
+}
+
+// Coverage returns the fraction of the key space visited by the chains,
+// recomputing every chain. It needs 2^|Unknown| bits of memory.
+func (t *Table) Coverage() (float64, error) {
+	w, err := newWalker(t)
+	if err != nil {
+		return 0, fmt.Errorf("Coverage: %w", err)
+	}
+	defer w.cipher.Destroy()
+
+	space := t.space()
+	visited := make([]uint64, (space+63)/64)
+	covered := uint64(0)
+	for _, chain := range t.Chains {
+		index := chain.Start
+		for column := 0; column < int(chain.Length); column++ {
+			if visited[index/64]>>(index%64)&1 == 0 {
+				visited[index/64] |= 1 << (index % 64)
+				covered++
+			}
+			if index, err = w.step(index, column); err != nil {
+				return 0, fmt.Errorf("Coverage: %w", err)
+			}
+		}
+	}
+	return float64(covered) / float64(space), nil
+}
+
+func (t *Table) space() uint64 {
+	return uint64(1) << bits.OnesCount64(t.Unknown)
+}
+
+// Key returns the DES key of a chain point.
+func (t *Table) Key(index uint32) []byte {
+	return binary.BigEndian.AppendUint64(nil, t.key(index))
+}
+
+func (t *Table) key(index uint32) uint64 {
+	return des.CandidateKey(t.Known, t.Unknown, uint64(index))
+}
+
+// reduce maps a ciphertext to a key index. Rainbow tables vary the
+// function with the column; distinguished point tables only with the salt.
+func (t *Table) reduce(ciphertext uint64, column int) uint32 {
+	tweak := t.Salt
+	if t.Kind == Rainbow {
+		tweak += uint64(column) + 1
+	}
+	x := ciphertext ^ tweak*reductionMultiplier
+	x ^= x >> 29
+	return uint32(x & (t.space() - 1))
+}
+
+func (t *Table) distinguished(index uint32) bool {
+	return index&(1<<t.DistinguishedBits-1) == 0
+}
+
+// findEnd returns the position of the chain ending at end, or -1.
+func (t *Table) findEnd(end uint32) int {
+	i, found := slices.BinarySearchFunc(t.Chains, end, func(c Chain, target uint32) int {
+		return compare(c.End, target)
+	})
+	if !found {
+		return -1
+	}
+	return i
+}
+
+// walker owns a DES instance for computing chains.
+type walker struct {
+	t      *Table
+	cipher *des.DES
+	block  [8]byte
+	key    [8]byte
+}
+
+func newWalker(t *Table) (*walker, error) {
+	cipher, err := des.NewDES()
+	if err != nil {
+		return nil, err
+	}
+	return &walker{t: t, cipher: cipher}, nil
+}
+
+func (w *walker) encrypt(index uint32) (uint64, error) {
+	binary.BigEndian.PutUint64(w.key[:], w.t.key(index))
+	if err := w.cipher.SetKey(w.key[:]); err != nil {
+		return 0, err
+	}
+
+	binary.BigEndian.PutUint64(w.block[:], w.t.Plaintext)
+	ciphertext, err := w.cipher.Encrypt(w.block[:])
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(ciphertext), nil
+}
+
+// step moves from the key index in the given column to the next point.
+func (w *walker) step(index uint32, column int) (uint32, error) {
+	ciphertext, err := w.encrypt(index)
+	if err != nil {
+		return 0, err
+	}
+	return w.t.reduce(ciphertext, column), nil
+}
+
+// walk follows a chain from index in column to its end. Rainbow chains stop
+// after limit columns; distinguished point chains stop at the first
+// distinguished point and report false when none came within limit steps.
+func (w *walker) walk(index uint32, column, limit int) (end uint32, steps int, done bool, err error) {
+	for ; column < limit; column++ {
+		if w.t.Kind == DistinguishedPoints && w.t.distinguished(index) && steps > 0 {
+			return index, steps, true, nil
+		}
+		if index, err = w.step(index, column); err != nil {
+			return 0, steps, false, err
+		}
+		steps++
+	}
+	if w.t.Kind == DistinguishedPoints {
+		return index, steps, w.t.distinguished(index), nil
+	}
+	return index, steps, true, nil
+}
+
+func (w *walker) lookup(ciphertext uint64, stats *LookupStats) ([]byte, error) {
+	t := w.t
+	if t.Kind == DistinguishedPoints {
+		return w.lookupDistinguished(ciphertext, stats)
+	}
+
+	// Assume the key sits in each column, last first, and walk to the end.
+	for column := t.ChainLength - 1; column >= 0; column-- {
+		index := t.reduce(ciphertext, column)
+		end, steps, _, err := w.walk(index, column+1, t.ChainLength)
+		stats.Encryptions += int64(steps)
+		if err != nil {
+			return nil, err
+		}
+
+		if i := t.findEnd(end); i >= 0 {
+			key, err := w.regenerate(t.Chains[i], column, ciphertext, stats)
+			if err != nil || key != nil {
+				return key, err
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (w *walker) lookupDistinguished(ciphertext uint64, stats *LookupStats) ([]byte, error) {
+	t := w.t
+	index := t.reduce(ciphertext, 0)
+	for steps := 0; steps < t.ChainLength; steps++ {
+		if t.distinguished(index) {
+			i := t.findEnd(index)
+			if i < 0 {
+				return nil, ErrNotFound
+			}
+			key, err := w.regenerate(t.Chains[i], int(t.Chains[i].Length)-1, ciphertext, stats)
+			if err != nil || key != nil {
+				return key, err
+			}
+			return nil, ErrNotFound
+		}
+
+		next, err := w.step(index, 0)
+		stats.Encryptions++
+		if err != nil {
+			return nil, err
+		}
+		index = next
+	}
+	return nil, ErrNotFound
+}
+
+// regenerate walks the chain from its start up to column last, looking for
+// a key that encrypts the plaintext to ciphertext. It returns nil and
+// counts a false alarm when there is none.
+func (w *walker) regenerate(chain Chain, last int, ciphertext uint64, stats *LookupStats) ([]byte, error) {
+	index := chain.Start
+	for column := 0; column <= last; column++ {
+		c, err := w.encrypt(index)
+		stats.Encryptions++
+		if err != nil {
+			return nil, err
+		}
+		if c == ciphertext {
+			return w.t.Key(index), nil
+		}
+		index = w.t.reduce(c, column)
+	}
+	stats.FalseAlarms++
+	return nil, nil
+}
+
+// Evaluate looks up the ciphertexts of keys drawn uniformly from the key
+// space and reports how many were found, estimating the table's success
+// probability along with its false alarm rate.
+func (t *Table) Evaluate(ctx context.Context, indices []uint32, workers int) (*LookupStats, error) {
+	if workers < 0 {
+		return nil, errors.New("Evaluate: number of workers cannot be negative")
+	}
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var mu sync.Mutex
+	total := &LookupStats{}
+
+	err := t.parallel(ctx, workers, len(indices), func(w *walker, i int) error {
+		index := indices[i] & uint32(t.space()-1)
+		ciphertext, err := w.encrypt(index)
+		if err != nil {
+			return err
+		}
+
+		stats := &LookupStats{Lookups: 1}
+		_, err = w.lookup(ciphertext, stats)
+		if err == nil {
+			stats.Found = 1
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		mu.Lock()
+		total.Lookups += stats.Lookups
+		total.Found += stats.Found
+		total.FalseAlarms += stats.FalseAlarms
+		total.Encryptions += stats.Encryptions
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Evaluate: %w", err)
+	}
+	return total, nil
+}
+
+// SuccessRate is the fraction of lookups that found a key.
+func (s *LookupStats) SuccessRate() float64 {
+	if s.Lookups == 0 {
+		return 0
+	}
+	return float64(s.Found) / float64(s.Lookups)
+}
+
+// parallel calls fn for 0..n-1 across workers, each with its own walker.
+func (t *Table) parallel(ctx context.Context, workers, n int, fn func(w *walker, i int) error) error {
+	jobs := make(chan int, n)
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, workers)
+
+	for range min(workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			w, err := newWalker(t)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			defer w.cipher.Destroy()
+
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errCh <- err
+					return
+				}
+				if err := fn(w, i); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compare(a, b uint32) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package rainbow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testPlaintext = 0x01234567_89ABCDEF
+	testKnown     = 0x13345779_9BBC0000
+	testUnknown   = 0x3EFE
+)
+
+func buildTestTable(t *testing.T, kind Kind) *Table {
+	t.Helper()
+
+	config := Config{
+		Kind:        kind,
+		Plaintext:   testPlaintext,
+		Known:       testKnown,
+		Unknown:     testUnknown,
+		Chains:      256,
+		ChainLength: 32,
+		Workers:     4,
+	}
+	if kind == DistinguishedPoints {
+		config.Chains = 512
+		config.DistinguishedBits = 3
+	}
+
+	table, stats, err := Build(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if stats.Started != config.Chains || len(table.Chains) != stats.Started-stats.Discarded-stats.Merged {
+		t.Fatalf("build stats %+v for %d chains", stats, len(table.Chains))
+	}
+	return table
+}
+
+func TestTables(t *testing.T) {
+	for _, kind := range []Kind{Rainbow, DistinguishedPoints} {
+		table := buildTestTable(t, kind)
+
+		coverage, err := table.Coverage()
+		if err != nil {
+			t.Fatalf("Coverage: %v", err)
+		}
+		if coverage < 0.1 || coverage > 1 {
+			t.Errorf("kind %d: coverage %.3f", kind, coverage)
+		}
+
+		// Every chain start must be found from its ciphertext.
+		w, _ := newWalker(table)
+		for _, chain := range table.Chains[:16] {
+			ciphertext, _ := w.encrypt(chain.Start)
+			key, _, err := table.Lookup(ciphertext)
+			if err != nil {
+				t.Fatalf("kind %d: Lookup of chain start %d: %v", kind, chain.Start, err)
+			}
+			if c, _ := encryptWithKey(w, key); c != ciphertext {
+				t.Errorf("kind %d: key %x does not encrypt to %016x", kind, key, ciphertext)
+			}
+		}
+
+		random := rand.New(rand.NewChaCha8([32]byte{7}))
+		indices := make([]uint32, 200)
+		for i := range indices {
+			indices[i] = random.Uint32()
+		}
+		stats, err := table.Evaluate(context.Background(), indices, 4)
+		if err != nil {
+			t.Fatalf("Evaluate: %v", err)
+		}
+		if stats.Lookups != len(indices) || math.Abs(stats.SuccessRate()-coverage) > 0.15 {
+			t.Errorf("kind %d: success rate %.3f, coverage %.3f", kind, stats.SuccessRate(), coverage)
+		}
+		t.Logf("kind %d: %d chains, coverage %.3f, success %.3f, %.1f false alarms and %.0f encryptions per lookup",
+			kind, len(table.Chains), coverage, stats.SuccessRate(),
+			float64(stats.FalseAlarms)/float64(stats.Lookups), float64(stats.Encryptions)/float64(stats.Lookups))
+	}
+}
+
+func encryptWithKey(w *walker, key []byte) (uint64, error) {
+	if err := w.cipher.SetKey(key); err != nil {
+		return 0, err
+	}
+	ciphertext, err := w.cipher.Encrypt([]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF})
+	if err != nil {
+		return 0, err
+	}
+	var c uint64
+	for _, b := range ciphertext {
+		c = c<<8 | uint64(b)
+	}
+	return c, nil
+}
+
+func TestSaveLoad(t *testing.T) {
+	table := buildTestTable(t, DistinguishedPoints)
+
+	path := filepath.Join(t.TempDir(), "table.rbt")
+	if err := table.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if loaded.Kind != table.Kind || loaded.Plaintext != table.Plaintext || loaded.Known != table.Known ||
+		loaded.Unknown != table.Unknown || loaded.ChainLength != table.ChainLength ||
+		loaded.DistinguishedBits != table.DistinguishedBits || len(loaded.Chains) != len(table.Chains) {
+		t.Fatalf("loaded table %+v differs", loaded)
+	}
+	for i := range table.Chains {
+		if loaded.Chains[i] != table.Chains[i] {
+			t.Fatalf("chain %d: %+v, want %+v", i, loaded.Chains[i], table.Chains[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := table.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("WriteTo = %d, %v for %d bytes", n, err, buf.Len())
+	}
+	data := buf.Bytes()
+	data[0] ^= 0xFF
+	if _, err := ReadTable(bytes.NewReader(data)); err == nil {
+		t.Error("ReadTable accepted a bad magic")
+	}
+	data[0] ^= 0xFF
+	if _, err := ReadTable(bytes.NewReader(data[:len(data)-4])); err == nil {
+		t.Error("ReadTable accepted a truncated table")
+	}
+}
+
+func TestLookupMiss(t *testing.T) {
+	table := buildTestTable(t, Rainbow)
+	table.Chains = table.Chains[:0]
+	if _, _, err := table.Lookup(0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup in empty table: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	valid := Config{Unknown: testUnknown, Chains: 1, ChainLength: 1}
+	for _, config := range []Config{
+		{Kind: 2, Unknown: testUnknown, Chains: 1, ChainLength: 1},
+		{Unknown: 0x0101, Chains: 1, ChainLength: 1},
+		{Unknown: 0, Chains: 1, ChainLength: 1},
+		{Unknown: testUnknown, Chains: 0, ChainLength: 1},
+		{Unknown: testUnknown, Chains: 1, ChainLength: 0},
+		{Kind: DistinguishedPoints, Unknown: testUnknown, Chains: 1, ChainLength: 1},
+		{Unknown: testUnknown, Chains: 1, ChainLength: 1, Workers: -1},
+	} {
+		if _, _, err := Build(context.Background(), config); err == nil {
+			t.Errorf("Build(%+v) succeeded", config)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := Build(ctx, valid); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,96 @@
+package randomness
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const (
+	DefaultAlpha         = 0.01
+	DefaultBlockSize     = 128
+	DefaultSerialLength  = 16
+	DefaultEntropyLength = 10
+)
+
+type Config struct {
+	BlockSize     int
+	SerialLength  int
+	EntropyLength int
+}
+
+type Result struct {
+	Name    string
+	PValues []float64
+	Err     error
+}
+
+func (r Result) Passed(alpha float64) bool {
+	if r.Err != nil {
+		return false
+	}
+	for _, p := range r.PValues {
+		if p < alpha {
+			return false
+		}
+	}
+	return true
+}
+
+// Run applies every test of the battery to the sequence. Zero config fields
+// take the SP 800-22 defaults; pattern lengths are lowered for short
+// sequences so that m stays below log2(n) - 2.
+func Run(sequence Bits, config Config) []Result {
+	blockSize := config.BlockSize
+	if blockSize == 0 {
+		blockSize = DefaultBlockSize
+	}
+
+	log2n := bits.Len(uint(len(sequence))) - 1
+	serialLength := config.SerialLength
+	if serialLength == 0 {
+		serialLength = max(min(DefaultSerialLength, log2n-3), 2)
+	}
+	entropyLength := config.EntropyLength
+	if entropyLength == 0 {
+		entropyLength = max(min(DefaultEntropyLength, log2n-6), 1)
+	}
+
+	var results []Result
+	single := func(name string, p float64, err error) {
+		results = append(results, Result{Name: name, PValues: []float64{p}, Err: err})
+	}
+	pair := func(name string, p1, p2 float64, err error) {
+		results = append(results, Result{Name: name, PValues: []float64{p1, p2}, Err: err})
+	}
+
+	p, err := Frequency(sequence)
+	single("Frequency", p, err)
+
+	p, err = BlockFrequency(sequence, blockSize)
+	single(fmt.Sprintf("BlockFrequency(M=%d)", blockSize), p, err)
+
+	p, err = Runs(sequence)
+	single("Runs", p, err)
+
+	p, err = LongestRun(sequence)
+	single("LongestRun", p, err)
+
+	p, err = Spectral(sequence)
+	single("FFT", p, err)
+
+	p1, p2, err := Serial(sequence, serialLength)
+	pair(fmt.Sprintf("Serial(m=%d)", serialLength), p1, p2, err)
+
+	p, err = ApproximateEntropy(sequence, entropyLength)
+	single(fmt.Sprintf("ApproximateEntropy(m=%d)", entropyLength), p, err)
+
+	p1, p2, err = CumulativeSums(sequence)
+	pair("CumulativeSums", p1, p2, err)
+
+	for i := range results {
+		if results[i].Err != nil {
+			results[i].PValues = nil
+		}
+	}
+	return results
+}
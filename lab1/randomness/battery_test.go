@@ -0,0 +1,68 @@
+package randomness
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestRunRandom(t *testing.T) {
+	data := make([]byte, 1<<14)
+	rand.NewChaCha8([32]byte{7}).Read(data)
+
+	results := Run(FromBytes(data), Config{})
+	if len(results) != 8 {
+		t.Fatalf("got %d results, want 8", len(results))
+	}
+	for _, r := range results {
+		if !r.Passed(DefaultAlpha) {
+			t.Errorf("%s failed on random data: %v %v", r.Name, r.PValues, r.Err)
+		}
+	}
+
+	if results[5].Name != "Serial(m=14)" || results[6].Name != "ApproximateEntropy(m=10)" {
+		t.Errorf("pattern lengths not adapted to n=%d: %s, %s", len(data)*8, results[5].Name, results[6].Name)
+	}
+}
+
+func TestRunPatterned(t *testing.T) {
+	// An alternating sequence is perfectly balanced at every scale, so only
+	// the tests that look at transitions and patterns reject it.
+	alternating := parse(t, strings.Repeat("01", 4096))
+	balanced := map[string]bool{"Frequency": true, "BlockFrequency(M=128)": true, "CumulativeSums": true}
+	for _, r := range Run(alternating, Config{}) {
+		if r.Passed(DefaultAlpha) != balanced[r.Name] {
+			t.Errorf("%s on an alternating sequence: %v", r.Name, r.PValues)
+		}
+	}
+
+	ones := parse(t, strings.Repeat("1", 8192))
+	for _, r := range Run(ones, Config{}) {
+		if r.Passed(DefaultAlpha) {
+			t.Errorf("%s passed on all ones: %v", r.Name, r.PValues)
+		}
+	}
+}
+
+func TestRunShortSequence(t *testing.T) {
+	results := Run(parse(t, strings.Repeat("0110", 16)), Config{})
+	for _, r := range results {
+		if r.Name == "LongestRun" {
+			if !errors.Is(r.Err, ErrSequenceTooShort) || r.PValues != nil || r.Passed(DefaultAlpha) {
+				t.Errorf("LongestRun on 64 bits: %v %v", r.PValues, r.Err)
+			}
+			return
+		}
+	}
+	t.Fatal("LongestRun missing from results")
+}
+
+func TestFromBytes(t *testing.T) {
+	if got := FromBytes([]byte{0xA5, 0x01}); string(got) != string(parse(t, "1010010100000001")) {
+		t.Errorf("FromBytes: got %v", got)
+	}
+	if _, err := ParseBits("0102"); err == nil {
+		t.Error("ParseBits accepted '2'")
+	}
+}
@@ -0,0 +1,39 @@
+package randomness
+
+import "fmt"
+
+// Bits is a bit sequence with one bit (0 or 1) per element.
+type Bits []byte
+
+// FromBytes expands data into bits, most significant bit first.
+func FromBytes(data []byte) Bits {
+	bits := make(Bits, len(data)*8)
+	for i, b := range data {
+		for j := 0; j < 8; j++ {
+			bits[i*8+j] = (b >> (7 - j)) & 1
+		}
+	}
+	return bits
+}
+
+func ParseBits(s string) (Bits, error) {
+	bits := make(Bits, 0, len(s))
+	for i, c := range s {
+		switch c {
+		case '0', '1':
+			bits = append(bits, byte(c-'0'))
+		case ' ', '\n', '\t':
+		default:
+			return nil, fmt.Errorf("invalid bit %q at offset %d", c, i)
+		}
+	}
+	return bits, nil
+}
+
+func (b Bits) Ones() int {
+	ones := 0
+	for _, bit := range b {
+		ones += int(bit)
+	}
+	return ones
+}
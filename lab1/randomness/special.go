@@ -0,0 +1,160 @@
+package randomness
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+const (
+	gammaEpsilon    = 1e-15
+	gammaIterations = 1000
+)
+
+// igamc is the regularized upper incomplete gamma function Q(a, x).
+func igamc(a, x float64) float64 {
+	if x <= 0 || a <= 0 {
+		return 1
+	}
+	if x < a+1 {
+		return 1 - gammaSeries(a, x)
+	}
+	return gammaContinuedFraction(a, x)
+}
+
+func gammaSeries(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+
+	term := 1 / a
+	sum := term
+	for n := 1; n < gammaIterations; n++ {
+		term *= x / (a + float64(n))
+		sum += term
+		if math.Abs(term) < math.Abs(sum)*gammaEpsilon {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-lgamma)
+}
+
+func gammaContinuedFraction(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	const tiny = 1e-300
+
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < gammaIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < gammaEpsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// dft returns the discrete Fourier transform of x for any length, using
+// Bluestein's algorithm when the length is not a power of two.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	if n == 0 {
+		return nil
+	}
+	if n&(n-1) == 0 {
+		result := append([]complex128(nil), x...)
+		fft(result, false)
+		return result
+	}
+
+	size := 1 << bits.Len(uint(2*n-1))
+
+	chirp := make([]complex128, n)
+	for k := range chirp {
+		angle := math.Pi * float64((k*k)%(2*n)) / float64(n)
+		chirp[k] = cmplx.Exp(complex(0, -angle))
+	}
+
+	a := make([]complex128, size)
+	b := make([]complex128, size)
+	for k := 0; k < n; k++ {
+		a[k] = x[k] * chirp[k]
+	}
+	b[0] = cmplx.Conj(chirp[0])
+	for k := 1; k < n; k++ {
+		b[k] = cmplx.Conj(chirp[k])
+		b[size-k] = b[k]
+	}
+
+	fft(a, false)
+	fft(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	fft(a, true)
+
+	result := make([]complex128, n)
+	for k := range result {
+		result[k] = a[k] * chirp[k]
+	}
+	return result
+}
+
+// fft is an in-place radix-2 transform; len(x) must be a power of two.
+// The inverse transform is scaled by 1/len(x).
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		step := cmplx.Exp(complex(0, sign*2*math.Pi/float64(length)))
+		for start := 0; start < n; start += length {
+			w := complex(1, 0)
+			for k := 0; k < length/2; k++ {
+				u := x[start+k]
+				v := x[start+k+length/2] * w
+				x[start+k] = u + v
+				x[start+k+length/2] = u - v
+				w *= step
+			}
+		}
+	}
+
+	if inverse {
+		scale := complex(1/float64(n), 0)
+		for i := range x {
+			x[i] *= scale
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package randomness
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrSequenceTooShort = errors.New("sequence too short")
+
+func Frequency(bits Bits) (float64, error) {
+	n := len(bits)
+	if n == 0 {
+		return 0, fmt.Errorf("Frequency: %w", ErrSequenceTooShort)
+	}
+
+	sum := 2*bits.Ones() - n
+	sObs := math.Abs(float64(sum)) / math.Sqrt(float64(n))
+	return math.Erfc(sObs / math.Sqrt2), nil
+}
+
+func BlockFrequency(bits Bits, blockSize int) (float64, error) {
+	if blockSize <= 0 {
+		return 0, fmt.Errorf("BlockFrequency: block size must be positive (got %d)", blockSize)
+	}
+
+	blocks := len(bits) / blockSize
+	if blocks == 0 {
+		return 0, fmt.Errorf("BlockFrequency: %w", ErrSequenceTooShort)
+	}
+
+	chiSquared := 0.0
+	for i := 0; i < blocks; i++ {
+		pi := float64(bits[i*blockSize:(i+1)*blockSize].Ones()) / float64(blockSize)
+		chiSquared += (pi - 0.5) * (pi - 0.5)
+	}
+	chiSquared *= 4 * float64(blockSize)
+
+	return igamc(float64(blocks)/2, chiSquared/2), nil
+}
+
+func Runs(bits Bits) (float64, error) {
+	n := len(bits)
+	if n < 2 {
+		return 0, fmt.Errorf("Runs: %w", ErrSequenceTooShort)
+	}
+
+	pi := float64(bits.Ones()) / float64(n)
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(float64(n)) {
+		return 0, nil
+	}
+
+	runs := 1
+	for k := 1; k < n; k++ {
+		if bits[k] != bits[k-1] {
+			runs++
+		}
+	}
+
+	numerator := math.Abs(float64(runs) - 2*float64(n)*pi*(1-pi))
+	denominator := 2 * math.Sqrt(2*float64(n)) * pi * (1 - pi)
+	return math.Erfc(numerator / denominator), nil
+}
+
+type longestRunParams struct {
+	blockSize     int
+	minRun        int
+	probabilities []float64
+}
+
+var longestRunTable = []struct {
+	minLength int
+	params    longestRunParams
+}{
+	{750000, longestRunParams{10000, 10, []float64{0.0882, 0.2092, 0.2483, 0.1933, 0.1208, 0.0675, 0.0727}}},
+	{6272, longestRunParams{128, 4, []float64{0.1174035788, 0.242955959, 0.249363483, 0.17517706, 0.102701071, 0.112398847}}},
+	{128, longestRunParams{8, 1, []float64{0.21484375, 0.3671875, 0.23046875, 0.1875}}},
+}
+
+func LongestRun(bits Bits) (float64, error) {
+	var params *longestRunParams
+	for i := range longestRunTable {
+		if len(bits) >= longestRunTable[i].minLength {
+			params = &longestRunTable[i].params
+			break
+		}
+	}
+	if params == nil {
+		return 0, fmt.Errorf("LongestRun: %w (need at least 128 bits)", ErrSequenceTooShort)
+	}
+
+	categories := len(params.probabilities)
+	counts := make([]int, categories)
+	blocks := len(bits) / params.blockSize
+
+	for i := 0; i < blocks; i++ {
+		longest, run := 0, 0
+		for _, bit := range bits[i*params.blockSize : (i+1)*params.blockSize] {
+			if bit == 1 {
+				run++
+				longest = max(longest, run)
+			} else {
+				run = 0
+			}
+		}
+
+		category := min(max(longest-params.minRun, 0), categories-1)
+		counts[category]++
+	}
+
+	chiSquared := 0.0
+	for i, p := range params.probabilities {
+		expected := float64(blocks) * p
+		d := float64(counts[i]) - expected
+		chiSquared += d * d / expected
+	}
+
+	return igamc(float64(categories-1)/2, chiSquared/2), nil
+}
+
+func Spectral(bits Bits) (float64, error) {
+	n := len(bits)
+	if n < 2 {
+		return 0, fmt.Errorf("Spectral: %w", ErrSequenceTooShort)
+	}
+
+	x := make([]complex128, n)
+	for i, bit := range bits {
+		x[i] = complex(float64(2*int(bit)-1), 0)
+	}
+	spectrum := dft(x)
+
+	threshold := math.Sqrt(math.Log(1/0.05) * float64(n))
+	expected := 0.95 * float64(n) / 2
+
+	below := 0
+	for _, s := range spectrum[:n/2] {
+		if math.Hypot(real(s), imag(s)) < threshold {
+			below++
+		}
+	}
+
+	d := (float64(below) - expected) / math.Sqrt(float64(n)*0.95*0.05/4)
+	return math.Erfc(math.Abs(d) / math.Sqrt2), nil
+}
+
+// patternCounts counts the overlapping m-bit patterns of the sequence
+// extended cyclically by its first m-1 bits.
+func patternCounts(bits Bits, m int) []int {
+	counts := make([]int, 1<<m)
+	if m == 0 {
+		return counts
+	}
+
+	n := len(bits)
+	mask := 1<<m - 1
+
+	pattern := 0
+	for i := 0; i < m-1; i++ {
+		pattern = pattern<<1 | int(bits[i])
+	}
+	for i := 0; i < n; i++ {
+		pattern = (pattern<<1 | int(bits[(i+m-1)%n])) & mask
+		counts[pattern]++
+	}
+	return counts
+}
+
+func psiSquared(bits Bits, m int) float64 {
+	if m <= 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, c := range patternCounts(bits, m) {
+		sum += float64(c) * float64(c)
+	}
+	n := float64(len(bits))
+	return sum*float64(int(1)<<m)/n - n
+}
+
+func Serial(bits Bits, m int) (float64, float64, error) {
+	if m < 2 {
+		return 0, 0, fmt.Errorf("Serial: pattern length must be at least 2 (got %d)", m)
+	}
+	if len(bits) < m {
+		return 0, 0, fmt.Errorf("Serial: %w", ErrSequenceTooShort)
+	}
+
+	psiM := psiSquared(bits, m)
+	psiM1 := psiSquared(bits, m-1)
+	psiM2 := psiSquared(bits, m-2)
+
+	delta1 := psiM - psiM1
+	delta2 := psiM - 2*psiM1 + psiM2
+
+	p1 := igamc(math.Pow(2, float64(m-2)), delta1/2)
+	p2 := igamc(math.Pow(2, float64(m-3)), delta2/2)
+	return p1, p2, nil
+}
+
+func ApproximateEntropy(bits Bits, m int) (float64, error) {
+	if m < 1 {
+		return 0, fmt.Errorf("ApproximateEntropy: block length must be positive (got %d)", m)
+	}
+	if len(bits) <= m {
+		return 0, fmt.Errorf("ApproximateEntropy: %w", ErrSequenceTooShort)
+	}
+
+	n := float64(len(bits))
+	phi := func(m int) float64 {
+		sum := 0.0
+		for _, c := range patternCounts(bits, m) {
+			if c > 0 {
+				p := float64(c) / n
+				sum += p * math.Log(p)
+			}
+		}
+		return sum
+	}
+
+	apEn := phi(m) - phi(m+1)
+	chiSquared := 2 * n * (math.Ln2 - apEn)
+	return igamc(math.Pow(2, float64(m-1)), chiSquared/2), nil
+}
+
+// CumulativeSums returns the p-values of the forward and backward modes.
+func CumulativeSums(bits Bits) (float64, float64, error) {
+	if len(bits) == 0 {
+		return 0, 0, fmt.Errorf("CumulativeSums: %w", ErrSequenceTooShort)
+	}
+
+	n := len(bits)
+	maxExcursion := func(reverse bool) int {
+		sum, z := 0, 0
+		for i := range bits {
+			bit := bits[i]
+			if reverse {
+				bit = bits[n-1-i]
+			}
+			sum += 2*int(bit) - 1
+			z = max(z, sum, -sum)
+		}
+		return z
+	}
+
+	return cusumPValue(n, maxExcursion(false)), cusumPValue(n, maxExcursion(true)), nil
+}
+
+func cusumPValue(n, z int) float64 {
+	sqrtN := math.Sqrt(float64(n))
+	fz := float64(z)
+
+	sum1 := 0.0
+	for k := (-n/z + 1) / 4; k <= (n/z-1)/4; k++ {
+		sum1 += normalCDF(float64(4*k+1) * fz / sqrtN)
+		sum1 -= normalCDF(float64(4*k-1) * fz / sqrtN)
+	}
+
+	sum2 := 0.0
+	for k := (-n/z - 3) / 4; k <= (n/z-1)/4; k++ {
+		sum2 += normalCDF(float64(4*k+3) * fz / sqrtN)
+		sum2 -= normalCDF(float64(4*k+1) * fz / sqrtN)
+	}
+
+	return 1 - sum1 + sum2
+}
@@ -0,0 +1,130 @@
+package randomness
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Worked examples from NIST SP 800-22 Rev. 1a, section 2.
+const (
+	example100 = "11001001000011111101101010100010001000010110100011" +
+		"00001000110100110001001100011001100010100010111000"
+	example128 = "11001100000101010110110001001100111000000000001001" +
+		"00110101010001000100111101011010000000110101111100" +
+		"1100111001101101100010110010"
+)
+
+func parse(t *testing.T, s string) Bits {
+	t.Helper()
+	bits, err := ParseBits(s)
+	if err != nil {
+		t.Fatalf("ParseBits: %v", err)
+	}
+	return bits
+}
+
+func checkPValue(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 5e-6 {
+		t.Errorf("%s: p-value %.6f, want %.6f", name, got, want)
+	}
+}
+
+func TestNISTExamples(t *testing.T) {
+	single := []struct {
+		name string
+		bits string
+		test func(Bits) (float64, error)
+		want float64
+	}{
+		{"Frequency", "1011010101", Frequency, 0.527089},
+		{"Frequency", example100, Frequency, 0.109599},
+		{"BlockFrequency(M=3)", "0110011010", func(b Bits) (float64, error) { return BlockFrequency(b, 3) }, 0.801252},
+		{"BlockFrequency(M=10)", example100, func(b Bits) (float64, error) { return BlockFrequency(b, 10) }, 0.706438},
+		{"Runs", "1001101011", Runs, 0.147232},
+		{"Runs", example100, Runs, 0.500798},
+		{"LongestRun", example128, LongestRun, 0.180609},
+		// The section 2.6 examples count one peak fewer than the reference
+		// implementation; these p-values follow the reference code.
+		{"FFT", "1001010011", Spectral, 0.468160},
+		{"FFT", example100, Spectral, 0.646355},
+		{"ApproximateEntropy(m=3)", "0100110101", func(b Bits) (float64, error) { return ApproximateEntropy(b, 3) }, 0.261961},
+		{"ApproximateEntropy(m=2)", example100, func(b Bits) (float64, error) { return ApproximateEntropy(b, 2) }, 0.235301},
+	}
+
+	for _, c := range single {
+		p, err := c.test(parse(t, c.bits))
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		checkPValue(t, c.name, p, c.want)
+	}
+
+	p1, p2, err := Serial(parse(t, "0011011101"), 3)
+	if err != nil {
+		t.Fatalf("Serial: %v", err)
+	}
+	checkPValue(t, "Serial p1", p1, 0.808792)
+	checkPValue(t, "Serial p2", p2, 0.670320)
+
+	forward, _, err := CumulativeSums(parse(t, "1011010111"))
+	if err != nil {
+		t.Fatalf("CumulativeSums: %v", err)
+	}
+	checkPValue(t, "CumulativeSums forward", forward, 0.4116588)
+
+	forward, backward, err := CumulativeSums(parse(t, example100))
+	if err != nil {
+		t.Fatalf("CumulativeSums: %v", err)
+	}
+	checkPValue(t, "CumulativeSums forward", forward, 0.219194)
+	checkPValue(t, "CumulativeSums backward", backward, 0.114866)
+}
+
+func TestDFT(t *testing.T) {
+	for _, n := range []int{1, 2, 10, 16, 100, 127} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(float64((i*7)%5)-2, float64(i%3))
+		}
+
+		got := dft(x)
+		for k := 0; k < n; k++ {
+			var want complex128
+			for j, v := range x {
+				want += v * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/float64(n)))
+			}
+			if cmplx.Abs(got[k]-want) > 1e-9*float64(n) {
+				t.Fatalf("n=%d: X[%d] = %v, want %v", n, k, got[k], want)
+			}
+		}
+	}
+}
+
+func TestIgamc(t *testing.T) {
+	cases := []struct{ a, x, want float64 }{
+		{1, 1, math.Exp(-1)},
+		{1, 5, math.Exp(-5)},
+		{0.5, 2, math.Erfc(math.Sqrt2)},
+		{3, 2.5, math.Exp(-2.5) * (1 + 2.5 + 2.5*2.5/2)},
+		{40, 35, poissonTail(40, 35)},
+		{200, 230, poissonTail(200, 230)},
+	}
+	for _, c := range cases {
+		if got := igamc(c.a, c.x); math.Abs(got-c.want) > 1e-6 {
+			t.Errorf("igamc(%v, %v) = %v, want %v", c.a, c.x, got, c.want)
+		}
+	}
+}
+
+// poissonTail is Q(a, x) for integer a: e^-x * sum_{k<a} x^k/k!.
+func poissonTail(a int, x float64) float64 {
+	term, sum := math.Exp(-x), 0.0
+	for k := 0; k < a; k++ {
+		sum += term
+		term *= x / float64(k+1)
+	}
+	return sum
+}
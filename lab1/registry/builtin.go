@@ -0,0 +1,96 @@
+package registry
+
+import (
+	"fmt"
+	"lab1/deal"
+	"lab1/des"
+	"lab1/desx"
+	"lab1/interfaces"
+	"lab1/spn"
+	tripledes "lab1/tripleDes"
+	"strconv"
+)
+
+func init() {
+	builtins := []Algorithm{
+		{
+			Name:        "des",
+			Description: "DES, 64-bit block, 64-bit key (56 effective bits)",
+			New: func(map[string]string) (interfaces.BlockCipher, int, error) {
+				cipher, err := des.NewDES()
+				return cipher, des.DESKeySize, err
+			},
+		},
+		{
+			Name:        "desx",
+			Description: "DES-X, DES with 64-bit pre- and post-whitening, 192-bit key",
+			New: func(map[string]string) (interfaces.BlockCipher, int, error) {
+				cipher, err := desx.NewDESX()
+				return cipher, desx.DESXKeySize, err
+			},
+		},
+		{
+			Name:        "2des",
+			Description: "DoubleDES, 64-bit block, 128-bit key (meet-in-the-middle demonstration)",
+			New: func(map[string]string) (interfaces.BlockCipher, int, error) {
+				cipher, err := tripledes.NewDoubleDES()
+				return cipher, 16, err
+			},
+		},
+		{
+			Name:        "3des",
+			Description: "TripleDES, 64-bit block, 192-bit key",
+			Params: []Param{
+				{Name: "variant", Description: "encryption sequence", Default: "ede", Values: []string{"ede", "eee"}},
+			},
+			New: func(params map[string]string) (interfaces.BlockCipher, int, error) {
+				mode := tripledes.EDE
+				if params["variant"] == "eee" {
+					mode = tripledes.EEE
+				}
+				cipher, err := tripledes.NewTripleDES(mode)
+				return cipher, 24, err
+			},
+		},
+		{
+			Name:        "deal",
+			Description: "DEAL, 128-bit block Feistel network over DES",
+			Params: []Param{
+				{Name: "key", Description: "key size in bits", Default: "128", Values: []string{"128"}},
+				{Name: "rounds", Description: "number of rounds", Default: "6"},
+			},
+			New: func(params map[string]string) (interfaces.BlockCipher, int, error) {
+				rounds, err := strconv.Atoi(params["rounds"])
+				if err != nil || rounds <= 0 {
+					return nil, 0, fmt.Errorf("invalid number of rounds %q", params["rounds"])
+				}
+				cipher, err := deal.NewDEAL(rounds)
+				return cipher, 16, err
+			},
+		},
+		{
+			Name:        "spn",
+			Description: "substitution-permutation network from a built-in definition",
+			Params: []Param{
+				{Name: "definition", Description: "cipher definition", Default: "present80", Values: spn.BuiltinNames()},
+			},
+			New: func(params map[string]string) (interfaces.BlockCipher, int, error) {
+				definition, err := spn.Builtin(params["definition"])
+				if err != nil {
+					return nil, 0, err
+				}
+				cipher, err := spn.NewCipher(definition)
+				if err != nil {
+					return nil, 0, err
+				}
+				return cipher, cipher.KeySize(), nil
+			},
+		},
+	}
+
+	for _, algorithm := range builtins {
+		if err := Register(algorithm); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"lab1/interfaces"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Param struct {
+	Name        string
+	Description string
+	Default     string
+	Values      []string
+}
+
+type Factory func(params map[string]string) (cipher interfaces.BlockCipher, keySize int, err error)
+
+type Algorithm struct {
+	Name        string
+	Description string
+	Params      []Param
+	New         Factory
+}
+
+type Spec struct {
+	Algorithm string
+	Params    map[string]string
+	Mode      interfaces.CipherMode
+}
+
+var (
+	mu         sync.RWMutex
+	algorithms = make(map[string]Algorithm)
+)
+
+func Register(algorithm Algorithm) error {
+	name := strings.ToLower(algorithm.Name)
+	if name == "" || strings.Contains(name, "-") {
+		return fmt.Errorf("invalid algorithm name %q", algorithm.Name)
+	}
+
+	if algorithm.New == nil {
+		return fmt.Errorf("algorithm %q has no factory", algorithm.Name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := algorithms[name]; exists {
+		return fmt.Errorf("algorithm %q is already registered", algorithm.Name)
+	}
+
+	algorithm.Name = name
+	algorithm.Params = slices.Clone(algorithm.Params)
+	algorithms[name] = algorithm
+	return nil
+}
+
+func Algorithms() []Algorithm {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]Algorithm, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		result = append(result, algorithm)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func Lookup(name string) (Algorithm, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	algorithm, ok := algorithms[strings.ToLower(name)]
+	return algorithm, ok
+}
+
+func (a Algorithm) Usage() string {
+	var sb strings.Builder
+	sb.WriteString(a.Name)
+	for _, param := range a.Params {
+		fmt.Fprintf(&sb, "[-%s]", strings.ToUpper(param.Name))
+	}
+	return sb.String()
+}
+
+func ParseSpec(spec string) (*Spec, error) {
+	i := strings.LastIndex(spec, "-")
+	if i < 0 {
+		return nil, fmt.Errorf("spec %q must end with a cipher mode", spec)
+	}
+
+	mode, err := interfaces.ParseCipherMode(spec[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("spec %q: %w", spec, err)
+	}
+
+	parsed, err := ParseCipher(spec[:i])
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.Mode = mode
+	return parsed, nil
+}
+
+func ParseCipher(name string) (*Spec, error) {
+	if name == "" {
+		return nil, errors.New("empty cipher name")
+	}
+
+	tokens := strings.Split(name, "-")
+
+	algorithm, ok := Lookup(tokens[0])
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm %q", tokens[0])
+	}
+
+	values := tokens[1:]
+	if len(values) > len(algorithm.Params) {
+		return nil, fmt.Errorf("%q: too many parameters, expected %s", name, algorithm.Usage())
+	}
+
+	params := make(map[string]string, len(algorithm.Params))
+	for i, param := range algorithm.Params {
+		value := param.Default
+		if i < len(values) {
+			value = values[i]
+		}
+
+		if value == "" {
+			return nil, fmt.Errorf("%q: missing parameter %s", name, param.Name)
+		}
+
+		if len(param.Values) > 0 {
+			index := slices.IndexFunc(param.Values, func(v string) bool {
+				return strings.EqualFold(v, value)
+			})
+			if index < 0 {
+				return nil, fmt.Errorf("%q: invalid %s %q (allowed: %s)", name, param.Name, value, strings.Join(param.Values, ", "))
+			}
+			value = param.Values[index]
+		}
+
+		params[param.Name] = value
+	}
+
+	return &Spec{Algorithm: algorithm.Name, Params: params}, nil
+}
+
+func (s *Spec) String() string {
+	algorithm, ok := Lookup(s.Algorithm)
+	if !ok {
+		return s.Algorithm
+	}
+
+	parts := []string{algorithm.Name}
+	for _, param := range algorithm.Params {
+		parts = append(parts, s.Params[param.Name])
+	}
+	return strings.Join(append(parts, s.Mode.String()), "-")
+}
+
+func (s *Spec) NewBlockCipher() (interfaces.BlockCipher, int, error) {
+	algorithm, ok := Lookup(s.Algorithm)
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown algorithm %q", s.Algorithm)
+	}
+
+	cipher, keySize, err := algorithm.New(s.Params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", s.Algorithm, err)
+	}
+	return cipher, keySize, nil
+}
+
+func NewBlockCipher(name string) (interfaces.BlockCipher, int, error) {
+	spec, err := ParseCipher(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	return spec.NewBlockCipher()
+}
+
+// NewCipherContext builds a context for spec, whose mode it uses. A
+// non-zero config.Mode must agree with it; ECB is the zero mode, so it
+// cannot be told apart from an unset one.
+func NewCipherContext(spec string, config interfaces.CipherContextConfig) (*interfaces.CipherContext, error) {
+	parsed, err := ParseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	if config.Mode != 0 && config.Mode != parsed.Mode {
+		return nil, fmt.Errorf("config mode %v conflicts with mode %v of spec %q", config.Mode, parsed.Mode, spec)
+	}
+
+	cipher, _, err := parsed.NewBlockCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	config.Mode = parsed.Mode
+	return interfaces.NewCipherContext(cipher, config)
+}
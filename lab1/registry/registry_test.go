@@ -0,0 +1,150 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"lab1/interfaces"
+	"testing"
+)
+
+func TestParseSpec(t *testing.T) {
+	cases := []struct {
+		spec      string
+		algorithm string
+		params    map[string]string
+		mode      interfaces.CipherMode
+		canonical string
+	}{
+		{"des-ECB", "des", map[string]string{}, interfaces.ECB, "des-ECB"},
+		{"desx-CTR", "desx", map[string]string{}, interfaces.CTR, "desx-CTR"},
+		{"2des-CBC", "2des", map[string]string{}, interfaces.CBC, "2des-CBC"},
+		{"3des-ede-CTR", "3des", map[string]string{"variant": "ede"}, interfaces.CTR, "3des-ede-CTR"},
+		{"3DES-EEE-cbc", "3des", map[string]string{"variant": "eee"}, interfaces.CBC, "3des-eee-CBC"},
+		{"3des-OFB", "3des", map[string]string{"variant": "ede"}, interfaces.OFB, "3des-ede-OFB"},
+		{"deal-128-OFB", "deal", map[string]string{"key": "128", "rounds": "6"}, interfaces.OFB, "deal-128-6-OFB"},
+		{"deal-128-8-RandomDelta", "deal", map[string]string{"key": "128", "rounds": "8"}, interfaces.RandomDelta, "deal-128-8-RandomDelta"},
+		{"spn-heys-CTR", "spn", map[string]string{"definition": "heys"}, interfaces.CTR, "spn-heys-CTR"},
+	}
+
+	for _, c := range cases {
+		spec, err := ParseSpec(c.spec)
+		if err != nil {
+			t.Errorf("ParseSpec(%q): %v", c.spec, err)
+			continue
+		}
+
+		if spec.Algorithm != c.algorithm || spec.Mode != c.mode || len(spec.Params) != len(c.params) {
+			t.Errorf("ParseSpec(%q) = %+v", c.spec, spec)
+		}
+		for name, value := range c.params {
+			if spec.Params[name] != value {
+				t.Errorf("ParseSpec(%q): param %s = %q, want %q", c.spec, name, spec.Params[name], value)
+			}
+		}
+
+		if spec.String() != c.canonical {
+			t.Errorf("ParseSpec(%q).String() = %q, want %q", c.spec, spec.String(), c.canonical)
+		}
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"des",
+		"des-XTS",
+		"blowfish-CBC",
+		"des-56-CBC",
+		"3des-eed-CBC",
+		"deal-192-CBC",
+		"deal-128-6-7-CBC",
+	} {
+		if _, err := ParseSpec(spec); err == nil {
+			t.Errorf("ParseSpec(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestNewCipherContext(t *testing.T) {
+	cc, err := NewCipherContext("3des-ede-CBC", interfaces.CipherContextConfig{
+		Key:     []byte("0123456789ABCDEF01234567"),
+		Mode:    interfaces.CBC,
+		Padding: interfaces.PKCS7,
+	})
+	if err != nil {
+		t.Fatalf("NewCipherContext: %v", err)
+	}
+
+	ctx := context.Background()
+	plaintext := []byte("registry round trip")
+
+	encrypted, err := cc.EncryptBytes(ctx, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptBytes: %v", err)
+	}
+
+	decrypted, err := cc.DecryptBytes(ctx, encrypted)
+	if err != nil {
+		t.Fatalf("DecryptBytes: %v", err)
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("got %q, want %q", decrypted, plaintext)
+	}
+
+	if _, err := NewCipherContext("spn-present80-CBC", interfaces.CipherContextConfig{Key: make([]byte, 10)}); err != nil {
+		t.Errorf("NewCipherContext(spn-present80-CBC): %v", err)
+	}
+	if _, err := NewCipherContext("des-CBC", interfaces.CipherContextConfig{Key: make([]byte, 8), Mode: interfaces.CTR}); err == nil {
+		t.Error("NewCipherContext accepted config mode CTR for a CBC spec")
+	}
+	if _, err := NewCipherContext("deal-128-0-CBC", interfaces.CipherContextConfig{Key: make([]byte, 16)}); err == nil {
+		t.Error("NewCipherContext accepted zero DEAL rounds")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	simple := Algorithm{
+		Name:        "simple",
+		Description: "test cipher",
+		Params:      []Param{{Name: "block", Default: "16", Values: []string{"8", "16"}}},
+		New: func(params map[string]string) (interfaces.BlockCipher, int, error) {
+			blockSize := 16
+			if params["block"] == "8" {
+				blockSize = 8
+			}
+			return interfaces.NewSimpleCipher(blockSize, &interfaces.SimpleKeyExpander{}, &interfaces.SimpleRoundTransformer{}), blockSize, nil
+		},
+	}
+
+	if err := Register(simple); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := Register(simple); err == nil {
+		t.Error("Register accepted a duplicate algorithm")
+	}
+	if err := Register(Algorithm{Name: "bad-name", New: simple.New}); err == nil {
+		t.Error("Register accepted a name containing '-'")
+	}
+	if err := Register(Algorithm{Name: "nofactory"}); err == nil {
+		t.Error("Register accepted an algorithm without a factory")
+	}
+
+	cipher, keySize, err := NewBlockCipher("simple-8")
+	if err != nil {
+		t.Fatalf("NewBlockCipher: %v", err)
+	}
+	if cipher.BlockSize() != 8 || keySize != 8 {
+		t.Errorf("simple-8: block size %d, key size %d", cipher.BlockSize(), keySize)
+	}
+
+	names := make(map[string]bool)
+	for _, algorithm := range Algorithms() {
+		names[algorithm.Name] = true
+	}
+	for _, name := range []string{"des", "3des", "deal", "simple"} {
+		if !names[name] {
+			t.Errorf("Algorithms() is missing %q", name)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package spn
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed definitions
+var builtinDefinitions embed.FS
+
+// Definition describes an SPN. Every round XORs a round key into the state,
+// substitutes it with the S-box, permutes its bits and applies the linear
+// layer; after the last round one more round key is added, so a cipher
+// uses Rounds+1 round keys.
+type Definition struct {
+	Name      string `json:"name" yaml:"name"`
+	BlockBits int    `json:"block_bits" yaml:"block_bits"`
+	Rounds    int    `json:"rounds" yaml:"rounds"`
+
+	SBox        SBoxDefinition         `json:"sbox" yaml:"sbox"`
+	Permutation *PermutationDefinition `json:"permutation,omitempty" yaml:"permutation,omitempty"`
+	LinearLayer *LinearDefinition      `json:"linear_layer,omitempty" yaml:"linear_layer,omitempty"`
+	// LastRoundDiffusion keeps the permutation and linear layer in the
+	// last round, as PRESENT does; Heys' cipher drops them.
+	LastRoundDiffusion bool `json:"last_round_diffusion,omitempty" yaml:"last_round_diffusion,omitempty"`
+
+	KeySchedule KeyScheduleDefinition `json:"key_schedule" yaml:"key_schedule"`
+}
+
+// SBoxDefinition is a bijective S-box applied to every Bits-wide chunk of
+// the state, table[x] being the output for x.
+type SBoxDefinition struct {
+	Bits  int   `json:"bits" yaml:"bits"`
+	Table []int `json:"table" yaml:"table"`
+}
+
+// PermutationDefinition moves state bit i to position Table[i]. BitOrder
+// "msb" (the default) numbers bits from the most significant bit of the
+// block, "lsb" from the least significant, as the PRESENT paper does.
+type PermutationDefinition struct {
+	BitOrder string `json:"bit_order,omitempty" yaml:"bit_order,omitempty"`
+	Table    []int  `json:"table" yaml:"table"`
+}
+
+// LinearDefinition multiplies every Width-bit word of the state by an
+// invertible binary matrix. Rows are strings of '0' and '1': output bit i
+// of a word is the parity of the word ANDed with row i, bits counted from
+// the most significant.
+type LinearDefinition struct {
+	Width int      `json:"width" yaml:"width"`
+	Rows  []string `json:"rows" yaml:"rows"`
+}
+
+// Key schedule types.
+const (
+	// IndependentKeys takes the key as Rounds+1 concatenated round keys.
+	IndependentKeys = "independent"
+	// RegisterKeys is the PRESENT schedule: each round key is the top
+	// BlockBits of a KeyBits register, which is then rotated left by
+	// Rotation, has its top SBoxChunks chunks substituted and the round
+	// counter XORed in at CounterBit, counted from the least significant
+	// bit.
+	RegisterKeys = "register"
+)
+
+type KeyScheduleDefinition struct {
+	Type       string `json:"type" yaml:"type"`
+	KeyBits    int    `json:"key_bits,omitempty" yaml:"key_bits,omitempty"`
+	Rotation   int    `json:"rotation,omitempty" yaml:"rotation,omitempty"`
+	SBoxChunks int    `json:"sbox_chunks,omitempty" yaml:"sbox_chunks,omitempty"`
+	CounterBit int    `json:"counter_bit,omitempty" yaml:"counter_bit,omitempty"`
+}
+
+// ParseDefinition decodes a JSON definition, rejecting unknown fields.
+func ParseDefinition(data []byte) (*Definition, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var definition Definition
+	if err := decoder.Decode(&definition); err != nil {
+		return nil, fmt.Errorf("invalid SPN definition: %w", err)
+	}
+	return &definition, nil
+}
+
+// ParseYAMLDefinition decodes a YAML definition with the same field names
+// as the JSON one, rejecting unknown fields.
+func ParseYAMLDefinition(data []byte) (*Definition, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var definition Definition
+	if err := decoder.Decode(&definition); err != nil {
+		return nil, fmt.Errorf("invalid SPN definition: %w", err)
+	}
+	return &definition, nil
+}
+
+// LoadDefinition reads a definition file, as YAML if its extension is
+// .yaml or .yml and as JSON otherwise.
+func LoadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SPN definition: %w", err)
+	}
+	return parseFile(path, data)
+}
+
+func parseFile(name string, data []byte) (*Definition, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return ParseYAMLDefinition(data)
+	default:
+		return ParseDefinition(data)
+	}
+}
+
+// Builtin returns one of the definitions shipped with the package, such
+// as "heys" (a YAML file) or "present80" (a JSON file).
+func Builtin(name string) (*Definition, error) {
+	entries, _ := builtinDefinitions.ReadDir("definitions")
+	for _, entry := range entries {
+		if definitionName(entry.Name()) == name {
+			file := path.Join("definitions", entry.Name())
+			data, err := builtinDefinitions.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read SPN definition: %w", err)
+			}
+			return parseFile(file, data)
+		}
+	}
+	return nil, fmt.Errorf("unknown SPN definition %q (available: %s)", name, strings.Join(BuiltinNames(), ", "))
+}
+
+func BuiltinNames() []string {
+	entries, _ := builtinDefinitions.ReadDir("definitions")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, definitionName(entry.Name()))
+	}
+	slices.Sort(names)
+	return names
+}
+
+func definitionName(file string) string {
+	return strings.TrimSuffix(file, path.Ext(file))
+}
+
+// KeySize returns the key length in bytes the definition expects.
+func (d *Definition) KeySize() int {
+	if d.KeySchedule.Type == RegisterKeys {
+		return (d.KeySchedule.KeyBits + 7) / 8
+	}
+	return (d.Rounds + 1) * d.BlockBits / 8
+}
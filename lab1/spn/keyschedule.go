@@ -0,0 +1,94 @@
+package spn
+
+import (
+	"fmt"
+	"lab1/interfaces"
+	"lab1/permutations"
+	"math/bits"
+)
+
+type keySchedule struct {
+	definition KeyScheduleDefinition
+	blockSize  int
+	keySize    int
+	// count is the number of round keys ExpandKey returns.
+	count int
+	sbox  *permutations.SBox
+}
+
+func newKeySchedule(definition *Definition, sbox *permutations.SBox) (*keySchedule, error) {
+	schedule := definition.KeySchedule
+	ks := &keySchedule{
+		definition: schedule,
+		blockSize:  definition.BlockBits / 8,
+		keySize:    definition.KeySize(),
+		count:      definition.Rounds + 1,
+		sbox:       sbox,
+	}
+
+	switch schedule.Type {
+	case "", IndependentKeys:
+		ks.definition.Type = IndependentKeys
+	case RegisterKeys:
+		keyBits := schedule.KeyBits
+		if keyBits < definition.BlockBits || keyBits%8 != 0 {
+			return nil, fmt.Errorf("key register must be a whole number of bytes and at least the %d-bit block (got %d bits)", definition.BlockBits, keyBits)
+		}
+		if schedule.Rotation < 0 || schedule.Rotation >= keyBits {
+			return nil, fmt.Errorf("key rotation must be 0..%d (got %d)", keyBits-1, schedule.Rotation)
+		}
+		if schedule.SBoxChunks < 0 || schedule.SBoxChunks*sbox.InputBits() > keyBits {
+			return nil, fmt.Errorf("%d S-box chunks do not fit in the %d-bit key register", schedule.SBoxChunks, keyBits)
+		}
+		if schedule.CounterBit < 0 || schedule.CounterBit+bits.Len(uint(definition.Rounds)) > keyBits {
+			return nil, fmt.Errorf("round counter at bit %d does not fit in the %d-bit key register", schedule.CounterBit, keyBits)
+		}
+	default:
+		return nil, fmt.Errorf("unknown key schedule type %q", schedule.Type)
+	}
+	return ks, nil
+}
+
+// ExpandKey returns the round keys, the last one added after the final round.
+func (ks *keySchedule) ExpandKey(key []byte) ([][]byte, error) {
+	if len(key) != ks.keySize {
+		return nil, &interfaces.KeySizeError{Cipher: "SPN", Size: len(key), Valid: []int{ks.keySize}}
+	}
+
+	roundKeys := make([][]byte, ks.count)
+	if ks.definition.Type == IndependentKeys {
+		for i := range roundKeys {
+			roundKeys[i] = append([]byte(nil), key[i*ks.blockSize:(i+1)*ks.blockSize]...)
+		}
+		return roundKeys, nil
+	}
+
+	register := append([]byte(nil), key...)
+	rotated := make([]byte, len(register))
+	defer interfaces.Zeroize(register)
+	defer interfaces.Zeroize(rotated)
+
+	keyBits := len(register) * 8
+	sboxBits := ks.sbox.InputBits()
+	for i := range roundKeys {
+		roundKeys[i] = append([]byte(nil), register[:ks.blockSize]...)
+
+		for bit := range keyBits {
+			permutations.WriteBits(rotated, bit, 1, permutations.ReadBits(register, (bit+ks.definition.Rotation)%keyBits, 1))
+		}
+		register, rotated = rotated, register
+
+		for chunk := range ks.definition.SBoxChunks {
+			start := chunk * sboxBits
+			value := ks.sbox.Lookup(uint32(permutations.ReadBits(register, start, sboxBits)))
+			permutations.WriteBits(register, start, sboxBits, uint64(value))
+		}
+
+		counter := uint64(i + 1)
+		for bit := 0; counter>>bit != 0; bit++ {
+			position := keyBits - 1 - ks.definition.CounterBit - bit
+			permutations.WriteBits(register, position, 1, permutations.ReadBits(register, position, 1)^counter>>bit&1)
+		}
+	}
+	return roundKeys, nil
+}
@@ -0,0 +1,86 @@
+package spn
+
+import (
+	"errors"
+	"fmt"
+	"lab1/permutations"
+	"math/bits"
+)
+
+// linearLayer multiplies every width-bit word of the state by a binary
+// matrix. Bit width-1-j of rows[i] is the coefficient of input bit j in
+// output bit i, both counted from the most significant bit of the word.
+type linearLayer struct {
+	width int
+	rows  []uint64
+}
+
+func newLinearLayer(definition *LinearDefinition, blockBits int) (*linearLayer, error) {
+	width := definition.Width
+	if width < 1 || width > 64 || blockBits%width != 0 {
+		return nil, fmt.Errorf("linear layer width must be 1..64 bits and divide the %d-bit block (got %d)", blockBits, width)
+	}
+	if len(definition.Rows) != width {
+		return nil, fmt.Errorf("linear layer needs %d rows (got %d)", width, len(definition.Rows))
+	}
+
+	l := &linearLayer{width: width, rows: make([]uint64, width)}
+	for i, row := range definition.Rows {
+		if len(row) != width {
+			return nil, fmt.Errorf("linear layer row %d has %d columns, want %d", i, len(row), width)
+		}
+		for _, c := range row {
+			if c != '0' && c != '1' {
+				return nil, fmt.Errorf("linear layer row %d contains %q, want only 0 and 1", i, c)
+			}
+			l.rows[i] = l.rows[i]<<1 | uint64(c-'0')
+		}
+	}
+	return l, nil
+}
+
+// apply transforms state in place.
+func (l *linearLayer) apply(state []byte) {
+	for start := 0; start < len(state)*8; start += l.width {
+		word := permutations.ReadBits(state, start, l.width)
+		var result uint64
+		for _, row := range l.rows {
+			result = result<<1 | uint64(bits.OnesCount64(word&row)&1)
+		}
+		permutations.WriteBits(state, start, l.width, result)
+	}
+}
+
+// inverse inverts the matrix by Gauss-Jordan elimination over GF(2).
+func (l *linearLayer) inverse() (*linearLayer, error) {
+	n := l.width
+	matrix := append([]uint64(nil), l.rows...)
+	inverse := make([]uint64, n)
+	for i := range inverse {
+		inverse[i] = 1 << (n - 1 - i)
+	}
+
+	for column := range n {
+		mask := uint64(1) << (n - 1 - column)
+		pivot := -1
+		for row := column; row < n; row++ {
+			if matrix[row]&mask != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot < 0 {
+			return nil, errors.New("linear layer matrix is not invertible")
+		}
+		matrix[column], matrix[pivot] = matrix[pivot], matrix[column]
+		inverse[column], inverse[pivot] = inverse[pivot], inverse[column]
+
+		for row := range n {
+			if row != column && matrix[row]&mask != 0 {
+				matrix[row] ^= matrix[column]
+				inverse[row] ^= inverse[column]
+			}
+		}
+	}
+	return &linearLayer{width: n, rows: inverse}, nil
+}
@@ -0,0 +1,273 @@
+// Package spn builds substitution-permutation network block ciphers from a
+// Definition, so toy ciphers such as Heys' tutorial SPN or PRESENT can be
+// prototyped without code and studied with the analysis package.
+package spn
+
+import (
+	"errors"
+	"fmt"
+	"lab1/interfaces"
+	"lab1/permutations"
+)
+
+// Cipher is the BlockCipher described by a Definition.
+type Cipher struct {
+	name      string
+	blockSize int
+	keySize   int
+	rounds    int
+
+	sbox, inverseSBox               *permutations.SBox
+	permutation, inversePermutation *permutations.Permutation
+	linear, inverseLinear           *linearLayer
+	lastRoundDiffusion              bool
+
+	schedule  *keySchedule
+	roundKeys [][]byte
+	destroyed bool
+}
+
+func NewCipher(definition *Definition) (*Cipher, error) {
+	if definition == nil {
+		return nil, errors.New("SPN definition cannot be nil")
+	}
+	return NewReducedCipher(definition, definition.Rounds)
+}
+
+// NewReducedCipher builds the cipher with only the first rounds rounds of
+// the definition. It takes the same keys as the full cipher and uses the
+// first rounds+1 round keys of its schedule, so AnalyzeRounds can compare
+// round counts with one key size.
+func NewReducedCipher(definition *Definition, rounds int) (*Cipher, error) {
+	if definition == nil {
+		return nil, errors.New("SPN definition cannot be nil")
+	}
+	c, err := compile(definition)
+	if err != nil {
+		return nil, fmt.Errorf("SPN definition %q: %w", definition.Name, err)
+	}
+	if rounds < 1 || rounds > definition.Rounds {
+		return nil, fmt.Errorf("%s rounds must be between 1 and %d (got %d)", c.name, definition.Rounds, rounds)
+	}
+	c.rounds = rounds
+	c.schedule.count = rounds + 1
+	return c, nil
+}
+
+func compile(definition *Definition) (*Cipher, error) {
+	blockBits := definition.BlockBits
+	if blockBits < 8 || blockBits%8 != 0 {
+		return nil, fmt.Errorf("block must be a positive whole number of bytes (got %d bits)", blockBits)
+	}
+	if definition.Rounds < 1 {
+		return nil, fmt.Errorf("rounds must be positive (got %d)", definition.Rounds)
+	}
+
+	c := &Cipher{
+		name:               definition.Name,
+		blockSize:          blockBits / 8,
+		keySize:            definition.KeySize(),
+		lastRoundDiffusion: definition.LastRoundDiffusion,
+	}
+	if c.name == "" {
+		c.name = "SPN"
+	}
+
+	var err error
+	if definition.SBox.Bits < 1 || blockBits%definition.SBox.Bits != 0 {
+		return nil, fmt.Errorf("%d-bit S-box does not divide the %d-bit block", definition.SBox.Bits, blockBits)
+	}
+	if c.sbox, err = permutations.NewSBox(definition.SBox.Bits, definition.SBox.Bits, definition.SBox.Table); err != nil {
+		return nil, err
+	}
+	if c.inverseSBox, err = c.sbox.Inverse(); err != nil {
+		return nil, err
+	}
+
+	if definition.Permutation != nil {
+		if c.permutation, err = compilePermutation(definition.Permutation, blockBits); err != nil {
+			return nil, err
+		}
+		if c.inversePermutation, err = c.permutation.Inverse(); err != nil {
+			return nil, err
+		}
+	}
+
+	if definition.LinearLayer != nil {
+		if c.linear, err = newLinearLayer(definition.LinearLayer, blockBits); err != nil {
+			return nil, err
+		}
+		if c.inverseLinear, err = c.linear.inverse(); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.schedule, err = newKeySchedule(definition, c.sbox); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// compilePermutation turns the "bit i moves to Table[i]" form of a
+// definition into the P-block of permutations, which lists for every output
+// bit the input bit it takes.
+func compilePermutation(definition *PermutationDefinition, blockBits int) (*permutations.Permutation, error) {
+	var indexMode permutations.IndexMode
+	switch definition.BitOrder {
+	case "", "msb":
+		indexMode = permutations.HighToLow
+	case "lsb":
+		indexMode = permutations.LowToHigh
+	default:
+		return nil, fmt.Errorf("unknown permutation bit order %q, want msb or lsb", definition.BitOrder)
+	}
+	if len(definition.Table) != blockBits {
+		return nil, fmt.Errorf("permutation needs %d entries (got %d)", blockBits, len(definition.Table))
+	}
+
+	pBlock := make([]int, blockBits)
+	seen := make([]bool, blockBits)
+	for bit, target := range definition.Table {
+		if target < 0 || target >= blockBits || seen[target] {
+			return nil, fmt.Errorf("permutation entry %d is %d, which is out of range or repeated", bit, target)
+		}
+		seen[target] = true
+		pBlock[target] = bit
+	}
+	return permutations.NewPermutation(pBlock, blockBits/8, indexMode, permutations.ZeroBit)
+}
+
+func (c *Cipher) SetKey(key []byte) error {
+	if c.destroyed {
+		return interfaces.ErrDestroyed
+	}
+	roundKeys, err := c.schedule.ExpandKey(key)
+	if err != nil {
+		return err
+	}
+	c.clearRoundKeys()
+	c.roundKeys = roundKeys
+	return nil
+}
+
+func (c *Cipher) Encrypt(block []byte) ([]byte, error) {
+	if err := c.check(block); err != nil {
+		return nil, err
+	}
+
+	state := append([]byte(nil), block...)
+	scratch := make([]byte, c.blockSize)
+	for round := range c.rounds {
+		xorBytes(state, c.roundKeys[round])
+		if err := c.sbox.Apply(scratch, state); err != nil {
+			interfaces.Zeroize(state, scratch)
+			return nil, fmt.Errorf("S-box failed at round %d: %w", round, err)
+		}
+		state, scratch = scratch, state
+
+		if c.diffuses(round) {
+			if c.permutation != nil {
+				if err := c.permutation.Apply(scratch, state); err != nil {
+					interfaces.Zeroize(state, scratch)
+					return nil, fmt.Errorf("permutation failed at round %d: %w", round, err)
+				}
+				state, scratch = scratch, state
+			}
+			if c.linear != nil {
+				c.linear.apply(state)
+			}
+		}
+	}
+	xorBytes(state, c.roundKeys[c.rounds])
+
+	interfaces.Zeroize(scratch)
+	return state, nil
+}
+
+func (c *Cipher) Decrypt(block []byte) ([]byte, error) {
+	if err := c.check(block); err != nil {
+		return nil, err
+	}
+
+	state := append([]byte(nil), block...)
+	scratch := make([]byte, c.blockSize)
+	xorBytes(state, c.roundKeys[c.rounds])
+	for round := c.rounds - 1; round >= 0; round-- {
+		if c.diffuses(round) {
+			if c.inverseLinear != nil {
+				c.inverseLinear.apply(state)
+			}
+			if c.inversePermutation != nil {
+				if err := c.inversePermutation.Apply(scratch, state); err != nil {
+					interfaces.Zeroize(state, scratch)
+					return nil, fmt.Errorf("inverse permutation failed at round %d: %w", round, err)
+				}
+				state, scratch = scratch, state
+			}
+		}
+
+		if err := c.inverseSBox.Apply(scratch, state); err != nil {
+			interfaces.Zeroize(state, scratch)
+			return nil, fmt.Errorf("inverse S-box failed at round %d: %w", round, err)
+		}
+		state, scratch = scratch, state
+		xorBytes(state, c.roundKeys[round])
+	}
+
+	interfaces.Zeroize(scratch)
+	return state, nil
+}
+
+// diffuses reports whether the permutation and linear layer run in round.
+func (c *Cipher) diffuses(round int) bool {
+	return round < c.rounds-1 || c.lastRoundDiffusion
+}
+
+func (c *Cipher) check(block []byte) error {
+	if c.destroyed {
+		return interfaces.ErrDestroyed
+	}
+	if len(block) != c.blockSize {
+		return &interfaces.BlockSizeError{Cipher: c.name, Size: len(block), Valid: []int{c.blockSize}}
+	}
+	if c.roundKeys == nil {
+		return fmt.Errorf("round keys not set: %w", interfaces.ErrKeyNotSet)
+	}
+	return nil
+}
+
+func (c *Cipher) BlockSize() int {
+	return c.blockSize
+}
+
+// KeySize is the key length in bytes SetKey accepts.
+func (c *Cipher) KeySize() int {
+	return c.keySize
+}
+
+func (c *Cipher) Rounds() int {
+	return c.rounds
+}
+
+// SBox returns the S-box, for instance to analyze it with analysis.NewSBox.
+func (c *Cipher) SBox() *permutations.SBox {
+	return c.sbox
+}
+
+func (c *Cipher) Destroy() {
+	c.clearRoundKeys()
+	c.destroyed = true
+}
+
+func (c *Cipher) clearRoundKeys() {
+	for _, key := range c.roundKeys {
+		interfaces.Zeroize(key)
+	}
+	c.roundKeys = nil
+}
+
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
@@ -0,0 +1,313 @@
+package spn
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"lab1/analysis"
+	"lab1/interfaces"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func builtinCipher(t *testing.T, name string) *Cipher {
+	t.Helper()
+	definition, err := Builtin(name)
+	if err != nil {
+		t.Fatalf("Builtin(%q): %v", name, err)
+	}
+	c, err := NewCipher(definition)
+	if err != nil {
+		t.Fatalf("NewCipher(%q): %v", name, err)
+	}
+	return c
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors from the PRESENT paper (Bogdanov et al., CHES 2007).
+func TestPRESENT80Vectors(t *testing.T) {
+	c := builtinCipher(t, "present80")
+	if c.BlockSize() != 8 || c.KeySize() != 10 || c.Rounds() != 31 {
+		t.Fatalf("PRESENT-80 has %d-byte blocks, %d-byte keys, %d rounds", c.BlockSize(), c.KeySize(), c.Rounds())
+	}
+
+	for _, tc := range []struct{ key, plaintext, ciphertext string }{
+		{"00000000000000000000", "0000000000000000", "5579c1387b228445"},
+		{"ffffffffffffffffffff", "0000000000000000", "e72c46c0f5945049"},
+		{"00000000000000000000", "ffffffffffffffff", "a112ffc72f68417b"},
+		{"ffffffffffffffffffff", "ffffffffffffffff", "3333dcd3213210d2"},
+	} {
+		if err := c.SetKey(decodeHex(t, tc.key)); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		ciphertext, err := c.Encrypt(decodeHex(t, tc.plaintext))
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if got := hex.EncodeToString(ciphertext); got != tc.ciphertext {
+			t.Errorf("key %s, plaintext %s: got %s, want %s", tc.key, tc.plaintext, got, tc.ciphertext)
+		}
+		plaintext, err := c.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Decrypt: %v", err)
+		}
+		if got := hex.EncodeToString(plaintext); got != tc.plaintext {
+			t.Errorf("key %s: decrypted %s, want %s", tc.key, got, tc.plaintext)
+		}
+	}
+}
+
+// With all round keys zero, one round of Heys' cipher is S-box then
+// permutation, and the output key addition changes nothing.
+func TestHeysStructure(t *testing.T) {
+	definition, _ := Builtin("heys")
+	c, err := NewReducedCipher(definition, 2)
+	if err != nil {
+		t.Fatalf("NewReducedCipher: %v", err)
+	}
+	if c.KeySize() != 10 {
+		t.Fatalf("Heys key size %d, want 5 round keys of 2 bytes", c.KeySize())
+	}
+	if err := c.SetKey(make([]byte, 10)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	// 0x0001: S gives 0xEEE4, P transposes it as a 4x4 bit matrix into
+	// 0xEFE0, and the last round only substitutes: 0x070E.
+	ciphertext, err := c.Encrypt([]byte{0x00, 0x01})
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !bytes.Equal(ciphertext, []byte{0x07, 0x0E}) {
+		t.Errorf("got %X, want 070E", ciphertext)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	random := rand.NewChaCha8([32]byte{50})
+	withLinear, err := ParseDefinition([]byte(`{
+		"name": "toy",
+		"block_bits": 24,
+		"rounds": 3,
+		"sbox": {"bits": 3, "table": [3, 6, 0, 5, 7, 1, 4, 2]},
+		"permutation": {"table": [
+			0, 3, 6, 9, 12, 15, 18, 21, 1, 4, 7, 10,
+			13, 16, 19, 22, 2, 5, 8, 11, 14, 17, 20, 23
+		]},
+		"linear_layer": {"width": 8, "rows": [
+			"11000000", "01100000", "00110000", "00011000",
+			"00001100", "00000110", "00000011", "10000001"
+		]}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseDefinition: %v", err)
+	}
+	// Every row has even weight, so that matrix is singular; a last row of
+	// one bit makes it invertible.
+	if _, err := NewCipher(withLinear); err == nil {
+		t.Error("NewCipher accepted a singular linear layer")
+	}
+	withLinear.LinearLayer.Rows[7] = "00000001"
+
+	heys, _ := Builtin("heys")
+	present, _ := Builtin("present80")
+	for _, definition := range []*Definition{heys, present, withLinear} {
+		for rounds := 1; rounds <= min(definition.Rounds, 4); rounds++ {
+			c, err := NewReducedCipher(definition, rounds)
+			if err != nil {
+				t.Fatalf("%s: NewReducedCipher(%d): %v", definition.Name, rounds, err)
+			}
+			key := make([]byte, c.KeySize())
+			block := make([]byte, c.BlockSize())
+			for i := 0; i < 20; i++ {
+				random.Read(key)
+				random.Read(block)
+				if err := c.SetKey(key); err != nil {
+					t.Fatalf("SetKey: %v", err)
+				}
+				ciphertext, err := c.Encrypt(block)
+				if err != nil {
+					t.Fatalf("Encrypt: %v", err)
+				}
+				plaintext, err := c.Decrypt(ciphertext)
+				if err != nil {
+					t.Fatalf("Decrypt: %v", err)
+				}
+				if !bytes.Equal(plaintext, block) {
+					t.Fatalf("%s, %d rounds: %X decrypts to %X, want %X", definition.Name, rounds, ciphertext, plaintext, block)
+				}
+			}
+		}
+	}
+}
+
+func TestAnalysisTools(t *testing.T) {
+	c := builtinCipher(t, "heys")
+	sbox, err := analysis.NewSBox(c.SBox())
+	if err != nil {
+		t.Fatalf("analysis.NewSBox: %v", err)
+	}
+	// The differential Heys' tutorial builds its attack on.
+	if got := sbox.DDT()[0xB][0x2]; got != 8 {
+		t.Errorf("DDT[B][2] = %d, want 8", got)
+	}
+
+	present, _ := Builtin("present80")
+	results, err := analysis.AnalyzeRounds(func(rounds int) (interfaces.BlockCipher, error) {
+		return NewReducedCipher(present, rounds)
+	}, present.KeySize(), []int{1, 5}, analysis.Plaintext, analysis.Config{Samples: 8, Rand: rand.NewChaCha8([32]byte{51})})
+	if err != nil {
+		t.Fatalf("AnalyzeRounds: %v", err)
+	}
+	// One round confines a flipped bit to one S-box, so at most 4 bits change.
+	if got := results[0].Avalanche(); got > 4.0/64 {
+		t.Errorf("1 round: avalanche %v", got)
+	}
+	if got := results[1].Avalanche(); math.Abs(got-0.5) > 0.05 {
+		t.Errorf("5 rounds: avalanche %v, want about 0.5", got)
+	}
+}
+
+func TestLoadDefinition(t *testing.T) {
+	definition, err := LoadDefinition(filepath.Join("testdata", "heys.json"))
+	if err != nil {
+		t.Fatalf("LoadDefinition: %v", err)
+	}
+	if definition.Name != "Heys tutorial SPN" || definition.Rounds != 4 || len(definition.Permutation.Table) != 16 {
+		t.Errorf("loaded %+v", definition)
+	}
+
+	if _, err := LoadDefinition(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadDefinition read a missing file")
+	}
+	if names := strings.Join(BuiltinNames(), ","); names != "heys,present80" {
+		t.Errorf("BuiltinNames = %s", names)
+	}
+}
+
+func TestLoadYAMLDefinition(t *testing.T) {
+	data, err := builtinDefinitions.ReadFile("definitions/heys.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "heys.yml")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := LoadDefinition(path)
+	if err != nil {
+		t.Fatalf("LoadDefinition: %v", err)
+	}
+	fromJSON, err := LoadDefinition(filepath.Join("testdata", "heys.json"))
+	if err != nil {
+		t.Fatalf("LoadDefinition: %v", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("YAML definition\n%+v\nJSON definition\n%+v", fromYAML, fromJSON)
+	}
+
+	yamlCipher, err := NewCipher(fromYAML)
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	jsonCipher, err := NewCipher(fromJSON)
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+
+	random := rand.NewChaCha8([32]byte{50})
+	key := make([]byte, jsonCipher.KeySize())
+	random.Read(key)
+	if err := yamlCipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	jsonCipher.SetKey(key)
+
+	for range 8 {
+		block := make([]byte, jsonCipher.BlockSize())
+		random.Read(block)
+		got, err := yamlCipher.Encrypt(block)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if want, _ := jsonCipher.Encrypt(block); !bytes.Equal(got, want) {
+			t.Errorf("%X: YAML definition gives %X, JSON %X", block, got, want)
+		}
+	}
+
+	if _, err := ParseYAMLDefinition([]byte("name: x\nrounds: 4\nsboxes: {}\n")); err == nil {
+		t.Error("ParseYAMLDefinition accepted an unknown field")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	valid := func() *Definition {
+		definition, _ := Builtin("heys")
+		return definition
+	}
+
+	for name, change := range map[string]func(*Definition){
+		"block bits":      func(d *Definition) { d.BlockBits = 12 },
+		"rounds":          func(d *Definition) { d.Rounds = 0 },
+		"S-box width":     func(d *Definition) { d.SBox.Bits = 3 },
+		"no S-box":        func(d *Definition) { d.SBox = SBoxDefinition{} },
+		"S-box table":     func(d *Definition) { d.SBox.Table = d.SBox.Table[:8] },
+		"S-box bijection": func(d *Definition) { d.SBox.Table[0] = d.SBox.Table[1] },
+		"bit order":       func(d *Definition) { d.Permutation.BitOrder = "middle" },
+		"permutation":     func(d *Definition) { d.Permutation.Table[0] = 4 },
+		"linear width":    func(d *Definition) { d.LinearLayer = &LinearDefinition{Width: 3, Rows: []string{"100", "010", "001"}} },
+		"linear row":      func(d *Definition) { d.LinearLayer = &LinearDefinition{Width: 2, Rows: []string{"10", "0x"}} },
+		"schedule type":   func(d *Definition) { d.KeySchedule.Type = "magic" },
+		"register size": func(d *Definition) {
+			d.KeySchedule = KeyScheduleDefinition{Type: RegisterKeys, KeyBits: 8}
+		},
+		"counter": func(d *Definition) {
+			d.KeySchedule = KeyScheduleDefinition{Type: RegisterKeys, KeyBits: 16, CounterBit: 14}
+		},
+	} {
+		definition := valid()
+		change(definition)
+		if _, err := NewCipher(definition); err == nil {
+			t.Errorf("%s: NewCipher succeeded", name)
+		}
+	}
+
+	if _, err := ParseDefinition([]byte(`{"name": "x", "rounds": 1, "sboxes": []}`)); err == nil {
+		t.Error("ParseDefinition accepted an unknown field")
+	}
+	if _, err := Builtin("aes"); err == nil {
+		t.Error("Builtin found aes")
+	}
+	if _, err := NewReducedCipher(valid(), 5); err == nil {
+		t.Error("NewReducedCipher accepted more rounds than the definition")
+	}
+
+	c := builtinCipher(t, "heys")
+	if _, err := c.Encrypt(make([]byte, 2)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Encrypt without key: %v", err)
+	}
+	if err := c.SetKey(make([]byte, 8)); !errors.Is(err, interfaces.ErrInvalidKeySize) {
+		t.Errorf("SetKey with 8 bytes: %v", err)
+	}
+	c.SetKey(make([]byte, 10))
+	if _, err := c.Decrypt(make([]byte, 3)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Decrypt of 3 bytes: %v", err)
+	}
+	c.Destroy()
+	if _, err := c.Encrypt(make([]byte, 2)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Encrypt after Destroy: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package tripledes
+
+import "testing"
+
+func benchmarkTripleDES(b *testing.B, mode TripleDESMode, decrypt bool) {
+	cipher, err := NewTripleDES(mode)
+	if err != nil {
+		b.Fatalf("NewTripleDES: %v", err)
+	}
+	if err := cipher.SetKey([]byte("0123456789ABCDEF01234567")); err != nil {
+		b.Fatalf("SetKey: %v", err)
+	}
+
+	block := []byte("8 bytes!")
+	operation := cipher.Encrypt
+	if decrypt {
+		operation = cipher.Decrypt
+	}
+
+	b.SetBytes(int64(cipher.BlockSize()))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := operation(block); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTripleDESEncryptEDE(b *testing.B) { benchmarkTripleDES(b, EDE, false) }
+func BenchmarkTripleDESDecryptEDE(b *testing.B) { benchmarkTripleDES(b, EDE, true) }
+func BenchmarkTripleDESEncryptEEE(b *testing.B) { benchmarkTripleDES(b, EEE, false) }
+func BenchmarkTripleDESDecryptEEE(b *testing.B) { benchmarkTripleDES(b, EEE, true) }
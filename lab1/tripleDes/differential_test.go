@@ -0,0 +1,67 @@
+package tripledes
+
+import (
+	"crypto/des"
+	"lab1/internal/differential"
+	"testing"
+)
+
+func expandTripleDESKey(key []byte) []byte {
+	switch len(key) {
+	case 8:
+		return append(append(append([]byte{}, key...), key...), key...)
+	case 16:
+		return append(append([]byte{}, key...), key[:8]...)
+	default:
+		return key
+	}
+}
+
+func FuzzTripleDESBlockDifferential(f *testing.F) {
+	f.Add([]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, []byte("The qufc"))
+	f.Add([]byte("0123456789ABCDEF"), make([]byte, 8))
+	f.Add([]byte("0123456789ABCDEF01234567"), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+
+	cipher, err := NewTripleDES(EDE)
+	if err != nil {
+		f.Fatalf("NewTripleDES: %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, key, block []byte) {
+		key, block = differential.Fit(key, 8, 16, 24), differential.Fit(block, 8)
+
+		reference, err := des.NewTripleDESCipher(expandTripleDESKey(key))
+		if err != nil {
+			t.Fatalf("crypto/des: %v", err)
+		}
+
+		if err := differential.CompareBlock(cipher, reference, key, block); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzTripleDESModesDifferential(f *testing.F) {
+	f.Add([]byte("0123456789ABCDEF01234567"), []byte("initvect"), []byte("The quick brown fox jumps over the lazy dog"))
+	f.Add([]byte("0123456789ABCDEF"), make([]byte, 8), []byte{})
+	f.Add([]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, []byte("12345678"))
+
+	cipher, err := NewTripleDES(EDE)
+	if err != nil {
+		f.Fatalf("NewTripleDES: %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, key, iv, plaintext []byte) {
+		key, iv = differential.Fit(key, 8, 16, 24), differential.Fit(iv, 8)
+		plaintext = plaintext[:min(len(plaintext), 128)]
+
+		reference, err := des.NewTripleDESCipher(expandTripleDESKey(key))
+		if err != nil {
+			t.Fatalf("crypto/des: %v", err)
+		}
+
+		if err := differential.CompareModes(cipher, reference, key, iv, plaintext); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package tripledes
+
+import (
+	"fmt"
+	"lab1/des"
+	"lab1/interfaces"
+)
+
+// DoubleDES encrypts with K1 and then with K2. It needs only about 2^57
+// operations to break by meet-in-the-middle, which is why triple
+// encryption is used instead.
+type DoubleDES struct {
+	des1 *des.DES
+	des2 *des.DES
+}
+
+func NewDoubleDES() (*DoubleDES, error) {
+	des1, err := des.NewDES()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DES1: %w", err)
+	}
+
+	des2, err := des.NewDES()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DES2: %w", err)
+	}
+
+	return &DoubleDES{des1: des1, des2: des2}, nil
+}
+
+func (d *DoubleDES) SetKey(key []byte) error {
+	if len(key) != 16 {
+		return &interfaces.KeySizeError{Cipher: "DoubleDES", Size: len(key), Valid: []int{16}}
+	}
+
+	if err := d.des1.SetKey(key[:8]); err != nil {
+		return fmt.Errorf("failed to set key1: %w", err)
+	}
+
+	if err := d.des2.SetKey(key[8:]); err != nil {
+		return fmt.Errorf("failed to set key2: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DoubleDES) Destroy() {
+	d.des1.Destroy()
+	d.des2.Destroy()
+}
+
+func (d *DoubleDES) Encrypt(block []byte) ([]byte, error) {
+	if len(block) != 8 {
+		return nil, &interfaces.BlockSizeError{Cipher: "DoubleDES", Size: len(block), Valid: []int{8}}
+	}
+
+	result, err := d.des1.Encrypt(block)
+	if err != nil {
+		return nil, fmt.Errorf("DES1 encryption failed: %w", err)
+	}
+
+	result, err = d.des2.Encrypt(result)
+	if err != nil {
+		return nil, fmt.Errorf("DES2 encryption failed: %w", err)
+	}
+
+	return result, nil
+}
+
+func (d *DoubleDES) Decrypt(block []byte) ([]byte, error) {
+	if len(block) != 8 {
+		return nil, &interfaces.BlockSizeError{Cipher: "DoubleDES", Size: len(block), Valid: []int{8}}
+	}
+
+	result, err := d.des2.Decrypt(block)
+	if err != nil {
+		return nil, fmt.Errorf("DES2 decryption failed: %w", err)
+	}
+
+	result, err = d.des1.Decrypt(result)
+	if err != nil {
+		return nil, fmt.Errorf("DES1 decryption failed: %w", err)
+	}
+
+	return result, nil
+}
+
+func (d *DoubleDES) BlockSize() int {
+	return 8
+}
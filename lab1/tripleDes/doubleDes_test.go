@@ -0,0 +1,53 @@
+package tripledes
+
+import (
+	"bytes"
+	"crypto/des"
+	"errors"
+	"lab1/interfaces"
+	"testing"
+)
+
+func TestDoubleDES(t *testing.T) {
+	cipher, err := NewDoubleDES()
+	if err != nil {
+		t.Fatalf("NewDoubleDES: %v", err)
+	}
+
+	key := decodeHex(t, "0123456789ABCDEF23456789ABCDEF01")
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	first, _ := des.NewCipher(key[:8])
+	second, _ := des.NewCipher(key[8:])
+
+	plaintext := []byte("The qufc")
+	expected := make([]byte, 8)
+	first.Encrypt(expected, plaintext)
+	second.Encrypt(expected, expected)
+
+	encrypted, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !bytes.Equal(encrypted, expected) {
+		t.Errorf("Encrypt: got %X, want %X", encrypted, expected)
+	}
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt: got %X, want %X", decrypted, plaintext)
+	}
+
+	var keyErr *interfaces.KeySizeError
+	if err := cipher.SetKey(make([]byte, 24)); !errors.As(err, &keyErr) {
+		t.Errorf("24-byte key: got %v, want KeySizeError", err)
+	}
+	if _, err := cipher.Encrypt(make([]byte, 16)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Encrypt 16-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+}
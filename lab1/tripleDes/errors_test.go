@@ -0,0 +1,126 @@
+package tripledes
+
+import (
+	"bytes"
+	"errors"
+	"lab1/des"
+	"lab1/interfaces"
+	"testing"
+)
+
+func TestTripleDESErrors(t *testing.T) {
+	for _, mode := range []TripleDESMode{EDE, EEE} {
+		cipher, err := NewTripleDES(mode)
+		if err != nil {
+			t.Fatalf("NewTripleDES(%d): %v", mode, err)
+		}
+
+		if _, err := cipher.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+			t.Errorf("mode %d: Encrypt without key: got %v, want ErrKeyNotSet", mode, err)
+		}
+		if _, err := cipher.Decrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+			t.Errorf("mode %d: Decrypt without key: got %v, want ErrKeyNotSet", mode, err)
+		}
+
+		for _, size := range []int{0, 7, 12, 32} {
+			var keyErr *interfaces.KeySizeError
+			if err := cipher.SetKey(make([]byte, size)); !errors.As(err, &keyErr) || keyErr.Size != size {
+				t.Errorf("mode %d: %d-byte key: got %v, want KeySizeError", mode, size, err)
+			}
+		}
+
+		if err := cipher.SetKey(make([]byte, 24)); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		if _, err := cipher.Encrypt(make([]byte, 16)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+			t.Errorf("mode %d: Encrypt 16-byte block: got %v, want ErrInvalidBlockSize", mode, err)
+		}
+		if _, err := cipher.Decrypt(nil); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+			t.Errorf("mode %d: Decrypt empty block: got %v, want ErrInvalidBlockSize", mode, err)
+		}
+	}
+}
+
+func TestTripleDESDestroy(t *testing.T) {
+	key := []byte("0123456789abcdefFEDCBA98")
+	original := append([]byte(nil), key...)
+
+	cipher, err := NewTripleDES(EDE)
+	if err != nil {
+		t.Fatalf("NewTripleDES: %v", err)
+	}
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	if string(key) != string(original) {
+		t.Errorf("SetKey modified the caller's key: %x", key)
+	}
+
+	cipher.Destroy()
+
+	if _, err := cipher.Encrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Encrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, 8)); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("Decrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if err := cipher.SetKey(key); !errors.Is(err, interfaces.ErrDestroyed) {
+		t.Errorf("SetKey after Destroy: got %v, want ErrDestroyed", err)
+	}
+}
+
+func TestTripleDESKeyPolicy(t *testing.T) {
+	cipher, err := NewTripleDES(EDE)
+	if err != nil {
+		t.Fatalf("NewTripleDES: %v", err)
+	}
+
+	k1 := decodeHex(t, "0123456789ABCDEF")
+	k2 := decodeHex(t, "23456789ABCDEF01")
+	k3 := decodeHex(t, "456789ABCDEF0123")
+	// k2 with its parity bits flipped is the same DES key.
+	k2parity := decodeHex(t, "22446688AACCEE00")
+
+	join := func(keys ...[]byte) []byte { return bytes.Join(keys, nil) }
+
+	// Without a policy, degenerate keys are accepted for compatibility.
+	if err := cipher.SetKey(k1); err != nil {
+		t.Errorf("default policy rejected an 8-byte key: %v", err)
+	}
+
+	cipher.SetKeyPolicy(des.StrictKeyPolicy)
+	for name, key := range map[string][]byte{
+		"8-byte":   k1,
+		"K1 == K2": join(k1, k1, k3),
+		"K2 == K3": join(k1, k2, k2parity),
+	} {
+		if err := cipher.SetKey(key); !errors.Is(err, des.ErrWeakKey) {
+			t.Errorf("strict, %s key: got %v, want ErrWeakKey", name, err)
+		}
+	}
+
+	if err := cipher.SetKey(join(k1, decodeHex(t, "FEFEFEFEFEFEFEFE"), k3)); !errors.Is(err, des.ErrWeakKey) {
+		t.Errorf("strict, weak K2: got %v, want ErrWeakKey", err)
+	}
+	if err := cipher.SetKey(join(k1, k2, decodeHex(t, "446688AACCEE0022"))); !errors.Is(err, des.ErrKeyParity) {
+		t.Errorf("strict, bad parity: got %v, want ErrKeyParity", err)
+	}
+	if err := cipher.SetKey(join(k1, k2, k3)); err != nil {
+		t.Errorf("strict, three-key: %v", err)
+	}
+	if err := cipher.SetKey(join(k1, k2)); err != nil {
+		t.Errorf("strict, two-key: %v", err)
+	}
+
+	// A rejected K2 or K3 must not leave K1 replaced.
+	block := []byte("tripdes!")
+	want, _ := cipher.Encrypt(block)
+	for _, key := range [][]byte{join(k3, decodeHex(t, "FEFEFEFEFEFEFEFE"), k1), join(k3, k1, decodeHex(t, "01FE01FE01FE01FE"))} {
+		if err := cipher.SetKey(key); !errors.Is(err, des.ErrWeakKey) {
+			t.Errorf("strict, weak %X: got %v, want ErrWeakKey", key, err)
+		}
+		if got, err := cipher.Encrypt(block); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("after rejecting %X: encrypted %X, %v; want the previous key's %X", key, got, err, want)
+		}
+	}
+}
@@ -1,9 +1,9 @@
 package tripledes
 
 import (
-	"errors"
 	"fmt"
 	"lab1/des"
+	"lab1/interfaces"
 )
 
 type TripleDESMode int
@@ -14,13 +14,11 @@ const (
 )
 
 type TripleDES struct {
-	des1 *des.DES
-	des2 *des.DES
-	des3 *des.DES
-	mode TripleDESMode
-	key1 []byte
-	key2 []byte
-	key3 []byte
+	des1   *des.DES
+	des2   *des.DES
+	des3   *des.DES
+	mode   TripleDESMode
+	policy des.KeyPolicy
 }
 
 func NewTripleDES(mode TripleDESMode) (*TripleDES, error) {
@@ -48,53 +46,75 @@ func NewTripleDES(mode TripleDESMode) (*TripleDES, error) {
 }
 
 func (t *TripleDES) SetKey(key []byte) error {
+	var key1, key2, key3 []byte
+
 	switch len(key) {
 	case 8:
-		t.key1 = make([]byte, 8)
-		t.key2 = make([]byte, 8)
-		t.key3 = make([]byte, 8)
-		copy(t.key1, key)
-		copy(t.key2, key)
-		copy(t.key3, key)
+		key1, key2, key3 = key, key, key
 
 	case 16:
-		t.key1 = make([]byte, 8)
-		t.key2 = make([]byte, 8)
-		t.key3 = make([]byte, 8)
-		copy(t.key1, key[:8])
-		copy(t.key2, key[8:16])
-		copy(t.key3, key[:8])
+		key1, key2, key3 = key[:8], key[8:16], key[:8]
 
 	case 24:
-		t.key1 = make([]byte, 8)
-		t.key2 = make([]byte, 8)
-		t.key3 = make([]byte, 8)
-		copy(t.key1, key[:8])
-		copy(t.key2, key[8:16])
-		copy(t.key3, key[16:24])
+		key1, key2, key3 = key[:8], key[8:16], key[16:24]
 
 	default:
-		return fmt.Errorf("invalid key length: %d (must be 8, 16, or 24 bytes)", len(key))
+		return &interfaces.KeySizeError{Cipher: "TripleDES", Size: len(key), Valid: []int{8, 16, 24}}
+	}
+
+	if t.policy.RejectDegenerate && (sameDESKey(key1, key2) || sameDESKey(key2, key3)) {
+		return fmt.Errorf("degenerate TripleDES key (K1 == K2 or K2 == K3): %w", des.ErrWeakKey)
+	}
+	// Check all three keys first, so that a rejected key leaves the
+	// previous one in place.
+	for i, subkey := range [][]byte{key1, key2, key3} {
+		if err := t.policy.Check(subkey); err != nil {
+			return fmt.Errorf("failed to set key%d: %w", i+1, err)
+		}
 	}
 
-	if err := t.des1.SetKey(t.key1); err != nil {
+	if err := t.des1.SetKey(key1); err != nil {
 		return fmt.Errorf("failed to set key1: %w", err)
 	}
 
-	if err := t.des2.SetKey(t.key2); err != nil {
+	if err := t.des2.SetKey(key2); err != nil {
 		return fmt.Errorf("failed to set key2: %w", err)
 	}
 
-	if err := t.des3.SetKey(t.key3); err != nil {
+	if err := t.des3.SetKey(key3); err != nil {
 		return fmt.Errorf("failed to set key3: %w", err)
 	}
 
 	return nil
 }
 
+// SetKeyPolicy applies the policy to each of the three DES keys and, with
+// RejectDegenerate, to their combination.
+func (t *TripleDES) SetKeyPolicy(policy des.KeyPolicy) {
+	t.policy = policy
+	t.des1.SetKeyPolicy(policy)
+	t.des2.SetKeyPolicy(policy)
+	t.des3.SetKeyPolicy(policy)
+}
+
+// sameDESKey compares two DES keys ignoring the parity bits, in constant time.
+func sameDESKey(a, b []byte) bool {
+	var diff byte
+	for i := range a {
+		diff |= (a[i] ^ b[i]) &^ 1
+	}
+	return diff == 0
+}
+
+func (t *TripleDES) Destroy() {
+	t.des1.Destroy()
+	t.des2.Destroy()
+	t.des3.Destroy()
+}
+
 func (t *TripleDES) Encrypt(block []byte) ([]byte, error) {
 	if len(block) != 8 {
-		return nil, errors.New("block size must be 8 bytes")
+		return nil, &interfaces.BlockSizeError{Cipher: "TripleDES", Size: len(block), Valid: []int{8}}
 	}
 
 	var result []byte
@@ -142,7 +162,7 @@ func (t *TripleDES) Encrypt(block []byte) ([]byte, error) {
 
 func (t *TripleDES) Decrypt(block []byte) ([]byte, error) {
 	if len(block) != 8 {
-		return nil, errors.New("block size must be 8 bytes")
+		return nil, &interfaces.BlockSizeError{Cipher: "TripleDES", Size: len(block), Valid: []int{8}}
 	}
 
 	var result []byte
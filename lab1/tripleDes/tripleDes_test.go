@@ -0,0 +1,104 @@
+package tripledes
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+type tdeaVector struct {
+	key        string
+	plaintext  string
+	ciphertext string
+}
+
+// SP 800-20 known-answer tests run with keying option 3 (K1 = K2 = K3),
+// so the expected values coincide with the single-DES NBS tables.
+var tdeaKnownAnswerVectors = []tdeaVector{
+	{"0101010101010101", "8000000000000000", "95F8A5E5DD31D900"},
+	{"0101010101010101", "4000000000000000", "DD7F121CA5015619"},
+	{"0101010101010101", "2000000000000000", "2E8653104F3834EA"},
+	{"0101010101010101", "1000000000000000", "4BD388FF6CD81D4F"},
+	{"0101010101010101", "0800000000000000", "20B9E767B2FB1456"},
+	{"0101010101010101", "0400000000000000", "55579380D77138EF"},
+	{"0101010101010101", "0200000000000000", "6CC5DEFAAF04512F"},
+	{"0101010101010101", "0100000000000000", "0D9F279BA5D87260"},
+	{"8001010101010101", "0000000000000000", "95A8D72813DAA94D"},
+	{"4001010101010101", "0000000000000000", "0EEC1487DD8C26D5"},
+	{"2001010101010101", "0000000000000000", "7AD16FFB79C45926"},
+	{"1001010101010101", "0000000000000000", "D3746294CA6A6CF3"},
+	{"0801010101010101", "0000000000000000", "809F5F873C1FD761"},
+	{"0401010101010101", "0000000000000000", "C02FAFFEC989D1FC"},
+	{"0201010101010101", "0000000000000000", "4615AA1D33E72F10"},
+	{"0180010101010101", "0000000000000000", "2055123350C00858"},
+	{"1046913489980131", "0000000000000000", "88D55E54F54C97B4"},
+	{"1007103489988020", "0000000000000000", "0C0CC00C83EA48FD"},
+	{"7CA110454A1A6E57", "01A1D6D039776742", "690F5B0D9A26939B"},
+	{"0131D9619DC1376E", "5CD54CA83DEF57DA", "7A389D10354BD271"},
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+func checkTripleDES(t *testing.T, cipher *TripleDES, key, plaintext, expected []byte) {
+	t.Helper()
+
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey(%X): %v", key, err)
+	}
+
+	encrypted, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !bytes.Equal(encrypted, expected) {
+		t.Errorf("key %X plaintext %X: got %X, want %X", key, plaintext, encrypted, expected)
+	}
+
+	decrypted, err := cipher.Decrypt(expected)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("key %X ciphertext %X: got %X, want %X", key, expected, decrypted, plaintext)
+	}
+}
+
+func TestTripleDESKnownAnswer(t *testing.T) {
+	cipher, err := NewTripleDES(EDE)
+	if err != nil {
+		t.Fatalf("NewTripleDES: %v", err)
+	}
+
+	for _, v := range tdeaKnownAnswerVectors {
+		key := decodeHex(t, v.key)
+		plaintext := decodeHex(t, v.plaintext)
+		expected := decodeHex(t, v.ciphertext)
+
+		checkTripleDES(t, cipher, key, plaintext, expected)
+		checkTripleDES(t, cipher, bytes.Repeat(key, 2), plaintext, expected)
+		checkTripleDES(t, cipher, bytes.Repeat(key, 3), plaintext, expected)
+	}
+}
+
+func TestTripleDESThreeKey(t *testing.T) {
+	cipher, err := NewTripleDES(EDE)
+	if err != nil {
+		t.Fatalf("NewTripleDES: %v", err)
+	}
+
+	// SP 800-67 Rev. 1, Appendix B example.
+	key := decodeHex(t, "0123456789ABCDEF23456789ABCDEF01456789ABCDEF0123")
+	plaintext := []byte("The qufck brown fox jump")
+	expected := decodeHex(t, "A826FD8CE53B855FCCE21C8112256FE668D5C05DD9B6B900")
+
+	for i := 0; i < len(plaintext); i += 8 {
+		checkTripleDES(t, cipher, key, plaintext[i:i+8], expected[i:i+8])
+	}
+}
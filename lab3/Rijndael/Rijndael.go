@@ -12,10 +12,10 @@ const (
 	BlockSize256 = 32
 )
 
-var rconTable = [15]byte{
-	0x01, 0x02, 0x04, 0x08, 0x10, 0x20, 0x40, 0x80, 0x1B, 0x36,
-	0x6C, 0xD8, 0xAB, 0x4D,
-}
+var (
+	validBlockSizes = []int{BlockSize128, BlockSize192, BlockSize256}
+	validKeySizes   = []int{16, 24, 32}
+)
 
 func computeSBoxes(gf28Service *statelessService.GF28Service, modulus byte) ([]byte, []byte) {
 	sbox := make([]byte, 256)
@@ -93,11 +93,11 @@ type RijndaelKeyExpander struct {
 
 func NewRijndaelKeyExpander(blockSize, keySize int, modulus byte, sbox []byte) (*RijndaelKeyExpander, error) {
 	if blockSize != BlockSize128 && blockSize != BlockSize192 && blockSize != BlockSize256 {
-		return nil, fmt.Errorf("NewRijndaelKeyExpander: invalid block size")
+		return nil, fmt.Errorf("NewRijndaelKeyExpander: %w", &interfaces.BlockSizeError{Cipher: "Rijndael", Size: blockSize, Valid: validBlockSizes})
 	}
 
 	if keySize != 16 && keySize != 24 && keySize != 32 {
-		return nil, fmt.Errorf("NewRijndaelKeyExpander: invalid key size")
+		return nil, fmt.Errorf("NewRijndaelKeyExpander: %w", &interfaces.KeySizeError{Cipher: "Rijndael", Size: keySize, Valid: validKeySizes})
 	}
 
 	gf28Service := statelessService.NewGF28Service()
@@ -127,32 +127,35 @@ func NewRijndaelKeyExpander(blockSize, keySize int, modulus byte, sbox []byte) (
 
 func (rke *RijndaelKeyExpander) ExpandKey(key []byte) ([][]byte, error) {
 	if len(key) != rke.keySize {
-		return nil, fmt.Errorf("ExpandKey: invalid key size")
+		return nil, fmt.Errorf("ExpandKey: %w", &interfaces.KeySizeError{Cipher: "Rijndael", Size: len(key), Valid: []int{rke.keySize}})
 	}
 
 	nk := rke.keySize / 4
 	nb := rke.blockSize / 4
 	totalWords := nb * (rke.numRounds + 1)
 
-	w := make([][]byte, totalWords)
-
-	for i := 0; i < nk; i++ {
-		w[i] = make([]byte, 4)
-		copy(w[i], key[i*4:(i+1)*4])
+	rcon, err := rke.computeRcon((totalWords + nk - 1) / nk)
+	if err != nil {
+		return nil, fmt.Errorf("ExpandKey: %w", err)
 	}
 
+	w := make([]byte, totalWords*4)
+	copy(w, key)
+
+	var temp [4]byte
+	defer interfaces.Zeroize(temp[:])
+
 	for i := nk; i < totalWords; i++ {
-		temp := make([]byte, 4)
-		copy(temp, w[i-1])
+		copy(temp[:], w[(i-1)*4:i*4])
 
 		if i%nk == 0 {
-			temp = []byte{temp[1], temp[2], temp[3], temp[0]}
+			temp = [4]byte{temp[1], temp[2], temp[3], temp[0]}
 
 			for j := 0; j < 4; j++ {
 				temp[j] = rke.sbox[temp[j]]
 			}
 
-			temp[0] ^= rconTable[i/nk]
+			temp[0] ^= rcon[i/nk-1]
 
 		} else if nk > 6 && i%nk == 4 {
 			for j := 0; j < 4; j++ {
@@ -160,23 +163,34 @@ func (rke *RijndaelKeyExpander) ExpandKey(key []byte) ([][]byte, error) {
 			}
 		}
 
-		w[i] = make([]byte, 4)
 		for j := 0; j < 4; j++ {
-			w[i][j] = w[i-nk][j] ^ temp[j]
+			w[i*4+j] = w[(i-nk)*4+j] ^ temp[j]
 		}
 	}
 
 	roundKeys := make([][]byte, rke.numRounds+1)
-	for round := 0; round <= rke.numRounds; round++ {
-		roundKeys[round] = make([]byte, rke.blockSize)
-		for i := 0; i < nb; i++ {
-			copy(roundKeys[round][i*4:(i+1)*4], w[round*nb+i])
-		}
+	for round := range roundKeys {
+		roundKeys[round] = w[round*rke.blockSize : (round+1)*rke.blockSize : (round+1)*rke.blockSize]
 	}
 
 	return roundKeys, nil
 }
 
+func (rke *RijndaelKeyExpander) computeRcon(count int) ([]byte, error) {
+	rcon := make([]byte, count)
+	value := byte(0x01)
+	for i := 0; i < count; i++ {
+		rcon[i] = value
+
+		var err error
+		value, err = rke.gf28Service.Multiply(value, 0x02, rke.modulus)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rcon, nil
+}
+
 type RijndaelRoundTransformer struct {
 	blockSize   int
 	modulus     byte
@@ -187,7 +201,7 @@ type RijndaelRoundTransformer struct {
 
 func NewRijndaelRoundTransformer(blockSize int, modulus byte, sbox, invSbox []byte) (*RijndaelRoundTransformer, error) {
 	if blockSize != BlockSize128 && blockSize != BlockSize192 && blockSize != BlockSize256 {
-		return nil, fmt.Errorf("NewRijndaelRoundTransformer: invalid block size")
+		return nil, fmt.Errorf("NewRijndaelRoundTransformer: %w", &interfaces.BlockSizeError{Cipher: "Rijndael", Size: blockSize, Valid: validBlockSizes})
 	}
 
 	gf28Service := statelessService.NewGF28Service()
@@ -211,7 +225,7 @@ func NewRijndaelRoundTransformer(blockSize int, modulus byte, sbox, invSbox []by
 
 func (rrt *RijndaelRoundTransformer) Transform(inputBlock []byte, roundKey []byte) ([]byte, error) {
 	if len(inputBlock) != rrt.blockSize {
-		return nil, fmt.Errorf("Transform: invalid block size")
+		return nil, fmt.Errorf("Transform: %w", &interfaces.BlockSizeError{Cipher: "Rijndael", Size: len(inputBlock), Valid: []int{rrt.blockSize}})
 	}
 
 	nb := rrt.blockSize / 4
@@ -291,15 +305,16 @@ type RijndaelCipher struct {
 	roundKeys           [][]byte
 	numRounds           int
 	concreteTransformer *RijndaelRoundTransformer
+	destroyed           bool
 }
 
 func NewRijndaelCipher(blockSize, keySize int, modulus byte) (*RijndaelCipher, error) {
 	if blockSize != BlockSize128 && blockSize != BlockSize192 && blockSize != BlockSize256 {
-		return nil, fmt.Errorf("NewRijndaelCipher: invalid block size")
+		return nil, fmt.Errorf("NewRijndaelCipher: %w", &interfaces.BlockSizeError{Cipher: "Rijndael", Size: blockSize, Valid: validBlockSizes})
 	}
 
 	if keySize != 16 && keySize != 24 && keySize != 32 {
-		return nil, fmt.Errorf("NewRijndaelCipher: invalid key size")
+		return nil, fmt.Errorf("NewRijndaelCipher: %w", &interfaces.KeySizeError{Cipher: "Rijndael", Size: keySize, Valid: validKeySizes})
 	}
 
 	gf28Service := statelessService.NewGF28Service()
@@ -330,7 +345,29 @@ func NewRijndaelCipher(blockSize, keySize int, modulus byte) (*RijndaelCipher, e
 	}, nil
 }
 
+func NewReducedRijndaelCipher(blockSize, keySize int, modulus byte, rounds int) (*RijndaelCipher, error) {
+	rc, err := NewRijndaelCipher(blockSize, keySize, modulus)
+	if err != nil {
+		return nil, err
+	}
+
+	if rounds < 1 || rounds > rc.numRounds {
+		return nil, fmt.Errorf("NewReducedRijndaelCipher: rounds must be between 1 and %d (got %d)", rc.numRounds, rounds)
+	}
+
+	rc.numRounds = rounds
+	return rc, nil
+}
+
+func (rc *RijndaelCipher) NumRounds() int {
+	return rc.numRounds
+}
+
 func (rc *RijndaelCipher) SetKey(key []byte) error {
+	if rc.destroyed {
+		return interfaces.ErrDestroyed
+	}
+
 	if rc.keyExpander == nil {
 		return fmt.Errorf("SetKey: keyExpander is nil")
 	}
@@ -340,21 +377,32 @@ func (rc *RijndaelCipher) SetKey(key []byte) error {
 		return err
 	}
 
+	interfaces.Zeroize(rc.roundKeys...)
 	rc.roundKeys = roundKeys
 	return nil
 }
 
+func (rc *RijndaelCipher) Destroy() {
+	interfaces.Zeroize(rc.roundKeys...)
+	rc.roundKeys = nil
+	rc.destroyed = true
+}
+
 func (rc *RijndaelCipher) BlockSize() int {
 	return rc.blockSize
 }
 
 func (rc *RijndaelCipher) Encrypt(block []byte) ([]byte, error) {
+	if rc.destroyed {
+		return nil, interfaces.ErrDestroyed
+	}
+
 	if len(block) != rc.blockSize {
-		return nil, fmt.Errorf("Encrypt: invalid block size")
+		return nil, fmt.Errorf("Encrypt: %w", &interfaces.BlockSizeError{Cipher: "Rijndael", Size: len(block), Valid: []int{rc.blockSize}})
 	}
 
 	if rc.roundKeys == nil {
-		return nil, fmt.Errorf("Encrypt: key not set")
+		return nil, fmt.Errorf("round keys not set: %w", interfaces.ErrKeyNotSet)
 	}
 
 	nb := rc.blockSize / 4
@@ -382,12 +430,16 @@ func (rc *RijndaelCipher) Encrypt(block []byte) ([]byte, error) {
 }
 
 func (rc *RijndaelCipher) Decrypt(block []byte) ([]byte, error) {
+	if rc.destroyed {
+		return nil, interfaces.ErrDestroyed
+	}
+
 	if len(block) != rc.blockSize {
-		return nil, fmt.Errorf("Decrypt: invalid block size")
+		return nil, fmt.Errorf("Decrypt: %w", &interfaces.BlockSizeError{Cipher: "Rijndael", Size: len(block), Valid: []int{rc.blockSize}})
 	}
 
 	if rc.roundKeys == nil {
-		return nil, fmt.Errorf("Decrypt: key not set")
+		return nil, fmt.Errorf("round keys not set: %w", interfaces.ErrKeyNotSet)
 	}
 
 	nb := rc.blockSize / 4
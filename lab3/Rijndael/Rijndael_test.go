@@ -0,0 +1,175 @@
+package Rijndael
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"lab3/statelessService"
+)
+
+type rijndaelVector struct {
+	blockSize  int
+	keySize    int
+	key        string
+	plaintext  string
+	ciphertext string
+}
+
+var fips197Vectors = []rijndaelVector{
+	{16, 16, "2b7e151628aed2a6abf7158809cf4f3c", "3243f6a8885a308d313198a2e0370734", "3925841d02dc09fbdc118597196a0b32"},
+	{16, 16, "000102030405060708090a0b0c0d0e0f", "00112233445566778899aabbccddeeff", "69c4e0d86a7b0430d8cdb78070b4c55a"},
+	{16, 24, "000102030405060708090a0b0c0d0e0f1011121314151617", "00112233445566778899aabbccddeeff", "dda97ca4864cdfe06eaf70a0ec0d7191"},
+	{16, 32, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "00112233445566778899aabbccddeeff", "8ea2b7ca516745bfeafc49904b496089"},
+}
+
+// Rijndael reference values for every block/key length pair, using prefixes
+// of the same key and plaintext as in Gladman's test vector set.
+const (
+	referenceKey       = "2b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfe"
+	referencePlaintext = "3243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c8"
+)
+
+var referenceCiphertexts = []struct {
+	blockSize  int
+	keySize    int
+	ciphertext string
+}{
+	{16, 16, "3925841d02dc09fbdc118597196a0b32"},
+	{16, 24, "f9fb29aefc384a250340d833b87ebc00"},
+	{16, 32, "1a6e6c2c662e7da6501ffb62bc9e93f3"},
+	{24, 16, "b24d275489e82bb8f7375e0d5fcdb1f481757c538b65148a"},
+	{24, 24, "725ae43b5f3161de806a7c93e0bca93c967ec1ae1b71e1cf"},
+	{24, 32, "0ebacf199e3315c2e34b24fcc7c46ef4388aa475d66c194c"},
+	{32, 16, "7d15479076b69a46ffb3b3beae97ad8313f622f67fedb487de9f06b9ed9c8f19"},
+	{32, 24, "5d7101727bb25781bf6715b0e6955282b9610e23a43c2eb062699f0ebf5887b2"},
+	{32, 32, "a49406115dfb30a40418aafa4869b7c6a886ff31602a7dd19c889dc64f7e4e7a"},
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+func checkRijndael(t *testing.T, v rijndaelVector) {
+	t.Helper()
+
+	cipher, err := NewRijndaelCipher(v.blockSize, v.keySize, 0x1B)
+	if err != nil {
+		t.Fatalf("NewRijndaelCipher(%d, %d): %v", v.blockSize, v.keySize, err)
+	}
+
+	key := decodeHex(t, v.key)
+	plaintext := decodeHex(t, v.plaintext)
+	expected := decodeHex(t, v.ciphertext)
+
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	encrypted, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !bytes.Equal(encrypted, expected) {
+		t.Errorf("block %d key %d: got %x, want %s", v.blockSize*8, v.keySize*8, encrypted, v.ciphertext)
+	}
+
+	decrypted, err := cipher.Decrypt(expected)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("block %d key %d: decrypted %x, want %s", v.blockSize*8, v.keySize*8, decrypted, v.plaintext)
+	}
+}
+
+func TestRijndaelFIPS197(t *testing.T) {
+	for _, v := range fips197Vectors {
+		checkRijndael(t, v)
+	}
+}
+
+func TestRijndaelAllBlockAndKeySizes(t *testing.T) {
+	for _, c := range referenceCiphertexts {
+		checkRijndael(t, rijndaelVector{
+			blockSize:  c.blockSize,
+			keySize:    c.keySize,
+			key:        referenceKey[:c.keySize*2],
+			plaintext:  referencePlaintext[:c.blockSize*2],
+			ciphertext: c.ciphertext,
+		})
+	}
+}
+
+func TestReducedRijndael(t *testing.T) {
+	key := decodeHex(t, "000102030405060708090a0b0c0d0e0f")
+	plaintext := decodeHex(t, "00112233445566778899aabbccddeeff")
+
+	for _, rounds := range []int{1, 2, 5, 10} {
+		cipher, err := NewReducedRijndaelCipher(BlockSize128, 16, 0x1B, rounds)
+		if err != nil {
+			t.Fatalf("NewReducedRijndaelCipher(%d): %v", rounds, err)
+		}
+		if cipher.NumRounds() != rounds {
+			t.Errorf("NumRounds() = %d, want %d", cipher.NumRounds(), rounds)
+		}
+		if err := cipher.SetKey(key); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+
+		ciphertext, err := cipher.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("%d rounds: Encrypt: %v", rounds, err)
+		}
+		if rounds == 10 && hex.EncodeToString(ciphertext) != "69c4e0d86a7b0430d8cdb78070b4c55a" {
+			t.Errorf("10 rounds: got %x, want the FIPS-197 ciphertext", ciphertext)
+		}
+
+		decrypted, err := cipher.Decrypt(ciphertext)
+		if err != nil || !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("%d rounds: decrypted %x, %v", rounds, decrypted, err)
+		}
+	}
+
+	for _, rounds := range []int{0, 11} {
+		if _, err := NewReducedRijndaelCipher(BlockSize128, 16, 0x1B, rounds); err == nil {
+			t.Errorf("NewReducedRijndaelCipher(%d) succeeded", rounds)
+		}
+	}
+}
+
+func TestRijndaelRoundConstants(t *testing.T) {
+	// A 256-bit block with a 128-bit key needs 29 round constants, past the
+	// reduction by the modulus; recover them from the expanded key.
+	key := decodeHex(t, referenceKey[:32])
+	for _, modulus := range []byte{0x1B, 0x1D, 0x4D} {
+		sbox, _ := computeSBoxes(statelessService.NewGF28Service(), modulus)
+		expander, err := NewRijndaelKeyExpander(BlockSize256, 16, modulus, sbox)
+		if err != nil {
+			t.Fatalf("modulus 0x%02X: %v", modulus, err)
+		}
+		roundKeys, err := expander.ExpandKey(key)
+		if err != nil {
+			t.Fatalf("modulus 0x%02X: ExpandKey: %v", modulus, err)
+		}
+		w := bytes.Join(roundKeys, nil)
+
+		const nk = 4
+		want := byte(0x01)
+		for i := nk; i < len(w)/4; i += nk {
+			if got := w[4*i] ^ w[4*(i-nk)] ^ sbox[w[4*(i-1)+1]]; got != want {
+				t.Errorf("modulus 0x%02X: round constant %d = 0x%02X, want 0x%02X", modulus, i/nk, got, want)
+			}
+			if want&0x80 != 0 {
+				want = want<<1 ^ modulus
+			} else {
+				want <<= 1
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package Rijndael
+
+import (
+	"lab1/analysis"
+	"lab1/interfaces"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRijndaelAvalancheAcrossModuli(t *testing.T) {
+	for _, modulus := range []byte{0x1B, 0x1D, 0x4D} {
+		results, err := analysis.AnalyzeRounds(func(rounds int) (interfaces.BlockCipher, error) {
+			return NewReducedRijndaelCipher(BlockSize128, 16, modulus, rounds)
+		}, 16, []int{1, 10}, analysis.Plaintext, analysis.Config{
+			Samples: 4,
+			Rand:    rand.NewChaCha8([32]byte{modulus}),
+		})
+		if err != nil {
+			t.Fatalf("modulus 0x%02X: %v", modulus, err)
+		}
+
+		// Without MixColumns a flipped bit stays inside its byte.
+		if got := results[0].Avalanche(); got > 8.0/128 {
+			t.Errorf("modulus 0x%02X, 1 round: avalanche %v", modulus, got)
+		}
+		if got := results[1].Avalanche(); math.Abs(got-0.5) > 0.03 {
+			t.Errorf("modulus 0x%02X, 10 rounds: avalanche %v, want about 0.5", modulus, got)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package Rijndael
+
+import (
+	"context"
+	"fmt"
+	"lab1/interfaces"
+	"testing"
+)
+
+func BenchmarkRijndael(b *testing.B) {
+	for _, blockSize := range roundTripSizes {
+		for _, keySize := range roundTripSizes {
+			cipher, err := NewRijndaelCipher(blockSize, keySize, 0x1B)
+			if err != nil {
+				b.Fatalf("NewRijndaelCipher: %v", err)
+			}
+			if err := cipher.SetKey(make([]byte, keySize)); err != nil {
+				b.Fatalf("SetKey: %v", err)
+			}
+
+			block := make([]byte, blockSize)
+
+			b.Run(fmt.Sprintf("Encrypt/B%d-K%d", blockSize*8, keySize*8), func(b *testing.B) {
+				b.SetBytes(int64(blockSize))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := cipher.Encrypt(block); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+
+			b.Run(fmt.Sprintf("Decrypt/B%d-K%d", blockSize*8, keySize*8), func(b *testing.B) {
+				b.SetBytes(int64(blockSize))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := cipher.Decrypt(block); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkRijndaelKeySchedule(b *testing.B) {
+	cipher, err := NewRijndaelCipher(BlockSize128, 32, 0x1B)
+	if err != nil {
+		b.Fatalf("NewRijndaelCipher: %v", err)
+	}
+	key := make([]byte, 32)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := cipher.SetKey(key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRijndaelCipherContext(b *testing.B) {
+	cipher, err := NewRijndaelCipher(BlockSize128, 16, 0x1B)
+	if err != nil {
+		b.Fatalf("NewRijndaelCipher: %v", err)
+	}
+
+	ctx := context.Background()
+	plaintext := make([]byte, 4096)
+
+	for _, mode := range roundTripModes {
+		cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+			Key:     make([]byte, 16),
+			Mode:    mode,
+			Padding: interfaces.PKCS7,
+		})
+		if err != nil {
+			b.Fatalf("NewCipherContext: %v", err)
+		}
+
+		ciphertext, err := cc.EncryptBytes(ctx, plaintext)
+		if err != nil {
+			b.Fatalf("EncryptBytes: %v", err)
+		}
+
+		b.Run(fmt.Sprintf("%v/Encrypt", mode), func(b *testing.B) {
+			b.SetBytes(int64(len(plaintext)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := cc.EncryptBytes(ctx, plaintext); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("%v/Decrypt", mode), func(b *testing.B) {
+			b.SetBytes(int64(len(plaintext)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := cc.DecryptBytes(ctx, ciphertext); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
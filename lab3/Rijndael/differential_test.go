@@ -0,0 +1,135 @@
+package Rijndael
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"lab1/interfaces"
+	"testing"
+)
+
+// The harness in lab1/internal/differential is internal to lab1, so the
+// Rijndael fuzz tests compare with crypto/aes on their own.
+
+func newAESCiphers(f *testing.F) map[int]*RijndaelCipher {
+	ciphers := make(map[int]*RijndaelCipher)
+	for _, keySize := range []int{16, 24, 32} {
+		cipher, err := NewRijndaelCipher(BlockSize128, keySize, 0x1B)
+		if err != nil {
+			f.Fatalf("NewRijndaelCipher(%d): %v", keySize, err)
+		}
+		ciphers[keySize] = cipher
+	}
+	return ciphers
+}
+
+// fitAESKey zero-pads or truncates data to the smallest AES key size that
+// holds it, so that every fuzz input is a valid key.
+func fitAESKey(data []byte) []byte {
+	size := 32
+	for _, s := range []int{16, 24} {
+		if len(data) <= s {
+			size = s
+			break
+		}
+	}
+	return fitBlock(data, size)
+}
+
+func fitBlock(data []byte, size int) []byte {
+	fitted := make([]byte, size)
+	copy(fitted, data)
+	return fitted
+}
+
+func FuzzRijndaelBlockDifferential(f *testing.F) {
+	f.Add([]byte("0123456789abcdef"), []byte("plaintext block!"))
+	f.Add(make([]byte, 24), make([]byte, 16))
+	f.Add([]byte("0123456789abcdef0123456789abcdef"), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+
+	ciphers := newAESCiphers(f)
+
+	f.Fuzz(func(t *testing.T, key, block []byte) {
+		key, block = fitAESKey(key), fitBlock(block, BlockSize128)
+		reference, err := aes.NewCipher(key)
+		if err != nil {
+			t.Fatalf("crypto/aes: %v", err)
+		}
+
+		cipher := ciphers[len(key)]
+		if err := cipher.SetKey(key); err != nil {
+			t.Fatalf("SetKey: %v", err)
+		}
+		want := make([]byte, BlockSize128)
+		reference.Encrypt(want, block)
+		if got, err := cipher.Encrypt(block); err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("key %x: Encrypt(%x) = %x, %v; want %x", key, block, got, err, want)
+		}
+		reference.Decrypt(want, block)
+		if got, err := cipher.Decrypt(block); err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("key %x: Decrypt(%x) = %x, %v; want %x", key, block, got, err, want)
+		}
+	})
+}
+
+func FuzzRijndaelModesDifferential(f *testing.F) {
+	f.Add([]byte("0123456789abcdef"), []byte("fedcba9876543210"), []byte("The quick brown fox jumps over the lazy dog"))
+	f.Add(make([]byte, 24), make([]byte, 16), []byte{})
+	f.Add([]byte("0123456789abcdef0123456789abcdef"), make([]byte, 16), []byte("exactly sixteen!"))
+
+	ciphers := newAESCiphers(f)
+
+	f.Fuzz(func(t *testing.T, key, iv, plaintext []byte) {
+		key, iv = fitAESKey(key), fitBlock(iv, BlockSize128)
+		plaintext = plaintext[:min(len(plaintext), 256)]
+		reference, err := aes.NewCipher(key)
+		if err != nil {
+			t.Fatalf("crypto/aes: %v", err)
+		}
+
+		for _, mode := range []interfaces.CipherMode{interfaces.ECB, interfaces.CBC, interfaces.CFB, interfaces.OFB, interfaces.CTR} {
+			if err := compareMode(ciphers[len(key)], reference, mode, key, iv, plaintext); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+// compareMode encrypts plaintext with PKCS7 padding through a
+// CipherContext and through crypto/cipher, and decrypts the result back.
+func compareMode(ours interfaces.BlockCipher, reference cipher.Block, mode interfaces.CipherMode, key, iv, plaintext []byte) error {
+	cc, err := interfaces.NewCipherContext(ours, interfaces.CipherContextConfig{Key: key, Mode: mode, Padding: interfaces.PKCS7, IV: iv})
+	if err != nil {
+		return fmt.Errorf("%v: NewCipherContext: %w", mode, err)
+	}
+
+	padding := BlockSize128 - len(plaintext)%BlockSize128
+	want := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	switch mode {
+	case interfaces.ECB:
+		for i := 0; i < len(want); i += BlockSize128 {
+			reference.Encrypt(want[i:], want[i:])
+		}
+	case interfaces.CBC:
+		cipher.NewCBCEncrypter(reference, iv).CryptBlocks(want, want)
+	case interfaces.CFB:
+		cipher.NewCFBEncrypter(reference, iv).XORKeyStream(want, want)
+	case interfaces.OFB:
+		cipher.NewOFB(reference, iv).XORKeyStream(want, want)
+	case interfaces.CTR:
+		cipher.NewCTR(reference, iv).XORKeyStream(want, want)
+	}
+
+	ctx := context.Background()
+	got, err := cc.EncryptBytes(ctx, plaintext)
+	if err != nil || !bytes.Equal(got, want) {
+		return fmt.Errorf("%v key %x iv %x: encrypting %x gives %x, %v; want %x", mode, key, iv, plaintext, got, err, want)
+	}
+	decrypted, err := cc.DecryptBytes(ctx, want)
+	if err != nil || !bytes.Equal(decrypted, plaintext) {
+		return fmt.Errorf("%v key %x iv %x: decrypting %x gives %x, %v; want %x", mode, key, iv, want, decrypted, err, plaintext)
+	}
+	return nil
+}
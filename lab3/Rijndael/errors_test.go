@@ -0,0 +1,105 @@
+package Rijndael
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"lab1/interfaces"
+	"testing"
+)
+
+func TestRijndaelErrors(t *testing.T) {
+	var blockErr *interfaces.BlockSizeError
+	if _, err := NewRijndaelCipher(20, 16, 0x1B); !errors.As(err, &blockErr) || blockErr.Size != 20 {
+		t.Errorf("20-byte block: got %v, want BlockSizeError with size 20", err)
+	}
+
+	var keyErr *interfaces.KeySizeError
+	if _, err := NewRijndaelCipher(BlockSize128, 20, 0x1B); !errors.As(err, &keyErr) || keyErr.Size != 20 {
+		t.Errorf("20-byte key: got %v, want KeySizeError with size 20", err)
+	}
+
+	cipher, err := NewRijndaelCipher(BlockSize192, 24, 0x1B)
+	if err != nil {
+		t.Fatalf("NewRijndaelCipher: %v", err)
+	}
+
+	if _, err := cipher.Encrypt(make([]byte, BlockSize192)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Encrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, BlockSize192)); !errors.Is(err, interfaces.ErrKeyNotSet) {
+		t.Errorf("Decrypt without key: got %v, want ErrKeyNotSet", err)
+	}
+
+	if err := cipher.SetKey(make([]byte, 16)); !errors.As(err, &keyErr) || keyErr.Size != 16 {
+		t.Errorf("16-byte key for 192-bit cipher: got %v, want KeySizeError with size 16", err)
+	}
+
+	if err := cipher.SetKey(make([]byte, 24)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	if _, err := cipher.Encrypt(make([]byte, BlockSize128)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Encrypt 16-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, BlockSize256)); !errors.Is(err, interfaces.ErrInvalidBlockSize) {
+		t.Errorf("Decrypt 32-byte block: got %v, want ErrInvalidBlockSize", err)
+	}
+
+	cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+		Key:     make([]byte, 24),
+		Mode:    interfaces.CBC,
+		Padding: interfaces.PKCS7,
+		IV:      make([]byte, BlockSize192),
+	})
+	if err != nil {
+		t.Fatalf("NewCipherContext: %v", err)
+	}
+	if _, err := cc.DecryptBytes(context.Background(), make([]byte, BlockSize192)); !errors.Is(err, interfaces.ErrInvalidPadding) {
+		t.Errorf("decrypt garbage: got %v, want ErrInvalidPadding", err)
+	}
+}
+
+func TestRijndaelDestroy(t *testing.T) {
+	cipher, err := NewRijndaelCipher(BlockSize128, 32, 0x1B)
+	if err != nil {
+		t.Fatalf("NewRijndaelCipher: %v", err)
+	}
+
+	if err := cipher.SetKey(make([]byte, 32)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	first := cipher.roundKeys
+
+	if err := cipher.SetKey(bytes.Repeat([]byte{0x5A}, 32)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	second := cipher.roundKeys
+
+	cipher.Destroy()
+
+	for name, roundKeys := range map[string][][]byte{"replaced": first, "destroyed": second} {
+		for i, roundKey := range roundKeys {
+			if !bytes.Equal(roundKey, make([]byte, len(roundKey))) {
+				t.Errorf("%s round key %d not wiped: %x", name, i, roundKey)
+			}
+		}
+	}
+
+	// The sentinel is returned unwrapped, as by the lab1 ciphers.
+	if _, err := cipher.Encrypt(make([]byte, BlockSize128)); err != interfaces.ErrDestroyed {
+		t.Errorf("Encrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, BlockSize128)); err != interfaces.ErrDestroyed {
+		t.Errorf("Decrypt after Destroy: got %v, want ErrDestroyed", err)
+	}
+	// Destroyed is reported before the block size, as by DES.
+	if _, err := cipher.Encrypt(make([]byte, 3)); err != interfaces.ErrDestroyed {
+		t.Errorf("Encrypt short block after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if _, err := cipher.Decrypt(make([]byte, 3)); err != interfaces.ErrDestroyed {
+		t.Errorf("Decrypt short block after Destroy: got %v, want ErrDestroyed", err)
+	}
+	if err := cipher.SetKey(make([]byte, 32)); err != interfaces.ErrDestroyed {
+		t.Errorf("SetKey after Destroy: got %v, want ErrDestroyed", err)
+	}
+}
@@ -0,0 +1,112 @@
+package Rijndael
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"lab1/interfaces"
+	"lab1/keywrap"
+	"testing"
+)
+
+type keyWrapVector struct {
+	name    string
+	kek     string
+	keyData string
+	wrapped string
+	padded  bool
+}
+
+var keyWrapVectors = []keyWrapVector{
+	{
+		name:    "RFC 3394 4.1 (128-bit KEK, 128-bit key)",
+		kek:     "000102030405060708090A0B0C0D0E0F",
+		keyData: "00112233445566778899AABBCCDDEEFF",
+		wrapped: "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+	},
+	{
+		name:    "RFC 3394 4.2 (192-bit KEK, 128-bit key)",
+		kek:     "000102030405060708090A0B0C0D0E0F1011121314151617",
+		keyData: "00112233445566778899AABBCCDDEEFF",
+		wrapped: "96778B25AE6CA435F92B5B97C050AED2468AB8A17AD84E5D",
+	},
+	{
+		name:    "RFC 3394 4.3 (256-bit KEK, 128-bit key)",
+		kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+		keyData: "00112233445566778899AABBCCDDEEFF",
+		wrapped: "64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7",
+	},
+	{
+		name:    "RFC 3394 4.4 (192-bit KEK, 192-bit key)",
+		kek:     "000102030405060708090A0B0C0D0E0F1011121314151617",
+		keyData: "00112233445566778899AABBCCDDEEFF0001020304050607",
+		wrapped: "031D33264E15D33268F24EC260743EDCE1C6C7DDEE725A936BA814915C6762D2",
+	},
+	{
+		name:    "RFC 3394 4.5 (256-bit KEK, 192-bit key)",
+		kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+		keyData: "00112233445566778899AABBCCDDEEFF0001020304050607",
+		wrapped: "A8F9BC1612C68B3FF6E6F4FBE30E71E4769C8B80A32CB8958CD5D17D6B254DA1",
+	},
+	{
+		name:    "RFC 3394 4.6 (256-bit KEK, 256-bit key)",
+		kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+		keyData: "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+		wrapped: "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+	},
+	{
+		name:    "RFC 5649 (192-bit KEK, 20-byte key)",
+		kek:     "5840DF6E29B02AF1AB493B705BF16EA1AE8338F4DCC176A8",
+		keyData: "C37B7E6492584340BED12207808941155068F738",
+		wrapped: "138BDEAA9B8FA7FC61F97742E72248EE5AE6AE5360D1AE6A5F54F373FA543B6A",
+		padded:  true,
+	},
+	{
+		name:    "RFC 5649 (192-bit KEK, 7-byte key)",
+		kek:     "5840DF6E29B02AF1AB493B705BF16EA1AE8338F4DCC176A8",
+		keyData: "466F7250617369",
+		wrapped: "AFBEB0F07DFBF5419200F2CCB50BB24F",
+		padded:  true,
+	},
+}
+
+func TestKeyWrapVectors(t *testing.T) {
+	for _, v := range keyWrapVectors {
+		kek, _ := hex.DecodeString(v.kek)
+		keyData, _ := hex.DecodeString(v.keyData)
+		want, _ := hex.DecodeString(v.wrapped)
+
+		cipher, err := NewRijndaelCipher(BlockSize128, len(kek), 0x1B)
+		if err != nil {
+			t.Fatalf("%s: NewRijndaelCipher: %v", v.name, err)
+		}
+		wrapper, err := keywrap.NewKeyWrapper(cipher, kek)
+		if err != nil {
+			t.Fatalf("%s: NewKeyWrapper: %v", v.name, err)
+		}
+
+		wrap, unwrap := wrapper.Wrap, wrapper.Unwrap
+		if v.padded {
+			wrap, unwrap = wrapper.WrapWithPadding, wrapper.UnwrapWithPadding
+		}
+
+		wrapped, err := wrap(keyData)
+		if err != nil {
+			t.Fatalf("%s: wrap: %v", v.name, err)
+		}
+		if !bytes.Equal(wrapped, want) {
+			t.Errorf("%s: wrapped %X, want %X", v.name, wrapped, want)
+		}
+
+		unwrapped, err := unwrap(want)
+		if err != nil || !bytes.Equal(unwrapped, keyData) {
+			t.Errorf("%s: unwrapped %X, %v", v.name, unwrapped, err)
+		}
+
+		tampered := bytes.Clone(want)
+		tampered[len(tampered)-1] ^= 0x01
+		if _, err := unwrap(tampered); !errors.Is(err, interfaces.ErrAuthFailed) {
+			t.Errorf("%s: tampered: got %v, want ErrAuthFailed", v.name, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package Rijndael
+
+import (
+	"bytes"
+	"context"
+	"lab1/interfaces"
+	"testing"
+)
+
+// NIST SP 800-38A, Appendix F (AES-128).
+const (
+	sp80038aKey       = "2b7e151628aed2a6abf7158809cf4f3c"
+	sp80038aIV        = "000102030405060708090a0b0c0d0e0f"
+	sp80038aCounter   = "f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff"
+	sp80038aPlaintext = "6bc1bee22e409f96e93d7e117393172a" +
+		"ae2d8a571e03ac9c9eb76fac45af8e51" +
+		"30c81c46a35ce411e5fbc1191a0a52ef" +
+		"f69f2445df4f9b17ad2b417be66c3710"
+)
+
+var sp80038aVectors = []struct {
+	mode       interfaces.CipherMode
+	iv         string
+	ciphertext string
+}{
+	{interfaces.ECB, "", "3ad77bb40d7a3660a89ecaf32466ef97" +
+		"f5d3d58503b9699de785895a96fdbaaf" +
+		"43b1cd7f598ece23881b00e3ed030688" +
+		"7b0c785e27e8ad3f8223207104725dd4"},
+	{interfaces.CBC, sp80038aIV, "7649abac8119b246cee98e9b12e9197d" +
+		"5086cb9b507219ee95db113a917678b2" +
+		"73bed6b8e3c1743b7116e69e22229516" +
+		"3ff1caa1681fac09120eca307586e1a7"},
+	{interfaces.CFB, sp80038aIV, "3b3fd92eb72dad20333449f8e83cfb4a" +
+		"c8a64537a0b3a93fcde3cdad9f1ce58b" +
+		"26751f67a3cbb140b1808cf187a4f4df" +
+		"c04b05357c5d1c0eeac4c66f9ff7f2e6"},
+	{interfaces.OFB, sp80038aIV, "3b3fd92eb72dad20333449f8e83cfb4a" +
+		"7789508d16918f03f53c52dac54ed825" +
+		"9740051e9c5fecf64344f7a82260edcc" +
+		"304c6528f659c77866a510d9c1d6ae5e"},
+	{interfaces.CTR, sp80038aCounter, "874d6191b620e3261bef6864990db6ce" +
+		"9806f66b7970fdff8617187bb9fffdff" +
+		"5ae4df3edbd5d35e5b4f09020db03eab" +
+		"1e031dda2fbe03d1792170a0f3009cee"},
+}
+
+func TestCipherContextSP80038A(t *testing.T) {
+	ctx := context.Background()
+	plaintext := decodeHex(t, sp80038aPlaintext)
+
+	for _, v := range sp80038aVectors {
+		t.Run(v.mode.String(), func(t *testing.T) {
+			cipher, err := NewRijndaelCipher(BlockSize128, 16, 0x1B)
+			if err != nil {
+				t.Fatalf("NewRijndaelCipher: %v", err)
+			}
+
+			var iv []byte
+			if v.iv != "" {
+				iv = decodeHex(t, v.iv)
+			}
+
+			cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+				Key:     decodeHex(t, sp80038aKey),
+				Mode:    v.mode,
+				Padding: interfaces.Zeros,
+				IV:      iv,
+			})
+			if err != nil {
+				t.Fatalf("NewCipherContext: %v", err)
+			}
+
+			expected := decodeHex(t, v.ciphertext)
+
+			encrypted, err := cc.EncryptBytes(ctx, plaintext)
+			if err != nil {
+				t.Fatalf("EncryptBytes: %v", err)
+			}
+			if len(encrypted) < len(expected) || !bytes.Equal(encrypted[:len(expected)], expected) {
+				t.Errorf("got %x, want prefix %x", encrypted, expected)
+			}
+
+			decrypted, err := cc.DecryptBytes(ctx, expected)
+			if err != nil {
+				t.Fatalf("DecryptBytes: %v", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("decrypted %x, want %x", decrypted, plaintext)
+			}
+		})
+	}
+}
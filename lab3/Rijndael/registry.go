@@ -0,0 +1,61 @@
+package Rijndael
+
+import (
+	"fmt"
+	"lab1/interfaces"
+	"lab1/registry"
+	"strconv"
+)
+
+var sizeValues = []string{"128", "192", "256"}
+
+func init() {
+	algorithms := []registry.Algorithm{
+		{
+			Name:        "rijndael",
+			Description: "Rijndael with 128/192/256-bit blocks and keys over a chosen GF(2^8) modulus",
+			Params: []registry.Param{
+				{Name: "block", Description: "block size in bits", Default: "128", Values: sizeValues},
+				{Name: "key", Description: "key size in bits", Default: "128", Values: sizeValues},
+				{Name: "modulus", Description: "low byte of the GF(2^8) modulus in hex", Default: "1B"},
+			},
+			New: newRegisteredRijndael,
+		},
+		{
+			Name:        "aes",
+			Description: "AES (Rijndael with a 128-bit block and modulus 0x11B)",
+			Params: []registry.Param{
+				{Name: "key", Description: "key size in bits", Default: "128", Values: sizeValues},
+			},
+			New: func(params map[string]string) (interfaces.BlockCipher, int, error) {
+				return newRegisteredRijndael(map[string]string{"block": "128", "key": params["key"], "modulus": "1B"})
+			},
+		},
+	}
+
+	for _, algorithm := range algorithms {
+		if err := registry.Register(algorithm); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func newRegisteredRijndael(params map[string]string) (interfaces.BlockCipher, int, error) {
+	blockBits, err := strconv.Atoi(params["block"])
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid block size %q", params["block"])
+	}
+
+	keyBits, err := strconv.Atoi(params["key"])
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid key size %q", params["key"])
+	}
+
+	modulus, err := strconv.ParseUint(params["modulus"], 16, 8)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid modulus %q", params["modulus"])
+	}
+
+	cipher, err := NewRijndaelCipher(blockBits/8, keyBits/8, byte(modulus))
+	return cipher, keyBits / 8, err
+}
@@ -0,0 +1,81 @@
+package Rijndael
+
+import (
+	"bytes"
+	"context"
+	"lab1/interfaces"
+	"testing"
+)
+
+var (
+	roundTripModes = []interfaces.CipherMode{
+		interfaces.ECB, interfaces.CBC, interfaces.PCBC, interfaces.CFB,
+		interfaces.OFB, interfaces.CTR, interfaces.RandomDelta,
+	}
+	roundTripPaddings = []interfaces.PaddingMode{
+		interfaces.Zeros, interfaces.ANSIX923, interfaces.PKCS7, interfaces.ISO10126,
+	}
+	roundTripSizes = []int{BlockSize128, BlockSize192, BlockSize256}
+)
+
+func FuzzRijndaelRoundTrip(f *testing.F) {
+	ciphers := make([]*RijndaelCipher, 0, len(roundTripSizes)*len(roundTripSizes))
+	for _, blockSize := range roundTripSizes {
+		for _, keySize := range roundTripSizes {
+			cipher, err := NewRijndaelCipher(blockSize, keySize, 0x1B)
+			if err != nil {
+				f.Fatalf("NewRijndaelCipher(%d, %d): %v", blockSize, keySize, err)
+			}
+			ciphers = append(ciphers, cipher)
+		}
+	}
+
+	for c := range ciphers {
+		for mode := range roundTripModes {
+			for padding := range roundTripPaddings {
+				f.Add(byte(c), byte(mode), byte(padding), []byte("0123456789abcdef0123456789abcdef"), []byte("round trip input"))
+			}
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, cipherSelector, modeSelector, paddingSelector byte, keyMaterial, plaintext []byte) {
+		cipher := ciphers[int(cipherSelector)%len(ciphers)]
+		keySize := roundTripSizes[int(cipherSelector)%len(ciphers)%len(roundTripSizes)]
+		mode := roundTripModes[int(modeSelector)%len(roundTripModes)]
+		padding := roundTripPaddings[int(paddingSelector)%len(roundTripPaddings)]
+
+		if len(keyMaterial) < keySize || len(plaintext) > 128 {
+			t.Skip()
+		}
+
+		cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+			Key:     keyMaterial[:keySize],
+			Mode:    mode,
+			Padding: padding,
+		})
+		if err != nil {
+			t.Fatalf("NewCipherContext: %v", err)
+		}
+
+		ctx := context.Background()
+
+		encrypted, err := cc.EncryptBytes(ctx, plaintext)
+		if err != nil {
+			t.Fatalf("%d/%d %v/%v: EncryptBytes: %v", cipher.BlockSize(), keySize, mode, padding, err)
+		}
+
+		decrypted, err := cc.DecryptBytes(ctx, encrypted)
+		if err != nil {
+			t.Fatalf("%d/%d %v/%v: DecryptBytes: %v", cipher.BlockSize(), keySize, mode, padding, err)
+		}
+
+		expected := plaintext
+		if padding == interfaces.Zeros {
+			expected = bytes.TrimRight(plaintext, "\x00")
+		}
+
+		if !bytes.Equal(decrypted, expected) {
+			t.Fatalf("%d/%d %v/%v: got %x, want %x", cipher.BlockSize(), keySize, mode, padding, decrypted, expected)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package Rijndael
+
+import (
+	"fmt"
+	"lab1/analysis"
+	"lab3/statelessService"
+)
+
+// SBoxes returns the S-box and inverse S-box built over GF(2^8) modulo
+// x^8 + modulus.
+func SBoxes(modulus byte) ([]byte, []byte, error) {
+	gf28Service := statelessService.NewGF28Service()
+
+	irreducible, err := gf28Service.IsIrreducibleDegree8(0x100 | uint16(modulus))
+	if err != nil {
+		return nil, nil, fmt.Errorf("SBoxes: %w", err)
+	}
+	if !irreducible {
+		return nil, nil, fmt.Errorf("SBoxes: modulus 0x1%02X is not irreducible", modulus)
+	}
+
+	sbox, invSbox := computeSBoxes(gf28Service, modulus)
+	return sbox, invSbox, nil
+}
+
+// AnalyzeModuli computes the S-box properties for every irreducible
+// degree-8 modulus, in the order returned by ListIrreduciblePolynomials.
+func AnalyzeModuli() ([]analysis.SBoxProperties, error) {
+	moduli, err := statelessService.NewGF28Service().ListIrreduciblePolynomials()
+	if err != nil {
+		return nil, fmt.Errorf("AnalyzeModuli: %w", err)
+	}
+
+	properties := make([]analysis.SBoxProperties, 0, len(moduli))
+	for _, poly := range moduli {
+		sbox, _, err := SBoxes(byte(poly))
+		if err != nil {
+			return nil, fmt.Errorf("AnalyzeModuli: %w", err)
+		}
+
+		s, err := analysis.NewByteSBox(sbox)
+		if err != nil {
+			return nil, fmt.Errorf("AnalyzeModuli: %w", err)
+		}
+		properties = append(properties, s.Properties(fmt.Sprintf("0x%03X", poly)))
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,56 @@
+package Rijndael
+
+import (
+	"lab1/analysis"
+	"testing"
+)
+
+func TestAnalyzeModuli(t *testing.T) {
+	properties, err := AnalyzeModuli()
+	if err != nil {
+		t.Fatalf("AnalyzeModuli: %v", err)
+	}
+	if len(properties) != 30 {
+		t.Fatalf("got %d moduli, want 30", len(properties))
+	}
+
+	// Inversion is affine equivalent in every representation of GF(2^8), so
+	// only the fixed points can tell the moduli apart.
+	for _, p := range properties {
+		if !p.Bijective || p.DifferentialUniformity != 4 || p.Nonlinearity != 112 || p.AlgebraicDegree != 7 {
+			t.Errorf("%s: %+v", p.Name, p)
+		}
+	}
+
+	aes := properties[0]
+	for _, p := range properties {
+		if p.Name == "0x11B" {
+			aes = p
+		}
+	}
+	if aes.Name != "0x11B" || aes.FixedPoints != 0 || aes.OppositeFixedPoints != 0 {
+		t.Errorf("AES S-box: %+v", aes)
+	}
+}
+
+func TestSBoxes(t *testing.T) {
+	sbox, invSbox, err := SBoxes(0x1B)
+	if err != nil {
+		t.Fatalf("SBoxes: %v", err)
+	}
+	if sbox[0x00] != 0x63 || sbox[0x53] != 0xED || invSbox[0x63] != 0x00 {
+		t.Errorf("AES S-box: S(00)=%02x S(53)=%02x S^-1(63)=%02x", sbox[0x00], sbox[0x53], invSbox[0x63])
+	}
+
+	s, err := analysis.NewByteSBox(invSbox)
+	if err != nil {
+		t.Fatalf("NewByteSBox: %v", err)
+	}
+	if !s.IsBijective() || s.DifferentialUniformity() != 4 {
+		t.Errorf("inverse S-box: bijective %v, uniformity %d", s.IsBijective(), s.DifferentialUniformity())
+	}
+
+	if _, _, err := SBoxes(0x00); err == nil {
+		t.Error("SBoxes accepted the reducible modulus x^8")
+	}
+}
@@ -0,0 +1,53 @@
+package Rijndael
+
+import (
+	"bytes"
+	"crypto/aes"
+	"lab1/desx"
+	"testing"
+)
+
+func TestWhitenedRijndael(t *testing.T) {
+	inner, err := NewRijndaelCipher(BlockSize128, BlockSize128, 0x1B)
+	if err != nil {
+		t.Fatalf("NewRijndaelCipher: %v", err)
+	}
+	cipher, err := desx.Whitened(inner)
+	if err != nil {
+		t.Fatalf("Whitened: %v", err)
+	}
+
+	key := []byte("0123456789abcdefpre-whitening-k1post-whitening-2")
+	if err := cipher.SetKey(key); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+
+	// AES-128 is Rijndael-128-128, so the FX construction can be checked
+	// against crypto/aes.
+	reference, _ := aes.NewCipher(key[:16])
+	plaintext := []byte("sixteen byte msg")
+	expected := make([]byte, 16)
+	for i := range expected {
+		expected[i] = plaintext[i] ^ key[16+i]
+	}
+	reference.Encrypt(expected, expected)
+	for i := range expected {
+		expected[i] ^= key[32+i]
+	}
+
+	encrypted, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !bytes.Equal(encrypted, expected) {
+		t.Errorf("Encrypt: got %X, want %X", encrypted, expected)
+	}
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt: got %q, want %q", decrypted, plaintext)
+	}
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"lab1/interfaces"
+	"lab1/registry"
+	_ "lab3/Rijndael"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+const (
+	exitOK = iota
+	exitFailure
+	exitUsage
+	exitIO
+)
+
+const (
+	saltSize         = 16
+	pbkdf2Iterations = 100000
+)
+
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+func ioError(err error) error {
+	return &exitError{code: exitIO, err: err}
+}
+
+type options struct {
+	spec      string
+	cipher    string
+	variant   string
+	rounds    int
+	blockSize int
+	keySize   int
+	modulus   string
+	mode      string
+	padding   string
+	keyHex    string
+	keyFile   string
+	password  string
+	iv        string
+	input     string
+	output    string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	var encrypt bool
+	switch args[0] {
+	case "encrypt":
+		encrypt = true
+	case "decrypt":
+		encrypt = false
+	case "list":
+		printAlgorithms(stdout)
+		return exitOK
+	case "help", "-h", "--help":
+		printUsage(stdout)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "crypto: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	opts, err := parseOptions(args[0], args[1:], stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return reportError(stderr, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := execute(ctx, encrypt, opts, stdin, stdout); err != nil {
+		return reportError(stderr, err)
+	}
+
+	return exitOK
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, `usage: crypto encrypt|decrypt [flags]
+       crypto list
+
+Reads from --in (default stdin) and writes to --out (default stdout).
+Run "crypto encrypt -h" for the list of flags.
+
+Encrypted output layout:
+  [salt, 16 bytes, only with --password]
+  [IV, one block, only for IV modes when --iv is not given]
+  ciphertext
+
+Exit codes: 0 success, 1 encryption or decryption failure,
+2 invalid usage, 3 input/output error.`)
+}
+
+func reportError(w io.Writer, err error) int {
+	fmt.Fprintf(w, "crypto: %v\n", err)
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitFailure
+}
+
+func parseOptions(command string, args []string, stderr io.Writer) (*options, error) {
+	opts := &options{}
+
+	fs := flag.NewFlagSet("crypto "+command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.spec, "spec", "", "full ALGO-PARAMS-MODE specifier, e.g. rijndael-256-192-CBC (overrides cipher flags and --mode)")
+	fs.StringVar(&opts.cipher, "cipher", "rijndael", "cipher: des, 3des, deal, rijndael or any registered ALGO-PARAMS name")
+	fs.StringVar(&opts.variant, "variant", "ede", "TripleDES variant: ede or eee")
+	fs.IntVar(&opts.rounds, "rounds", 6, "number of DEAL rounds")
+	fs.IntVar(&opts.blockSize, "block-size", 128, "Rijndael block size in bits: 128, 192 or 256")
+	fs.IntVar(&opts.keySize, "key-size", 128, "Rijndael key size in bits: 128, 192 or 256")
+	fs.StringVar(&opts.modulus, "modulus", "0x1B", "Rijndael GF(2^8) modulus (low byte or full 9-bit polynomial)")
+	fs.StringVar(&opts.mode, "mode", "CBC", "cipher mode: ECB, CBC, PCBC, CFB, OFB, CTR or RandomDelta")
+	fs.StringVar(&opts.padding, "padding", "PKCS7", "padding: Zeros, ANSIX923, PKCS7 or ISO10126")
+	fs.StringVar(&opts.keyHex, "key-hex", "", "key as a hex string")
+	fs.StringVar(&opts.keyFile, "key-file", "", "file containing the raw key bytes")
+	fs.StringVar(&opts.password, "password", "", "derive the key from a password with PBKDF2-SHA256")
+	fs.StringVar(&opts.iv, "iv", "", "IV as a hex string (default: random, stored in the output)")
+	fs.StringVar(&opts.input, "in", "-", "input file, - for stdin")
+	fs.StringVar(&opts.output, "out", "-", "output file, - for stdout")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, &exitError{code: exitUsage, err: err}
+	}
+
+	if fs.NArg() != 0 {
+		return nil, usageErrorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	keySources := 0
+	for _, source := range []string{opts.keyHex, opts.keyFile, opts.password} {
+		if source != "" {
+			keySources++
+		}
+	}
+	if keySources != 1 {
+		return nil, usageErrorf("exactly one of --key-hex, --key-file or --password is required")
+	}
+
+	return opts, nil
+}
+
+func cipherName(opts *options) (string, error) {
+	switch strings.ToLower(opts.cipher) {
+	case "3des", "tripledes":
+		return "3des-" + opts.variant, nil
+
+	case "deal":
+		return fmt.Sprintf("deal-128-%d", opts.rounds), nil
+
+	case "rijndael", "aes":
+		modulus, err := strconv.ParseUint(opts.modulus, 0, 16)
+		if err != nil || modulus > 0x1FF || (modulus > 0xFF && modulus&0x100 == 0) {
+			return "", usageErrorf("invalid modulus %q", opts.modulus)
+		}
+		return fmt.Sprintf("rijndael-%d-%d-%X", opts.blockSize, opts.keySize, byte(modulus)), nil
+
+	default:
+		return opts.cipher, nil
+	}
+}
+
+func newCipher(opts *options) (interfaces.BlockCipher, interfaces.CipherMode, int, error) {
+	if opts.spec != "" {
+		spec, err := registry.ParseSpec(opts.spec)
+		if err != nil {
+			return nil, 0, 0, &exitError{code: exitUsage, err: err}
+		}
+
+		cipher, keySize, err := spec.NewBlockCipher()
+		if err != nil {
+			return nil, 0, 0, &exitError{code: exitUsage, err: err}
+		}
+		return cipher, spec.Mode, keySize, nil
+	}
+
+	mode, err := interfaces.ParseCipherMode(opts.mode)
+	if err != nil {
+		return nil, 0, 0, &exitError{code: exitUsage, err: err}
+	}
+
+	name, err := cipherName(opts)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	cipher, keySize, err := registry.NewBlockCipher(name)
+	if err != nil {
+		return nil, 0, 0, &exitError{code: exitUsage, err: err}
+	}
+	return cipher, mode, keySize, nil
+}
+
+func printAlgorithms(w io.Writer) {
+	for _, algorithm := range registry.Algorithms() {
+		fmt.Fprintf(w, "%s-MODE\n    %s\n", algorithm.Usage(), algorithm.Description)
+		for _, param := range algorithm.Params {
+			values := "any"
+			if len(param.Values) > 0 {
+				values = strings.Join(param.Values, "|")
+			}
+			fmt.Fprintf(w, "    %-8s %s (default %s, values %s)\n", strings.ToUpper(param.Name), param.Description, param.Default, values)
+		}
+	}
+}
+
+// ioReader and ioWriter remember I/O errors so that they can be told apart
+// from cipher errors returned by the same stream call.
+type ioReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *ioReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+type ioWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *ioWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+func openInput(path string, stdin io.Reader) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	return os.Open(path)
+}
+
+// openOutput returns the output and a function that closes it, removing a
+// file left incomplete by a failure.
+func openOutput(path string, stdout io.Writer) (io.Writer, func(failed bool) error, error) {
+	if path == "-" {
+		return stdout, func(bool) error { return nil }, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func(failed bool) error {
+		err := file.Close()
+		if failed {
+			os.Remove(path)
+		}
+		return err
+	}, nil
+}
+
+func execute(ctx context.Context, encrypt bool, opts *options, stdin io.Reader, stdout io.Writer) (err error) {
+	padding, err := interfaces.ParsePaddingMode(opts.padding)
+	if err != nil {
+		return &exitError{code: exitUsage, err: err}
+	}
+
+	cipher, mode, keySize, err := newCipher(opts)
+	if err != nil {
+		return err
+	}
+	blockSize := cipher.BlockSize()
+
+	inputFile, err := openInput(opts.input, stdin)
+	if err != nil {
+		return ioError(fmt.Errorf("failed to open input: %w", err))
+	}
+	defer inputFile.Close()
+	input := &ioReader{r: inputFile}
+
+	var header []byte
+
+	var key []byte
+	switch {
+	case opts.keyHex != "":
+		key, err = hex.DecodeString(opts.keyHex)
+		if err != nil {
+			return usageErrorf("invalid --key-hex: %v", err)
+		}
+
+	case opts.keyFile != "":
+		key, err = os.ReadFile(opts.keyFile)
+		if err != nil {
+			return ioError(fmt.Errorf("failed to read key file: %w", err))
+		}
+
+	default:
+		salt := make([]byte, saltSize)
+		if encrypt {
+			if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+				return fmt.Errorf("failed to generate salt: %w", err)
+			}
+			header = append(header, salt...)
+		} else if err := readHeader(input, salt, "a salt"); err != nil {
+			return err
+		}
+
+		key, err = pbkdf2.Key(sha256.New, opts.password, salt, pbkdf2Iterations, keySize)
+		if err != nil {
+			return fmt.Errorf("failed to derive key: %w", err)
+		}
+	}
+
+	var iv []byte
+	if mode.RequiresIV() {
+		if opts.iv != "" {
+			iv, err = hex.DecodeString(opts.iv)
+			if err != nil {
+				return usageErrorf("invalid --iv: %v", err)
+			}
+			if len(iv) != blockSize {
+				return usageErrorf("--iv must be %d bytes for this cipher", blockSize)
+			}
+		} else if encrypt {
+			iv = make([]byte, blockSize)
+			if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+				return fmt.Errorf("failed to generate IV: %w", err)
+			}
+			header = append(header, iv...)
+		} else {
+			iv = make([]byte, blockSize)
+			if err := readHeader(input, iv, "an IV"); err != nil {
+				return err
+			}
+		}
+	}
+
+	cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+		Key:     key,
+		Mode:    mode,
+		Padding: padding,
+		IV:      iv,
+	})
+	interfaces.Zeroize(key)
+	if err != nil {
+		return &exitError{code: exitUsage, err: err}
+	}
+	defer cc.Close()
+
+	outputFile, closeOutput, err := openOutput(opts.output, stdout)
+	if err != nil {
+		return ioError(fmt.Errorf("failed to open output: %w", err))
+	}
+	defer func() {
+		if closeErr := closeOutput(err != nil); closeErr != nil && err == nil {
+			err = ioError(fmt.Errorf("failed to write output: %w", closeErr))
+		}
+	}()
+	output := &ioWriter{w: outputFile}
+
+	if encrypt {
+		if _, err = output.Write(header); err == nil {
+			err = cc.EncryptStream(ctx, input, output)
+		}
+	} else {
+		err = cc.DecryptStream(ctx, input, output)
+	}
+
+	switch {
+	case input.err != nil || output.err != nil:
+		return ioError(err)
+	case err != nil && encrypt:
+		return fmt.Errorf("encryption failed: %w", err)
+	case err != nil:
+		return fmt.Errorf("decryption failed: %w", err)
+	}
+	return nil
+}
+
+func readHeader(input io.Reader, header []byte, name string) error {
+	if _, err := io.ReadFull(input, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("input is too short to contain %s", name)
+		}
+		return ioError(fmt.Errorf("failed to read input: %w", err))
+	}
+	return nil
+}
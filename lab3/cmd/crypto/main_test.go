@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runCommand(t *testing.T, args []string, input []byte) ([]byte, int) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, bytes.NewReader(input), &stdout, &stderr)
+	if code != exitOK {
+		t.Logf("crypto %s: %s", strings.Join(args, " "), stderr.String())
+	}
+	return stdout.Bytes(), code
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	plaintext := []byte("The quick brown fox jumps over the lazy dog")
+
+	cases := [][]string{
+		{"--cipher", "des", "--mode", "CBC", "--key-hex", "0123456789abcdef"},
+		{"--cipher", "3des", "--mode", "CTR", "--padding", "ANSIX923", "--key-hex", "0123456789abcdeffedcba98765432100123456789abcdef"},
+		{"--cipher", "deal", "--rounds", "8", "--mode", "OFB", "--password", "correct horse"},
+		{"--cipher", "rijndael", "--block-size", "256", "--key-size", "192", "--modulus", "0x1B", "--mode", "PCBC", "--password", "battery staple"},
+		{"--cipher", "rijndael", "--mode", "ECB", "--padding", "ISO10126", "--key-hex", "000102030405060708090a0b0c0d0e0f"},
+		{"--spec", "rijndael-256-192-CBC", "--key-hex", "000102030405060708090a0b0c0d0e0f1011121314151617"},
+		{"--spec", "3des-eee-CTR", "--password", "hunter2"},
+		{"--cipher", "aes-256", "--mode", "OFB", "--password", "hunter2"},
+		{"--cipher", "rijndael", "--mode", "CFB", "--key-hex", "000102030405060708090a0b0c0d0e0f", "--iv", "0f0e0d0c0b0a09080706050403020100"},
+	}
+
+	for _, args := range cases {
+		encrypted, code := runCommand(t, append([]string{"encrypt"}, args...), plaintext)
+		if code != exitOK {
+			t.Fatalf("encrypt %v: exit code %d", args, code)
+		}
+
+		decrypted, code := runCommand(t, append([]string{"decrypt"}, args...), encrypted)
+		if code != exitOK {
+			t.Fatalf("decrypt %v: exit code %d", args, code)
+		}
+
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("%v: got %q, want %q", args, decrypted, plaintext)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	cases := []struct {
+		args []string
+		want int
+	}{
+		{[]string{}, exitUsage},
+		{[]string{"frobnicate"}, exitUsage},
+		{[]string{"encrypt", "--cipher", "des"}, exitUsage},
+		{[]string{"encrypt", "--cipher", "blowfish", "--key-hex", "00"}, exitUsage},
+		{[]string{"encrypt", "--cipher", "des", "--key-hex", "0011"}, exitUsage},
+		{[]string{"encrypt", "--cipher", "des", "--mode", "XTS", "--key-hex", "0123456789abcdef"}, exitUsage},
+		{[]string{"encrypt", "--spec", "rijndael-512-128-CBC", "--key-hex", "00"}, exitUsage},
+		{[]string{"encrypt", "--spec", "des", "--key-hex", "0123456789abcdef"}, exitUsage},
+		{[]string{"encrypt", "--cipher", "des", "--key-file", "/nonexistent/key"}, exitIO},
+		{[]string{"decrypt", "--cipher", "des", "--mode", "ECB", "--key-hex", "0123456789abcdef"}, exitFailure},
+	}
+
+	for _, c := range cases {
+		if _, code := runCommand(t, c.args, []byte("not a ciphertext")); code != c.want {
+			t.Errorf("crypto %s: exit code %d, want %d", strings.Join(c.args, " "), code, c.want)
+		}
+	}
+}
+
+func TestStreamFiles(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain")
+	encrypted := filepath.Join(dir, "encrypted")
+	decrypted := filepath.Join(dir, "decrypted")
+
+	// Several stream chunks, ending in a partial block.
+	plaintext := make([]byte, 70000+5)
+	for i := range plaintext {
+		plaintext[i] = byte(i * 31)
+	}
+	if err := os.WriteFile(plain, plaintext, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	key := []string{"--cipher", "des", "--mode", "CBC", "--key-hex", "0123456789abcdef"}
+	if _, code := runCommand(t, append([]string{"encrypt", "--in", plain, "--out", encrypted}, key...), nil); code != exitOK {
+		t.Fatalf("encrypt: exit code %d", code)
+	}
+	if _, code := runCommand(t, append([]string{"decrypt", "--in", encrypted, "--out", decrypted}, key...), nil); code != exitOK {
+		t.Fatalf("decrypt: exit code %d", code)
+	}
+	if got, _ := os.ReadFile(decrypted); !bytes.Equal(got, plaintext) {
+		t.Errorf("decrypted file has %d bytes, want the %d-byte original", len(got), len(plaintext))
+	}
+
+	// A failed decryption leaves no partial output behind.
+	wrong := []string{"decrypt", "--in", encrypted, "--out", filepath.Join(dir, "wrong"), "--cipher", "des", "--mode", "CBC", "--key-hex", "fedcba9876543210"}
+	if _, code := runCommand(t, wrong, nil); code != exitFailure {
+		t.Errorf("decrypt with the wrong key: exit code %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wrong")); !os.IsNotExist(err) {
+		t.Errorf("failed decryption left its output: %v", err)
+	}
+
+	missing := []string{"encrypt", "--in", filepath.Join(dir, "missing"), "--cipher", "des", "--key-hex", "0123456789abcdef"}
+	if _, code := runCommand(t, missing, nil); code != exitIO {
+		t.Errorf("encrypt of a missing file: exit code %d", code)
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"lab1/interfaces"
+	"lab1/registry"
+	_ "lab3/Rijndael"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type benchmarkResult struct {
+	Cipher         string  `json:"cipher"`
+	Mode           string  `json:"mode"`
+	Operation      string  `json:"operation"`
+	Size           int     `json:"size"`
+	Workers        int     `json:"workers"`
+	Iterations     int     `json:"iterations"`
+	MBPerSecond    float64 `json:"mb_per_second"`
+	AllocsPerBlock float64 `json:"allocs_per_block"`
+	Speedup        float64 `json:"speedup"`
+}
+
+type benchmarkReport struct {
+	GoVersion  string            `json:"go_version"`
+	GOOS       string            `json:"goos"`
+	GOARCH     string            `json:"goarch"`
+	NumCPU     int               `json:"num_cpu"`
+	Duration   string            `json:"duration"`
+	Benchmarks []benchmarkResult `json:"benchmarks"`
+}
+
+type cipherFactory struct {
+	name    string
+	keySize int
+	create  func() (interfaces.BlockCipher, error)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "cryptobench: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("cryptobench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	ciphersFlag := fs.String("ciphers", "des,3des,deal,rijndael-128-128", "comma-separated registered ciphers, e.g. des, 3des-eee, deal-128-8, rijndael-256-192")
+	modesFlag := fs.String("modes", "ECB,CBC,PCBC,CFB,OFB,CTR,RandomDelta", "comma-separated cipher modes")
+	sizesFlag := fs.String("sizes", "1024,16384", "comma-separated message sizes in bytes")
+	workersFlag := fs.String("workers", "1,2,4,8", "comma-separated worker counts")
+	duration := fs.Duration("duration", 500*time.Millisecond, "minimum measuring time per benchmark")
+	decrypt := fs.Bool("decrypt", false, "also benchmark decryption")
+	jsonOutput := fs.Bool("json", false, "emit results as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	factories, err := parseCiphers(*ciphersFlag)
+	if err != nil {
+		return err
+	}
+
+	modes, err := parseModes(*modesFlag)
+	if err != nil {
+		return err
+	}
+
+	sizes, err := parseInts(*sizesFlag, "size")
+	if err != nil {
+		return err
+	}
+
+	workers, err := parseInts(*workersFlag, "worker count")
+	if err != nil {
+		return err
+	}
+
+	operations := []string{"encrypt"}
+	if *decrypt {
+		operations = append(operations, "decrypt")
+	}
+
+	report := benchmarkReport{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Duration:  duration.String(),
+	}
+
+	for _, factory := range factories {
+		for _, mode := range modes {
+			for _, operation := range operations {
+				for _, size := range sizes {
+					var baseline float64
+					for i, workerCount := range workers {
+						result, err := measure(factory, mode, operation, size, workerCount, *duration)
+						if err != nil {
+							return fmt.Errorf("%s %v %s: %w", factory.name, mode, operation, err)
+						}
+
+						if i == 0 {
+							baseline = result.MBPerSecond
+						}
+						if baseline > 0 {
+							result.Speedup = result.MBPerSecond / baseline
+						}
+
+						report.Benchmarks = append(report.Benchmarks, result)
+					}
+				}
+			}
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	return printTable(stdout, report)
+}
+
+func printTable(w io.Writer, report benchmarkReport) error {
+	fmt.Fprintf(w, "%s %s/%s, %d CPUs, %s per benchmark\n\n", report.GoVersion, report.GOOS, report.GOARCH, report.NumCPU, report.Duration)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "CIPHER\tMODE\tOP\tSIZE\tWORKERS\tMB/s\tALLOCS/BLOCK\tSPEEDUP\t")
+	for _, r := range report.Benchmarks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%.3f\t%.1f\t%.2fx\t\n",
+			r.Cipher, r.Mode, r.Operation, r.Size, r.Workers, r.MBPerSecond, r.AllocsPerBlock, r.Speedup)
+	}
+	return tw.Flush()
+}
+
+func measure(factory cipherFactory, mode interfaces.CipherMode, operation string, size, workers int, duration time.Duration) (benchmarkResult, error) {
+	cipher, err := factory.create()
+	if err != nil {
+		return benchmarkResult{}, err
+	}
+
+	cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+		Key:     make([]byte, factory.keySize),
+		Mode:    mode,
+		Padding: interfaces.PKCS7,
+		Workers: workers,
+	})
+	if err != nil {
+		return benchmarkResult{}, err
+	}
+	defer cc.Close()
+
+	ctx := context.Background()
+	input := make([]byte, size)
+
+	apply := cc.EncryptBytes
+	if operation == "decrypt" {
+		input, err = cc.EncryptBytes(ctx, input)
+		if err != nil {
+			return benchmarkResult{}, err
+		}
+		apply = cc.DecryptBytes
+	}
+
+	if _, err := apply(ctx, input); err != nil {
+		return benchmarkResult{}, err
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	iterations := 0
+	start := time.Now()
+	for iterations == 0 || time.Since(start) < duration {
+		if _, err := apply(ctx, input); err != nil {
+			return benchmarkResult{}, err
+		}
+		iterations++
+	}
+	elapsed := time.Since(start)
+
+	runtime.ReadMemStats(&after)
+
+	blocks := (size + cipher.BlockSize()) / cipher.BlockSize()
+
+	return benchmarkResult{
+		Cipher:         factory.name,
+		Mode:           mode.String(),
+		Operation:      operation,
+		Size:           size,
+		Workers:        workers,
+		Iterations:     iterations,
+		MBPerSecond:    float64(size) * float64(iterations) / elapsed.Seconds() / 1e6,
+		AllocsPerBlock: float64(after.Mallocs-before.Mallocs) / float64(iterations*blocks),
+	}, nil
+}
+
+func parseCiphers(list string) ([]cipherFactory, error) {
+	var factories []cipherFactory
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+
+		spec, err := registry.ParseCipher(name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, keySize, err := spec.NewBlockCipher()
+		if err != nil {
+			return nil, err
+		}
+
+		factories = append(factories, cipherFactory{name, keySize, func() (interfaces.BlockCipher, error) {
+			cipher, _, err := spec.NewBlockCipher()
+			return cipher, err
+		}})
+	}
+
+	return factories, nil
+}
+
+func parseModes(list string) ([]interfaces.CipherMode, error) {
+	var modes []interfaces.CipherMode
+	for _, name := range strings.Split(list, ",") {
+		mode, err := interfaces.ParseCipherMode(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		modes = append(modes, mode)
+	}
+	return modes, nil
+}
+
+func parseInts(list, what string) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(list, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid %s %q", what, field)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
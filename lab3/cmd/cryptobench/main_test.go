@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONReport(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"--ciphers", "des,rijndael-128-128", "--modes", "ECB,CTR", "--sizes", "64",
+		"--workers", "1,2", "--duration", "1ms", "--decrypt", "--json"}
+	if err := run(args, &stdout, &stderr); err != nil {
+		t.Fatalf("cryptobench %s: %v", strings.Join(args, " "), err)
+	}
+
+	var report map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	for _, field := range []string{"go_version", "goos", "goarch", "num_cpu", "duration", "benchmarks"} {
+		if _, ok := report[field]; !ok {
+			t.Errorf("report has no %q field", field)
+		}
+	}
+
+	benchmarks, _ := report["benchmarks"].([]any)
+	// 2 ciphers, 2 modes, 2 operations and 2 worker counts.
+	if len(benchmarks) != 16 {
+		t.Fatalf("got %d benchmarks, want 16", len(benchmarks))
+	}
+	for _, b := range benchmarks {
+		result := b.(map[string]any)
+		for _, field := range []string{"cipher", "mode", "operation", "size", "workers", "iterations", "mb_per_second", "allocs_per_block", "speedup"} {
+			if _, ok := result[field]; !ok {
+				t.Fatalf("benchmark %v has no %q field", result, field)
+			}
+		}
+		if result["size"] != 64.0 || result["iterations"].(float64) < 1 || result["mb_per_second"].(float64) <= 0 {
+			t.Errorf("implausible benchmark %v", result)
+		}
+	}
+
+	first := benchmarks[0].(map[string]any)
+	if first["cipher"] != "des" || first["mode"] != "ECB" || first["operation"] != "encrypt" || first["speedup"] != 1.0 {
+		t.Errorf("first benchmark %v", first)
+	}
+}
+
+func TestTableAndErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"--ciphers", "des", "--modes", "CBC", "--sizes", "16", "--workers", "1", "--duration", "1ms"}, &stdout, &stderr); err != nil {
+		t.Fatalf("cryptobench: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "CIPHER") || !strings.Contains(stdout.String(), "CBC") {
+		t.Errorf("table output:\n%s", stdout.String())
+	}
+
+	for _, args := range [][]string{
+		{"--ciphers", "blowfish"},
+		{"--modes", "XTS"},
+		{"--sizes", "big"},
+		{"--workers", "0x"},
+	} {
+		if err := run(args, &stdout, &stderr); err == nil {
+			t.Errorf("cryptobench %s succeeded", strings.Join(args, " "))
+		}
+	}
+}
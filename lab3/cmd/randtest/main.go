@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"lab1/interfaces"
+	"lab1/randomness"
+	"lab1/registry"
+	_ "lab3/Rijndael"
+	"os"
+	"text/tabwriter"
+)
+
+type testResult struct {
+	Name    string    `json:"name"`
+	PValues []float64 `json:"p_values,omitempty"`
+	Passed  bool      `json:"passed"`
+	Error   string    `json:"error,omitempty"`
+}
+
+type testReport struct {
+	Spec    string       `json:"spec"`
+	Input   string       `json:"input"`
+	Bits    int          `json:"bits"`
+	Alpha   float64      `json:"alpha"`
+	Passed  int          `json:"passed"`
+	Results []testResult `json:"results"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "randtest: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("randtest", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	specFlag := fs.String("spec", "des-CTR", "cipher specifier ALGO[-PARAMS]-MODE, e.g. 3des-eee-OFB, rijndael-128-128-ECB")
+	input := fs.String("input", "zeros", "plaintext fed to the cipher: zeros (keystream in CTR/OFB) or counter (low-entropy blocks)")
+	bitCount := fs.Int("bits", 1000000, "length of the tested sequence in bits")
+	keyHex := fs.String("key-hex", "", "key in hex (random if empty)")
+	ivHex := fs.String("iv-hex", "", "IV in hex (random if empty)")
+	alpha := fs.Float64("alpha", randomness.DefaultAlpha, "significance level")
+	blockSize := fs.Int("block-size", randomness.DefaultBlockSize, "block length M of the block frequency test")
+	jsonOutput := fs.Bool("json", false, "emit results as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *bitCount <= 0 {
+		return fmt.Errorf("invalid bit count %d", *bitCount)
+	}
+	if *alpha <= 0 || *alpha >= 1 {
+		return fmt.Errorf("alpha must be in (0, 1) (got %v)", *alpha)
+	}
+
+	spec, err := registry.ParseSpec(*specFlag)
+	if err != nil {
+		return err
+	}
+
+	cipher, keySize, err := spec.NewBlockCipher()
+	if err != nil {
+		return err
+	}
+
+	key, err := decodeOrRandom(*keyHex, keySize, "key")
+	if err != nil {
+		return err
+	}
+	defer interfaces.Zeroize(key)
+
+	var iv []byte
+	if *ivHex != "" {
+		if iv, err = hex.DecodeString(*ivHex); err != nil {
+			return fmt.Errorf("invalid IV: %w", err)
+		}
+	}
+
+	plaintext, err := makeInput(*input, (*bitCount+7)/8, cipher.BlockSize())
+	if err != nil {
+		return err
+	}
+
+	cc, err := interfaces.NewCipherContext(cipher, interfaces.CipherContextConfig{
+		Key:     key,
+		Mode:    spec.Mode,
+		Padding: interfaces.Zeros,
+		IV:      iv,
+	})
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	output, err := cc.EncryptBytes(context.Background(), plaintext)
+	if err != nil {
+		return err
+	}
+
+	sequence := randomness.FromBytes(output)[:*bitCount]
+	results := randomness.Run(sequence, randomness.Config{BlockSize: *blockSize})
+
+	report := testReport{
+		Spec:  spec.String(),
+		Input: *input,
+		Bits:  *bitCount,
+		Alpha: *alpha,
+	}
+	for _, r := range results {
+		result := testResult{Name: r.Name, PValues: r.PValues, Passed: r.Passed(*alpha)}
+		if r.Err != nil {
+			result.Error = r.Err.Error()
+		}
+		if result.Passed {
+			report.Passed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	return printTable(stdout, report)
+}
+
+func printTable(w io.Writer, report testReport) error {
+	fmt.Fprintf(w, "%s, %s input, %d bits, alpha %v\n\n", report.Spec, report.Input, report.Bits, report.Alpha)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tP-VALUE\tRESULT")
+	for _, r := range report.Results {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+
+		if r.Error != "" {
+			fmt.Fprintf(tw, "%s\t-\t%s (%s)\n", r.Name, status, r.Error)
+			continue
+		}
+		for i, p := range r.PValues {
+			name := r.Name
+			if i > 0 {
+				name = ""
+			}
+			fmt.Fprintf(tw, "%s\t%.6f\t%s\n", name, p, status)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d/%d tests passed\n", report.Passed, len(report.Results))
+	return err
+}
+
+func decodeOrRandom(value string, size int, what string) ([]byte, error) {
+	if value != "" {
+		decoded, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", what, err)
+		}
+		return decoded, nil
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return nil, fmt.Errorf("failed to generate %s: %w", what, err)
+	}
+	return buf, nil
+}
+
+// makeInput returns size bytes of plaintext. The counter input numbers the
+// blocks big-endian, so consecutive blocks differ in only a few low bits.
+func makeInput(kind string, size, blockSize int) ([]byte, error) {
+	blocks := (size + blockSize - 1) / blockSize
+	data := make([]byte, blocks*blockSize)
+
+	switch kind {
+	case "zeros":
+	case "counter":
+		for i := 0; i < blocks; i++ {
+			binary.BigEndian.PutUint64(data[(i+1)*blockSize-8:], uint64(i))
+		}
+	default:
+		return nil, fmt.Errorf("unknown input %q (want zeros or counter)", kind)
+	}
+
+	return data[:size], nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func runReport(t *testing.T, args ...string) testReport {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	if err := run(append(args, "--json"), &stdout, &stderr); err != nil {
+		t.Fatalf("randtest %s: %v", strings.Join(args, " "), err)
+	}
+
+	var report testReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	return report
+}
+
+func TestKeystreamPasses(t *testing.T) {
+	report := runReport(t, "--spec", "des-CTR", "--bits", "100000",
+		"--key-hex", "0123456789abcdef", "--iv-hex", "0000000000000000")
+
+	if len(report.Results) != 8 {
+		t.Fatalf("got %d results, want 8", len(report.Results))
+	}
+	for _, r := range report.Results {
+		if !r.Passed {
+			t.Errorf("%s failed on DES-CTR keystream: %v %s", r.Name, r.PValues, r.Error)
+		}
+	}
+	if report.Spec != "des-CTR" || report.Bits != 100000 {
+		t.Errorf("report header %q, %d bits", report.Spec, report.Bits)
+	}
+}
+
+func TestECBOfZerosFails(t *testing.T) {
+	report := runReport(t, "--spec", "des-ECB", "--bits", "20000", "--key-hex", "0123456789abcdef")
+
+	// Every 128-bit block holds the same two ciphertext blocks, so only the
+	// block frequency test can miss the repetition.
+	for _, r := range report.Results {
+		if r.Passed && !strings.HasPrefix(r.Name, "BlockFrequency") {
+			t.Errorf("%s passed on a repeated ECB block: %v", r.Name, r.PValues)
+		}
+	}
+}
+
+func TestMakeInput(t *testing.T) {
+	data, err := makeInput("counter", 20, 8)
+	if err != nil {
+		t.Fatalf("makeInput: %v", err)
+	}
+	want := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0}
+	if !bytes.Equal(data, want) {
+		t.Errorf("counter input %x, want %x", data, want)
+	}
+
+	if _, err := makeInput("ones", 8, 8); err == nil {
+		t.Error("makeInput accepted an unknown input kind")
+	}
+}
+
+func TestTableOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"--spec", "des-OFB", "--bits", "1000", "--key-hex", "0123456789abcdef"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("randtest: %v", err)
+	}
+
+	out := stdout.String()
+	for _, want := range []string{"des-OFB, zeros input, 1000 bits", "Frequency", "LongestRun", "/8 tests passed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{"--bits", "0"},
+		{"--alpha", "1.5"},
+		{"--spec", "des"},
+		{"--input", "ones"},
+		{"--key-hex", "zz"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if err := run(args, &stdout, &stderr); err == nil {
+			t.Errorf("randtest %v: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"lab1/analysis"
+	"lab3/Rijndael"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "sboxreport: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("sboxreport", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	csvOutput := fs.Bool("csv", false, "emit the comparison as CSV")
+	table := fs.String("table", "", "dump the ddt or lat of the S-box given by --sbox as CSV")
+	sboxName := fs.String("sbox", "1B", "S-box for --table: des1..des8 or a Rijndael modulus low byte in hex")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *table != "" {
+		s, err := lookupSBox(*sboxName)
+		if err != nil {
+			return err
+		}
+
+		switch strings.ToLower(*table) {
+		case "ddt":
+			return analysis.WriteTableCSV(stdout, s.DDT())
+		case "lat":
+			return analysis.WriteTableCSV(stdout, s.LAT())
+		default:
+			return fmt.Errorf("unknown table %q (want ddt or lat)", *table)
+		}
+	}
+
+	var properties []analysis.SBoxProperties
+	for i := 0; i < 8; i++ {
+		s, err := analysis.NewDESSBox(i)
+		if err != nil {
+			return err
+		}
+		properties = append(properties, s.Properties(fmt.Sprintf("DES S%d", i+1)))
+	}
+
+	moduli, err := Rijndael.AnalyzeModuli()
+	if err != nil {
+		return err
+	}
+	for _, p := range moduli {
+		p.Name = "Rijndael " + p.Name
+		properties = append(properties, p)
+	}
+
+	if *csvOutput {
+		return analysis.WriteSBoxCSV(stdout, properties)
+	}
+
+	return printTable(stdout, properties)
+}
+
+func printTable(w io.Writer, properties []analysis.SBoxProperties) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "SBOX\tSIZE\tBIJECTIVE\tDIFF.UNIF\tLINEARITY\tNONLINEARITY\tDEGREE\tFIXED\tOPP.FIXED\t")
+	for _, p := range properties {
+		fmt.Fprintf(tw, "%s\t%dx%d\t%v\t%d\t%d\t%d\t%d\t%d\t%d\t\n",
+			p.Name, p.InputBits, p.OutputBits, p.Bijective, p.DifferentialUniformity,
+			p.Linearity, p.Nonlinearity, p.AlgebraicDegree, p.FixedPoints, p.OppositeFixedPoints)
+	}
+	return tw.Flush()
+}
+
+func lookupSBox(name string) (*analysis.SBox, error) {
+	if index, ok := strings.CutPrefix(strings.ToLower(name), "des"); ok {
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DES S-box %q", name)
+		}
+		return analysis.NewDESSBox(i - 1)
+	}
+
+	modulus, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(name), "0x"), 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus %q", name)
+	}
+
+	sbox, _, err := Rijndael.SBoxes(byte(modulus))
+	if err != nil {
+		return nil, err
+	}
+	return analysis.NewByteSBox(sbox)
+}
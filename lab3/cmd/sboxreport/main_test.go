@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestComparisonCSV(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"--csv"}, &stdout, &stderr); err != nil {
+		t.Fatalf("sboxreport: %v", err)
+	}
+
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV: %v", err)
+	}
+	if len(records) != 1+8+30 {
+		t.Fatalf("got %d rows, want 39", len(records))
+	}
+	if records[5][0] != "DES S5" || records[5][6] != "12" {
+		t.Errorf("DES S5 row %v", records[5])
+	}
+	if records[9][0] != "Rijndael 0x11B" || records[9][4] != "4" || records[9][6] != "112" {
+		t.Errorf("AES row %v", records[9])
+	}
+}
+
+func TestTableDump(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"--table", "ddt", "--sbox", "des1"}, &stdout, &stderr); err != nil {
+		t.Fatalf("sboxreport: %v", err)
+	}
+
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV: %v", err)
+	}
+	if len(records) != 65 || len(records[0]) != 17 || records[1][1] != "64" {
+		t.Errorf("DES S1 DDT is %dx%d, DDT[0][0] %s", len(records), len(records[0]), records[1][1])
+	}
+
+	for _, args := range [][]string{
+		{"--table", "xyz"},
+		{"--table", "lat", "--sbox", "des9"},
+		{"--table", "lat", "--sbox", "00"},
+		{"--table", "lat", "--sbox", "zz"},
+	} {
+		if err := run(args, &stdout, &stderr); err == nil {
+			t.Errorf("sboxreport %v: expected error", args)
+		}
+	}
+}
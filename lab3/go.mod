@@ -4,6 +4,7 @@ go 1.25.1
 
 require (
     lab1 v0.0.0
+    gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace lab1 => ../lab1
\ No newline at end of file